@@ -4,18 +4,12 @@ import (
 	"fmt"
 	"os"
 
-	"aliyun-tui-viewer/internal/app"
+	"aliyun-tui-viewer/internal/cli"
 )
 
 func main() {
-	application, err := app.New()
-	if err != nil {
-		fmt.Printf("Error initializing application: %v\n", err)
-		os.Exit(1)
-	}
-
-	if err := application.Run(); err != nil {
-		fmt.Printf("Error running application: %v\n", err)
+	if err := cli.Run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
@@ -39,6 +41,7 @@ type App struct {
 	slbInstanceTable                   *tview.Table
 	slbDetailView                      *tview.TextView
 	slbListenersTable                  *tview.Table
+	slbListenerDetailTable             *tview.Table
 	slbVServerGroupsTable              *tview.Table
 	slbVServerGroupBackendServersTable *tview.Table
 	ossBucketTable                     *tview.Table
@@ -48,6 +51,7 @@ type App struct {
 	rdsDetailView                      *tview.TextView
 	rdsDatabaseTable                   *tview.Table
 	rdsAccountTable                    *tview.Table
+	rdsInstancePageTable               *tview.Table // Table of rdsInstancePage, see showRdsInstancePage
 	redisInstanceTable                 *tview.Table
 	redisAccountTable                  *tview.Table
 	rocketmqInstanceTable              *tview.Table
@@ -71,19 +75,93 @@ type App struct {
 	allRocketMQInstances      []service.RocketMQInstance
 	allOssBuckets             []oss.BucketProperties
 	currentBucketName         string
+	currentDomainName         string
+	currentDnsRecords         []alidns.Record
+	currentSecurityGroupRules *ecs.DescribeSecurityGroupAttributeResponse
 	currentRdsInstanceId      string
+	rdsInstancePage           string // The RDS instance page the item detail returns to
 	currentRedisInstanceId    string
 	currentRocketMQInstanceId string
 
 	// OSS pagination state
+	ossCurrentPrefix   string // Prefix the object list is limited to, empty for the whole bucket
 	ossCurrentMarker   string
 	ossPreviousMarkers []string // Stack to track previous markers for backward navigation
 	ossCurrentPage     int
 	ossPageSize        int
 	ossHasNextPage     bool
+	ossCurrentObjects  []oss.ObjectProperties // Objects of the page shown
+	ossFolderStack     []ossFolderState       // Listings of the parent folders, innermost last
+	ossSelectKey       string                 // Row to select once the page is shown, see leaveOssFolder
+
+	// OSS search state, see startOssSearch
+	ossSearchCancel  context.CancelFunc // Stops the search in progress
+	ossSearchTable   *tview.Table
+	ossSearchResults []oss.ObjectProperties
+	ossSearchValues  []string // Fields of the last search dialog, offered again
+	ossSearchBucket  string   // Bucket of ossSearchValues
+
+	// OSS usage pages, see showOssUsage
+	ossUsageStack       []*ossUsageView // The folders drilled into, innermost last
+	ossUsageReturnPage  string
+	ossUsageReturnFocus tview.Primitive
+
+	// OSS versions page, see showOssVersions
+	ossVersionsTable    *tview.Table
+	ossVersions         []service.ObjectVersion
+	ossVersionsRestored bool // The object list is out of date
+
+	// Security group audit page, see showSecurityGroupAudit
+	sgAuditTable    *tview.Table
+	sgAuditFindings []service.SecurityGroupFinding
+	sgAuditSortBy   string // One of service.FindingSortKeys
+	sgAuditReverse  bool
+
+	// Reachability page, see showReachability
+	reachTable  *tview.Table
+	reachResult *service.Reachability
+	reachValues []string // Fields of the last reachability dialog, offered again
+
+	// Security group diff page, see showSecurityGroupDiff
+	sgDiffTable    *tview.Table
+	sgDiffLeft     *service.SecurityGroupRuleSet
+	sgDiffRight    *service.SecurityGroupRuleSet
+	sgDiffLines    []service.SecurityGroupRuleDiff
+	sgDiffHideSame bool                     // Leave out the rules both groups have
+	sgDiffTarget   service.SecurityGroupRef // Group of the last compare dialog, offered again
+
+	// The page the rules or instances of a security group go back to, when they were
+	// opened from the audit or the reachability page instead of the security group list
+	sgReturnPage  string
+	sgReturnFocus tview.Primitive
 
 	// Configuration
 	currentProfile string
+	forceReadOnly  bool // --read-only was given, so every profile is read-only
+	production     bool // The profile is a production profile, see confirmDestructive
+
+	// Region state
+	currentRegion   string
+	allRegionsMode  bool                   // List views fan out to every region
+	allRegions      []ecs.Region           // Regions from DescribeRegions, loaded on first use
+	regionPool      *regionPool            // Clients of the other regions, see regionalContext
+	profilePools    map[string]*regionPool // Clients of the other profiles, see securityGroupServices
+	resourceRegions map[string]string      // Resource ID -> region, filled in the all-regions mode
+	regionWarnings  []string               // Regions that failed during the last all-regions fetch
+
+	// Refresh state, see setupTableRefresh
+	refreshTargets  map[string]*refreshTarget // List page -> how to refresh it
+	pendingRefresh  *pendingRefresh           // Refresh whose result has not been rendered yet
+	refreshInterval time.Duration             // Auto-refresh interval, zero when disabled
+
+	// On-disk cache state, see restoreSnapshot
+	cacheEnabled  bool
+	cacheTTL      time.Duration
+	snapshotTimes map[string]time.Time // List page -> when the cached data it shows was saved
+
+	// Background loading state, see loadAsync
+	loadCancel   context.CancelFunc // Cancels the load in flight, nil when idle
+	loadModeLine string             // Mode line text to restore when the load ends
 
 	// Interaction state
 	yankTracker       *ui.YankTracker
@@ -92,53 +170,80 @@ type App struct {
 
 // Services holds all service instances
 type Services struct {
-	ECS      *service.ECSService
-	DNS      *service.DNSService
-	SLB      *service.SLBService
-	RDS      *service.RDSService
-	OSS      *service.OSSService
-	Redis    *service.RedisService
-	RocketMQ *service.RocketMQService
+	ECS      service.ECS
+	DNS      service.DNS
+	SLB      service.SLB
+	RDS      service.RDS
+	OSS      service.OSS
+	Redis    service.Redis
+	RocketMQ service.RocketMQ
+
+	// Writes decides whether write operations may be sent; nil allows them
+	Writes *service.WriteGuard
+}
+
+// NewServices creates SDK-backed services from the given clients. Their write operations
+// are refused if the configuration is read-only.
+func NewServices(clients *client.AliyunClients, cfg *config.Config) *Services {
+	writes := service.NewWriteGuard(cfg.ReadOnly, cfg.ReadOnlyReason)
+	return &Services{
+		ECS:      service.GuardECS(service.NewECSService(clients.ECS), writes),
+		DNS:      service.GuardDNS(service.NewDNSService(clients.DNS), writes),
+		SLB:      service.NewSLBService(clients.SLB),
+		RDS:      service.NewRDSService(clients.RDS),
+		OSS:      service.GuardOSS(service.NewOSSServiceWithCredentials(clients.OSS, clients.OSSCredentials(), cfg.OssEndpoint, cfg.OSSBucketEndpoint), writes),
+		Redis:    service.NewRedisService(clients.Redis),
+		RocketMQ: service.NewRocketMQService(clients.RocketMQ),
+		Writes:   writes,
+	}
+}
+
+// Options holds the command line overrides for the application
+type Options struct {
+	Profile  string        // Profile to use instead of the current one
+	Region   string        // Region to use instead of the profile's region_id
+	Watch    time.Duration // Auto-refresh interval instead of the configured refresh_interval
+	ReadOnly bool          // Refuse every write operation, whatever the profile allows
 }
 
 // New creates a new application instance
-func New() (*App, error) {
+func New(opts Options) (*App, error) {
 	// Load configuration
-	cfg, err := config.LoadAliyunConfig()
+	cfg, err := config.LoadProfileConfig(opts.Profile, opts.Region)
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
-
-	// Get current profile name
-	currentProfile, err := config.GetCurrentProfileName()
-	if err != nil {
-		return nil, fmt.Errorf("getting current profile: %w", err)
+	if opts.ReadOnly {
+		cfg.SetReadOnly()
 	}
 
 	// Create clients
-	clientConfig := &client.Config{
-		AccessKeyID:     cfg.AccessKeyID,
-		AccessKeySecret: cfg.AccessKeySecret,
-		RegionID:        cfg.RegionID,
-		OssEndpoint:     cfg.OssEndpoint,
-	}
-
-	clients, err := client.NewAliyunClients(clientConfig)
+	clients, err := client.NewAliyunClients(client.NewConfig(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("creating clients: %w", err)
 	}
 
-	// Create services
-	services := &Services{
-		ECS:      service.NewECSService(clients.ECS),
-		DNS:      service.NewDNSService(clients.DNS),
-		SLB:      service.NewSLBService(clients.SLB),
-		RDS:      service.NewRDSService(clients.RDS),
-		OSS:      service.NewOSSServiceWithCredentials(clients.OSS, cfg.AccessKeyID, cfg.AccessKeySecret, cfg.OssEndpoint),
-		Redis:    service.NewRedisService(clients.Redis),
-		RocketMQ: service.NewRocketMQService(clients.RocketMQ),
+	app := NewWithServices(NewServices(clients, cfg), cfg.Profile)
+	app.clients = clients
+	app.currentRegion = cfg.RegionID
+	app.forceReadOnly = opts.ReadOnly
+	app.production = cfg.Production
+	app.regionPool = newRegionPool(cfg.Profile, opts.ReadOnly)
+
+	refreshInterval := cfg.RefreshInterval
+	if opts.Watch > 0 {
+		refreshInterval = opts.Watch
 	}
+	app.SetRefreshInterval(refreshInterval)
+	app.SetCacheTTL(cfg.CacheTTL)
 
+	return app, nil
+}
+
+// NewWithServices creates an application instance on top of the given services.
+// It does not read any configuration or create SDK clients, so it can be driven
+// by the in-memory implementations from the service/fake package.
+func NewWithServices(services *Services, profileName string) *App {
 	// Create tview app and pages
 	tviewApp := tview.NewApplication()
 	pages := tview.NewPages()
@@ -147,9 +252,11 @@ func New() (*App, error) {
 	app := &App{
 		tviewApp:       tviewApp,
 		pages:          pages,
-		clients:        clients,
 		services:       services,
-		currentProfile: currentProfile,
+		currentProfile: profileName,
+		regionPool:     newRegionPool(profileName, false),
+		refreshTargets: make(map[string]*refreshTarget),
+		snapshotTimes:  make(map[string]time.Time),
 		yankTracker:    ui.NewYankTracker(),
 
 		// Search handlers will be initialized when creating views
@@ -158,11 +265,15 @@ func New() (*App, error) {
 	// Initialize UI
 	app.initializeUI()
 
-	return app, nil
+	return app
 }
 
 // Run starts the application
 func (a *App) Run() error {
+	if a.refreshInterval > 0 {
+		stop := a.startAutoRefresh()
+		defer stop()
+	}
 	return a.tviewApp.EnableMouse(true).Run()
 }
 
@@ -174,7 +285,7 @@ func (a *App) Stop() {
 // initializeUI initializes the user interface
 func (a *App) initializeUI() {
 	// Create mode line
-	a.modeLine = ui.CreateModeLine(a.currentProfile)
+	a.modeLine = ui.CreateModeLine(a.modeLineContext())
 
 	// Create main menu
 	a.mainMenu = ui.CreateMainMenu(
@@ -225,7 +336,7 @@ func (a *App) initializeUI() {
 	a.tviewApp.SetRoot(a.mainLayout, true)
 
 	// Initialize mode line with main menu shortcuts
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageMainMenu)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageMainMenu)
 
 	// Set up global input capture
 	a.setupGlobalInputCapture()
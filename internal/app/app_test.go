@@ -0,0 +1,136 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service/fake"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// newTestApp runs an app on a simulated screen with fake services seeded from the sample
+// fixtures
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	cloud, err := fake.LoadCloud("../service/fake/fixtures/sample.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := &Services{
+		ECS:      fake.NewECSService(cloud),
+		DNS:      fake.NewDNSService(cloud),
+		SLB:      fake.NewSLBService(cloud),
+		RDS:      fake.NewRDSService(cloud),
+		OSS:      fake.NewOSSService(cloud),
+		Redis:    fake.NewRedisService(cloud),
+		RocketMQ: fake.NewRocketMQService(cloud),
+	}
+	a := NewWithServices(services, "test")
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	a.tviewApp.SetScreen(screen)
+	done := make(chan error, 1)
+	go func() { done <- a.Run() }()
+	t.Cleanup(func() {
+		a.Stop()
+		<-done
+	})
+	return a
+}
+
+// onUI runs f on the UI goroutine and waits for it
+func onUI(a *App, f func()) {
+	done := make(chan struct{})
+	a.tviewApp.QueueUpdate(func() {
+		f()
+		close(done)
+	})
+	<-done
+}
+
+// waitForPage waits until page is in front, as loads render asynchronously
+func waitForPage(t *testing.T, a *App, page string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var front string
+		onUI(a, func() { front, _ = a.pages.GetFrontPage() })
+		if front == page {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("page %q is in front, want %q", front, page)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestListViews(t *testing.T) {
+	tests := []struct {
+		name  string
+		open  func(a *App)
+		page  string
+		table func(a *App) *tview.Table
+		rows  int
+	}{
+		{"ECS instances", (*App).switchToEcsListView, ui.PageEcsList, func(a *App) *tview.Table { return a.ecsInstanceTable }, 2},
+		{"security groups", (*App).switchToSecurityGroupsListView, ui.PageSecurityGroups, func(a *App) *tview.Table { return a.securityGroupTable }, 4},
+		{"DNS domains", (*App).switchToDnsDomainsListView, ui.PageDnsDomains, func(a *App) *tview.Table { return a.dnsDomainsTable }, 1},
+		{"load balancers", (*App).switchToSlbListView, ui.PageSlbList, func(a *App) *tview.Table { return a.slbInstanceTable }, 1},
+		{"OSS buckets", (*App).switchToOssBucketListView, ui.PageOssBuckets, func(a *App) *tview.Table { return a.ossBucketTable }, 1},
+		{"RDS instances", (*App).switchToRdsListView, ui.PageRdsList, func(a *App) *tview.Table { return a.rdsInstanceTable }, 2},
+		{"Redis instances", (*App).switchToRedisListView, ui.PageRedisList, func(a *App) *tview.Table { return a.redisInstanceTable }, 1},
+		{"RocketMQ instances", (*App).switchToRocketMQListView, ui.PageRocketMQList, func(a *App) *tview.Table { return a.rocketmqInstanceTable }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			onUI(a, func() { tt.open(a) })
+			waitForPage(t, a, tt.page)
+
+			var rows int
+			onUI(a, func() { rows = tt.table(a).GetRowCount() - 1 }) // Without the header
+			if rows != tt.rows {
+				t.Errorf("%d rows, want %d", rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestOssFolders(t *testing.T) {
+	a := newTestApp(t)
+	onUI(a, func() { a.switchToOssObjectListView("demo-logs") })
+	waitForPage(t, a, ui.PageOssObjects)
+
+	steps := []struct {
+		name   string
+		move   func(a *App)
+		prefix string
+		rows   int
+	}{
+		{"enter", func(a *App) { a.enterOssFolder("app/") }, "app/", 2},
+		{"leave", func(a *App) { a.leaveOssFolder() }, "", 1},
+	}
+	for _, step := range steps {
+		onUI(a, func() { step.move(a) })
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			var prefix string
+			var rows int
+			onUI(a, func() { prefix, rows = a.ossCurrentPrefix, a.ossObjectTable.GetRowCount()-1 })
+			if prefix == step.prefix && rows == step.rows {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: prefix %q with %d rows, want %q with %d", step.name, prefix, rows, step.prefix, step.rows)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// defaultRecordTTL is the TTL offered for new records, the smallest one every AliDNS
+// edition accepts
+const defaultRecordTTL = 600
+
+// setupDnsRecordKeyHandlers sets up key handlers for editing the records of a domain
+func (a *App) setupDnsRecordKeyHandlers(table *tview.Table) {
+	originalInputCapture := table.GetInputCapture()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'A': // Add a record
+			a.showAddDnsRecordForm(table)
+			return nil
+		case 'E': // Edit the selected record
+			a.showEditDnsRecordForm(table)
+			return nil
+		case 'D': // Delete the selected record
+			a.confirmDeleteDnsRecord(table)
+			return nil
+		case 'T': // Enable or disable the selected record
+			a.confirmToggleDnsRecord(table)
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// selectedDnsRecord returns the record of the selected row of the DNS records list
+func (a *App) selectedDnsRecord(table *tview.Table) (alidns.Record, bool) {
+	recordId, ok := ui.SelectedReference(table)
+	if !ok {
+		return alidns.Record{}, false
+	}
+	for _, record := range a.currentDnsRecords {
+		if record.RecordId == recordId {
+			return record, true
+		}
+	}
+	return alidns.Record{}, false
+}
+
+// describeRecord names a record in confirmation messages, e.g. "www.example.com A 192.0.2.1"
+func describeRecord(domainName string, spec service.DomainRecordSpec) string {
+	return fmt.Sprintf("%s %s %s", recordFQDN(domainName, spec.RR), spec.Type, spec.Value)
+}
+
+// recordFQDN returns the name a host record resolves, e.g. "www.example.com"
+func recordFQDN(domainName, rr string) string {
+	if rr == "@" {
+		return domainName
+	}
+	return rr + "." + domainName
+}
+
+// showAddDnsRecordForm asks for a new record of the current domain
+func (a *App) showAddDnsRecordForm(table *tview.Table) {
+	if !a.checkWritable() {
+		return
+	}
+	domainName := a.currentDomainName
+	spec := service.DomainRecordSpec{Type: "A", TTL: defaultRecordTTL, Line: service.DefaultDomainRecordLine}
+
+	ui.ShowDnsRecordForm(a.pages, a.tviewApp, fmt.Sprintf("Add record to %s", domainName), spec,
+		func(entered service.DomainRecordSpec) {
+			services := a.services
+			a.runWrite(fmt.Sprintf("add DNS record %s", describeRecord(domainName, entered)),
+				func(ctx context.Context) error {
+					_, err := services.DNS.AddDomainRecord(ctx, domainName, entered)
+					return err
+				},
+				func() { a.refreshDnsRecords(domainName) })
+			a.tviewApp.SetFocus(table)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// showEditDnsRecordForm edits the selected record, confirming the change before it is sent
+func (a *App) showEditDnsRecordForm(table *tview.Table) {
+	record, ok := a.selectedDnsRecord(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	domainName := a.currentDomainName
+	current := service.DomainRecordSpecOf(record)
+
+	ui.ShowDnsRecordForm(a.pages, a.tviewApp, fmt.Sprintf("Edit record %s", record.RecordId), current,
+		func(entered service.DomainRecordSpec) {
+			if entered == current {
+				a.tviewApp.SetFocus(table)
+				return
+			}
+			message := fmt.Sprintf("Change DNS record %s?\n\nFrom: %s (TTL %d, line %s)\nTo:   %s (TTL %d, line %s)",
+				record.RecordId,
+				describeRecord(domainName, current), current.TTL, current.Line,
+				describeRecord(domainName, entered), entered.TTL, entered.Line)
+			ui.ShowConfirmModal(a.pages, a.tviewApp, message, []string{"Change"},
+				func(string) {
+					a.tviewApp.SetFocus(table)
+					services := a.services
+					a.runWrite(fmt.Sprintf("update DNS record %s", record.RecordId),
+						func(ctx context.Context) error {
+							return services.DNS.UpdateDomainRecord(ctx, record.RecordId, entered)
+						},
+						func() { a.refreshDnsRecords(domainName) })
+				},
+				func() { a.tviewApp.SetFocus(table) })
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// confirmDeleteDnsRecord asks to delete the selected record, a destructive action
+func (a *App) confirmDeleteDnsRecord(table *tview.Table) {
+	record, ok := a.selectedDnsRecord(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	domainName := a.currentDomainName
+	message := fmt.Sprintf("Delete DNS record %s?\n\n%s", record.RecordId, describeRecord(domainName, service.DomainRecordSpecOf(record)))
+	a.confirmDestructive(table, message, record.RecordId, []string{"Delete"}, func(string) {
+		services := a.services
+		a.runWrite(fmt.Sprintf("delete DNS record %s", record.RecordId),
+			func(ctx context.Context) error { return services.DNS.DeleteDomainRecord(ctx, record.RecordId) },
+			func() { a.refreshDnsRecords(domainName) })
+	})
+}
+
+// confirmToggleDnsRecord asks to disable the selected record if it is enabled, or to enable
+// it otherwise. Disabling stops the record from resolving and is confirmed like a
+// destructive action.
+func (a *App) confirmToggleDnsRecord(table *tview.Table) {
+	record, ok := a.selectedDnsRecord(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	domainName := a.currentDomainName
+	enable := !strings.EqualFold(record.Status, service.DomainRecordEnabled)
+	setStatus := func(string) {
+		services := a.services
+		a.runWrite(fmt.Sprintf("set status of DNS record %s", record.RecordId),
+			func(ctx context.Context) error {
+				return services.DNS.SetDomainRecordStatus(ctx, record.RecordId, enable)
+			},
+			func() { a.refreshDnsRecords(domainName) })
+	}
+
+	described := describeRecord(domainName, service.DomainRecordSpecOf(record))
+	if enable {
+		ui.ShowConfirmModal(a.pages, a.tviewApp, fmt.Sprintf("Enable DNS record %s?\n\n%s", record.RecordId, described), []string{"Enable"},
+			func(action string) {
+				a.tviewApp.SetFocus(table)
+				setStatus(action)
+			},
+			func() { a.tviewApp.SetFocus(table) })
+		return
+	}
+	message := fmt.Sprintf("Disable DNS record %s?\n\n%s\n\nThe record stops resolving until it is enabled again.", record.RecordId, described)
+	a.confirmDestructive(table, message, record.RecordId, []string{"Disable"}, setStatus)
+}
+
+// refreshDnsRecords refreshes the records list after a write, unless the user has moved on
+// to another page or domain meanwhile
+func (a *App) refreshDnsRecords(domainName string) {
+	if page, _ := a.pages.GetFrontPage(); page != ui.PageDnsRecords || a.currentDomainName != domainName {
+		return
+	}
+	a.refreshPage(ui.PageDnsRecords)
+}
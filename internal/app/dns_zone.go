@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// setupDnsDomainKeyHandlers sets up key handlers for exporting and importing the zone of a domain
+func (a *App) setupDnsDomainKeyHandlers(table *tview.Table) {
+	originalInputCapture := table.GetInputCapture()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'X': // Export the records of the selected domain to a zone file
+			a.showExportZoneDialog(table)
+			return nil
+		case 'I': // Preview importing a zone file into the selected domain
+			a.showImportZoneDialog(table)
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// showExportZoneDialog asks for the file to export the selected domain to. Files ending in
+// .yaml or .yml get YAML, anything else a BIND zone file.
+func (a *App) showExportZoneDialog(table *tview.Table) {
+	domainName, ok := ui.SelectedReference(table)
+	if !ok {
+		return
+	}
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Export zone %s (.yaml for YAML)", domainName),
+		[]string{"File"}, []string{domainName + ".zone"},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			path := strings.TrimSpace(values[0])
+			if path == "" {
+				return
+			}
+			services := a.services
+			loadAsync(a, fmt.Sprintf("zone of %s", domainName), func(ctx context.Context) (int, error) {
+				records, err := services.DNS.FetchDomainRecords(ctx, domainName)
+				if err != nil {
+					return 0, err
+				}
+				zone := service.ZoneFromRecords(domainName, records)
+				data, err := service.FormatZone(zone, service.ZoneFormatForPath(path))
+				if err != nil {
+					return 0, err
+				}
+				if err := os.WriteFile(path, data, 0o644); err != nil {
+					return 0, fmt.Errorf("writing zone file: %w", err)
+				}
+				return len(zone.Records), nil
+			}, func(count int) {
+				ui.ShowMessageModal(a.pages, a.tviewApp, fmt.Sprintf("Exported %d records of %s to %s", count, domainName, path),
+					func() { a.tviewApp.SetFocus(table) })
+			})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// showImportZoneDialog asks for the zone file to import into the selected domain, then
+// previews the changes against the live records
+func (a *App) showImportZoneDialog(table *tview.Table) {
+	domainName, ok := ui.SelectedReference(table)
+	if !ok {
+		return
+	}
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Import zone file into %s", domainName),
+		[]string{"File"}, []string{domainName + ".zone"},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			path := strings.TrimSpace(values[0])
+			if path == "" {
+				return
+			}
+			services := a.services
+			loadAsync(a, fmt.Sprintf("zone import for %s", domainName), func(ctx context.Context) (*service.ZoneDiff, error) {
+				zone, err := service.ReadZoneFile(path, domainName)
+				if err != nil {
+					return nil, err
+				}
+				records, err := services.DNS.FetchDomainRecords(ctx, domainName)
+				if err != nil {
+					return nil, err
+				}
+				return service.DiffZone(domainName, records, zone.Records), nil
+			}, a.showDnsZoneImportView)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// showDnsZoneImportView shows the changes of a zone import; nothing is sent until they are applied
+func (a *App) showDnsZoneImportView(diff *service.ZoneDiff) {
+	table := ui.CreateDnsZoneImportView(diff)
+	ui.SetupTableNavigationWithSearch(table, a, nil)
+
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'A' { // Apply the changes
+			a.confirmApplyZoneDiff(table, diff)
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	a.pages.AddPage(ui.PageDnsZoneImport, ui.WrapTableInFlex(table), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageDnsZoneImport)
+	a.tviewApp.SetFocus(table)
+}
+
+// confirmApplyZoneDiff asks to apply the changes of a zone import. An import that deletes
+// records is confirmed like a destructive action.
+func (a *App) confirmApplyZoneDiff(table *tview.Table, diff *service.ZoneDiff) {
+	if len(diff.Changes) == 0 || !a.checkWritable() {
+		return
+	}
+
+	counts := map[string]int{}
+	for _, change := range diff.Changes {
+		counts[change.Action]++
+	}
+	message := fmt.Sprintf("Apply %d changes to %s?\n\n%d to add, %d to update, %d to delete",
+		len(diff.Changes), diff.Domain,
+		counts[service.ZoneChangeAdd], counts[service.ZoneChangeUpdate], counts[service.ZoneChangeDelete])
+
+	apply := func(string) {
+		services := a.services
+		a.runWrite(fmt.Sprintf("import zone into %s", diff.Domain),
+			func(ctx context.Context) error {
+				applied, err := service.ApplyZoneDiff(ctx, services.DNS, diff)
+				if err != nil {
+					return fmt.Errorf("%d of %d changes applied: %w", applied, len(diff.Changes), err)
+				}
+				return nil
+			},
+			func() { a.switchToDnsRecordsListView(diff.Domain) })
+	}
+
+	if counts[service.ZoneChangeDelete] > 0 {
+		a.confirmDestructive(table, message, diff.Domain, []string{"Apply"}, apply)
+		return
+	}
+	ui.ShowConfirmModal(a.pages, a.tviewApp, message, []string{"Apply"},
+		func(action string) {
+			a.tviewApp.SetFocus(table)
+			apply(action)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
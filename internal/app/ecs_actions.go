@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/ui"
+)
+
+const (
+	// instancePollInterval is how often the status of an instance is checked while an
+	// action on it completes
+	instancePollInterval = 2 * time.Second
+	// instancePollTimeout gives up following an action that takes longer than this
+	instancePollTimeout = 5 * time.Minute
+)
+
+// instanceAction is an action that changes the status of an ECS instance
+type instanceAction struct {
+	name      string // Used in error messages, e.g. "stop"
+	verb      string // Shown while the action completes, e.g. "Stopping"
+	doneState string // Status of the instance once the action has completed
+	// The instance starts in doneState, so it counts as done only after leaving it, e.g.
+	// Running → Starting → Running for a reboot
+	leavesDoneState bool
+	run             func(ctx context.Context, s *Services, instanceId string) error
+}
+
+// selectedEcsInstance returns the instance of the selected row of the ECS list
+func (a *App) selectedEcsInstance(table *tview.Table) (ecs.Instance, bool) {
+	instanceId, ok := ui.SelectedReference(table)
+	if !ok {
+		return ecs.Instance{}, false
+	}
+	for _, inst := range a.allECSInstances {
+		if inst.InstanceId == instanceId {
+			return inst, true
+		}
+	}
+	return ecs.Instance{}, false
+}
+
+// describeInstance names an instance in confirmation messages
+func describeInstance(inst ecs.Instance) string {
+	if inst.InstanceName == "" {
+		return inst.InstanceId
+	}
+	return fmt.Sprintf("%s (%s)", inst.InstanceId, inst.InstanceName)
+}
+
+// confirmStartInstance asks to start the selected instance
+func (a *App) confirmStartInstance(table *tview.Table) {
+	inst, ok := a.selectedEcsInstance(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	message := fmt.Sprintf("Start ECS instance %s?", describeInstance(inst))
+	a.confirmInstanceAction(table, message, []string{"Start"}, func(string) {
+		a.runInstanceAction(inst, instanceAction{
+			name:      "start",
+			verb:      "Starting",
+			doneState: "Running",
+			run: func(ctx context.Context, s *Services, instanceId string) error {
+				return s.ECS.StartInstance(ctx, instanceId)
+			},
+		})
+	})
+}
+
+// confirmStopInstance asks to stop the selected instance, optionally forcibly. Stopping
+// interrupts service, so it is confirmed like any destructive action.
+func (a *App) confirmStopInstance(table *tview.Table) {
+	inst, ok := a.selectedEcsInstance(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	message := fmt.Sprintf("Stop ECS instance %s?\n\nForce Stop is like cutting the power: data not yet written to disk may be lost.", describeInstance(inst))
+	a.confirmDestructive(table, message, inst.InstanceId, []string{"Stop", "Force Stop"}, func(action string) {
+		force := action == "Force Stop"
+		a.runInstanceAction(inst, instanceAction{
+			name:      "stop",
+			verb:      "Stopping",
+			doneState: "Stopped",
+			run: func(ctx context.Context, s *Services, instanceId string) error {
+				return s.ECS.StopInstance(ctx, instanceId, force)
+			},
+		})
+	})
+}
+
+// confirmRebootInstance asks to reboot the selected instance, a destructive action
+func (a *App) confirmRebootInstance(table *tview.Table) {
+	inst, ok := a.selectedEcsInstance(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	message := fmt.Sprintf("Reboot ECS instance %s?", describeInstance(inst))
+	a.confirmDestructive(table, message, inst.InstanceId, []string{"Reboot"}, func(string) {
+		a.runInstanceAction(inst, instanceAction{
+			name:            "reboot",
+			verb:            "Rebooting",
+			doneState:       "Running",
+			leavesDoneState: true,
+			run: func(ctx context.Context, s *Services, instanceId string) error {
+				return s.ECS.RebootInstance(ctx, instanceId)
+			},
+		})
+	})
+}
+
+// showModifyInstanceDialog edits the name and description of the selected instance
+func (a *App) showModifyInstanceDialog(table *tview.Table) {
+	inst, ok := a.selectedEcsInstance(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Modify %s", inst.InstanceId),
+		[]string{"Name", "Description"},
+		[]string{inst.InstanceName, inst.Description},
+		func(values []string) {
+			name, description := values[0], values[1]
+			message := fmt.Sprintf("Modify ECS instance %s?\n\nName: %s\nDescription: %s", describeInstance(inst), name, description)
+			a.confirmInstanceAction(table, message, []string{"Modify"}, func(string) {
+				a.modifyInstance(inst, name, description)
+			})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// confirmInstanceAction shows a confirmation modal and returns the focus to the ECS list
+// once it is dismissed
+func (a *App) confirmInstanceAction(table *tview.Table, message string, actions []string, onConfirm func(action string)) {
+	ui.ShowConfirmModal(a.pages, a.tviewApp, message, actions,
+		func(action string) {
+			a.tviewApp.SetFocus(table)
+			onConfirm(action)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// runInstanceAction submits an action in the background. The status cell of the instance
+// shows the action until the instance reaches the status the action leads to.
+func (a *App) runInstanceAction(inst ecs.Instance, action instanceAction) {
+	services := a.servicesFor(inst.InstanceId)
+	profile := a.currentProfile
+	a.setEcsInstanceCell(inst.InstanceId, "Status", action.verb+"…", tcell.ColorYellow)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), instancePollTimeout)
+		defer cancel()
+
+		if err := action.run(ctx, services, inst.InstanceId); err != nil {
+			a.tviewApp.QueueUpdateDraw(func() {
+				a.setEcsInstanceCell(inst.InstanceId, "Status", inst.Status, tcell.ColorWhite)
+				a.showErrorModal(fmt.Sprintf("Failed to %s instance %s: %v", action.name, inst.InstanceId, err))
+			})
+			return
+		}
+		a.followInstance(ctx, services, profile, inst.InstanceId, action.doneState, action.leavesDoneState)
+	}()
+}
+
+// modifyInstance changes the name and description of an instance in the background
+func (a *App) modifyInstance(inst ecs.Instance, name, description string) {
+	services := a.servicesFor(inst.InstanceId)
+	profile := a.currentProfile
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), instancePollTimeout)
+		defer cancel()
+
+		if err := services.ECS.ModifyInstanceAttribute(ctx, inst.InstanceId, name, description); err != nil {
+			a.tviewApp.QueueUpdateDraw(func() {
+				a.showErrorModal(fmt.Sprintf("Failed to modify instance: %v", err))
+			})
+			return
+		}
+		a.followInstance(ctx, services, profile, inst.InstanceId, "", false)
+	}()
+}
+
+// followInstance polls an instance until it reaches doneState, updating its row after each
+// poll. An empty doneState updates the row once. It runs outside the UI goroutine.
+func (a *App) followInstance(ctx context.Context, services *Services, profile, instanceId, doneState string, leavesDoneState bool) {
+	// A quick reboot may never be caught outside Running, so stop waiting for it after a
+	// few polls
+	const maxPollsInDoneState = 3
+	left, pollsInDoneState := !leavesDoneState, 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(instancePollInterval):
+		}
+
+		inst, err := services.ECS.FetchInstance(ctx, instanceId)
+		if err != nil {
+			a.tviewApp.QueueUpdateDraw(func() {
+				a.showErrorModal(fmt.Sprintf("Failed to refresh instance %s: %v", instanceId, err))
+			})
+			return
+		}
+
+		if inst.Status != doneState {
+			left = true
+		} else {
+			pollsInDoneState++
+		}
+		done := doneState == "" || (inst.Status == doneState && (left || pollsInDoneState >= maxPollsInDoneState))
+		a.tviewApp.QueueUpdateDraw(func() {
+			// The list belongs to another account after switching profiles
+			if a.currentProfile != profile {
+				return
+			}
+			a.updateEcsInstance(*inst, done)
+		})
+		if done {
+			return
+		}
+	}
+}
+
+// updateEcsInstance replaces an instance in the ECS list with a freshly fetched copy
+func (a *App) updateEcsInstance(inst ecs.Instance, done bool) {
+	for i := range a.allECSInstances {
+		if a.allECSInstances[i].InstanceId == inst.InstanceId {
+			a.allECSInstances[i] = inst
+			break
+		}
+	}
+
+	color := tcell.ColorYellow
+	if done {
+		color = tcell.ColorWhite
+	}
+	a.setEcsInstanceCell(inst.InstanceId, "Status", inst.Status, color)
+	a.setEcsInstanceCell(inst.InstanceId, "Name", inst.InstanceName, tcell.ColorWhite)
+}
+
+// setEcsInstanceCell sets a cell of an instance's row in the ECS list. Columns are found
+// by header, since the all-regions mode inserts a Region column.
+func (a *App) setEcsInstanceCell(instanceId, header, text string, color tcell.Color) {
+	table := a.ecsInstanceTable
+	if table == nil {
+		return
+	}
+	col := -1
+	for c := 0; c < table.GetColumnCount(); c++ {
+		if cell := table.GetCell(0, c); cell != nil && cell.Text == header {
+			col = c
+			break
+		}
+	}
+	if col < 0 {
+		return
+	}
+	for row := 1; row < table.GetRowCount(); row++ {
+		if ref, ok := table.GetCell(row, 0).GetReference().(string); ok && ref == instanceId {
+			table.GetCell(row, col).SetText(text).SetTextColor(color)
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// spinnerInterval is the time between two frames of the loading indicator
+const spinnerInterval = 100 * time.Millisecond
+
+// loadAsync runs fetch on a background goroutine while the mode line shows a spinner, so
+// the UI stays responsive during slow API calls. When fetch succeeds, render is called on
+// the UI goroutine; errors are shown in a modal. Only one load runs at a time: starting
+// another one, navigating away or pressing Esc cancels it and its result is dropped; a
+// dropped result that is an io.Closer, such as an open object, is closed. The context of
+// fetch stays valid until render returns, so render may read what fetch opened with it.
+func loadAsync[T any](a *App, label string, fetch func(ctx context.Context) (T, error), render func(T)) {
+	a.cancelLoad()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.loadCancel = cancel
+	a.loadModeLine = a.modeLine.GetText(false)
+	ui.UpdateModeLineWithLoading(a.modeLine, a.modeLineContext(), 0, label)
+
+	profile := a.modeLineContext()
+	go func() {
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for frame := 1; ; frame++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.tviewApp.QueueUpdateDraw(func() {
+					if ctx.Err() == nil {
+						ui.UpdateModeLineWithLoading(a.modeLine, profile, frame, label)
+					}
+				})
+			}
+		}
+	}()
+
+	go func() {
+		result, err := fetch(ctx)
+		a.tviewApp.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				// Cancelled; the mode line has already been restored
+				if closer, ok := any(result).(io.Closer); ok && err == nil {
+					closer.Close()
+				}
+				return
+			}
+			release := a.detachLoad()
+			defer release()
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					a.showErrorModal(fmt.Sprintf("Failed to load %s: %v", label, err))
+				}
+				return
+			}
+			render(result)
+		})
+	}()
+}
+
+// isLoading reports whether a background load is in flight
+func (a *App) isLoading() bool {
+	return a.loadCancel != nil
+}
+
+// cancelLoad cancels the background load in flight, if any, and restores the mode line.
+// A refresh waiting for that load is dropped.
+func (a *App) cancelLoad() {
+	if a.loadCancel == nil {
+		return
+	}
+	a.pendingRefresh = nil
+	a.finishLoad()
+}
+
+// finishLoad stops the spinner of the current load and restores the mode line it replaced
+func (a *App) finishLoad() {
+	a.detachLoad()()
+}
+
+// detachLoad restores the mode line the current load replaced and returns the cancel
+// function of its context, which also stops the spinner. Until it is called, the load's
+// context stays valid but no longer counts as the load in flight.
+func (a *App) detachLoad() context.CancelFunc {
+	cancel := a.loadCancel
+	a.loadCancel = nil
+	a.modeLine.SetText(a.loadModeLine)
+	return cancel
+}
+
+// nonNil returns items, or an empty slice if items is nil. List views use a nil cache to
+// mean "not loaded yet", so an empty result must not be stored as nil.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
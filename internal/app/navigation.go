@@ -1,7 +1,11 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
@@ -39,6 +43,10 @@ func (a *App) setupGlobalInputCapture() {
 
 		switch event.Key() {
 		case tcell.KeyEscape:
+			if a.isLoading() { // Esc cancels the load in flight instead of going back
+				a.cancelLoad()
+				return nil
+			}
 			a.handleEscapeKey(currentPageName)
 			return nil
 		case tcell.KeyRune:
@@ -51,6 +59,9 @@ func (a *App) setupGlobalInputCapture() {
 			} else if event.Rune() == 'O' { // Uppercase O opens profile selection
 				a.showProfileSelectionDialog()
 				return nil
+			} else if event.Rune() == 'R' { // Uppercase R opens region selection
+				a.showRegionSelectionDialog()
+				return nil
 			}
 		}
 		return event
@@ -66,27 +77,47 @@ func (a *App) handleEscapeKey(currentPageName string) {
 		a.handleNavigation(ui.PageEcsList, a.ecsInstanceTable)
 	case ui.PageSecurityGroupDetail:
 		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
-	case ui.PageSecurityGroupRules:
-		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
-	case ui.PageSecurityGroupInstances:
-		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
+	case ui.PageSecurityGroupRules, ui.PageSecurityGroupInstances:
+		a.leaveSecurityGroupPage()
+	case ui.PageSecurityGroupAudit:
+		a.leaveSecurityGroupAudit()
+	case ui.PageReachability:
+		a.leaveReachability()
+	case ui.PageSecurityGroupDiff:
+		a.leaveSecurityGroupDiff()
 	case ui.PageInstanceSecurityGroups:
 		a.handleNavigation(ui.PageEcsList, a.ecsInstanceTable)
-	case ui.PageDnsRecords:
+	case ui.PageDnsRecords, ui.PageDnsZoneImport:
 		a.handleNavigation(ui.PageDnsDomains, a.dnsDomainsTable)
 	case ui.PageSlbDetail:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
 	case ui.PageSlbListeners:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
+	case ui.PageSlbListenerDetail:
+		a.handleNavigation(ui.PageSlbListeners, a.slbListenersTable)
 	case ui.PageSlbVServerGroups:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
 	case ui.PageSlbVServerGroupBackendServers:
 		a.handleNavigation(ui.PageSlbVServerGroups, a.slbVServerGroupsTable)
 	case ui.PageOssObjects:
-		ui.UpdateModeLine(a.modeLine, a.currentProfile)
+		if a.leaveOssFolder() {
+			return
+		}
+		ui.UpdateModeLine(a.modeLine, a.modeLineContext())
+		a.handleNavigation(ui.PageOssBuckets, a.ossBucketTable)
+	case "ossObjectDetail", ui.PageOssObjectPreview:
+		a.handleNavigation(ui.PageOssObjects, a.ossObjectTable)
+	case ui.PageOssBucketConfig:
 		a.handleNavigation(ui.PageOssBuckets, a.ossBucketTable)
-	case "ossObjectDetail":
+	case ui.PageOssSearch:
+		a.stopOssSearch()
 		a.handleNavigation(ui.PageOssObjects, a.ossObjectTable)
+	case ui.PageOssSearchDetail:
+		a.handleNavigation(ui.PageOssSearch, a.ossSearchTable)
+	case ui.PageOssUsage:
+		a.leaveOssUsage()
+	case ui.PageOssVersions:
+		a.leaveOssVersions()
 	case ui.PageRdsDetail:
 		a.handleNavigation(ui.PageRdsList, a.rdsInstanceTable)
 	case ui.PageRdsDatabases:
@@ -97,6 +128,10 @@ func (a *App) handleEscapeKey(currentPageName string) {
 		a.handleNavigation(ui.PageRdsDatabases, a.rdsDatabaseTable)
 	case "rdsAccountDetail":
 		a.handleNavigation(ui.PageRdsAccounts, a.rdsAccountTable)
+	case ui.PageRdsNetInfo, ui.PageRdsWhitelists, ui.PageRdsParameters, ui.PageRdsBackups, ui.PageRdsReadOnly, ui.PageRdsSlowLogs:
+		a.handleNavigation(ui.PageRdsList, a.rdsInstanceTable)
+	case ui.PageRdsItemDetail:
+		a.handleNavigation(a.rdsInstancePage, a.rdsInstancePageTable)
 	case ui.PageRedisAccounts:
 		a.handleNavigation(ui.PageRedisList, a.redisInstanceTable)
 	case "redisDetail":
@@ -127,27 +162,47 @@ func (a *App) handleBackKey(currentPageName string) {
 		a.handleNavigation(ui.PageEcsList, a.ecsInstanceTable)
 	case ui.PageSecurityGroupDetail:
 		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
-	case ui.PageSecurityGroupRules:
-		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
-	case ui.PageSecurityGroupInstances:
-		a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
+	case ui.PageSecurityGroupRules, ui.PageSecurityGroupInstances:
+		a.leaveSecurityGroupPage()
+	case ui.PageSecurityGroupAudit:
+		a.leaveSecurityGroupAudit()
+	case ui.PageReachability:
+		a.leaveReachability()
+	case ui.PageSecurityGroupDiff:
+		a.leaveSecurityGroupDiff()
 	case ui.PageInstanceSecurityGroups:
 		a.handleNavigation(ui.PageEcsList, a.ecsInstanceTable)
-	case ui.PageDnsRecords:
+	case ui.PageDnsRecords, ui.PageDnsZoneImport:
 		a.handleNavigation(ui.PageDnsDomains, a.dnsDomainsTable)
 	case ui.PageSlbDetail:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
 	case ui.PageSlbListeners:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
+	case ui.PageSlbListenerDetail:
+		a.handleNavigation(ui.PageSlbListeners, a.slbListenersTable)
 	case ui.PageSlbVServerGroups:
 		a.handleNavigation(ui.PageSlbList, a.slbInstanceTable)
 	case ui.PageSlbVServerGroupBackendServers:
 		a.handleNavigation(ui.PageSlbVServerGroups, a.slbVServerGroupsTable)
 	case ui.PageOssObjects:
-		ui.UpdateModeLine(a.modeLine, a.currentProfile)
+		if a.leaveOssFolder() {
+			return
+		}
+		ui.UpdateModeLine(a.modeLine, a.modeLineContext())
+		a.handleNavigation(ui.PageOssBuckets, a.ossBucketTable)
+	case "ossObjectDetail", ui.PageOssObjectPreview:
+		a.handleNavigation(ui.PageOssObjects, a.ossObjectTable)
+	case ui.PageOssBucketConfig:
 		a.handleNavigation(ui.PageOssBuckets, a.ossBucketTable)
-	case "ossObjectDetail":
+	case ui.PageOssSearch:
+		a.stopOssSearch()
 		a.handleNavigation(ui.PageOssObjects, a.ossObjectTable)
+	case ui.PageOssSearchDetail:
+		a.handleNavigation(ui.PageOssSearch, a.ossSearchTable)
+	case ui.PageOssUsage:
+		a.leaveOssUsage()
+	case ui.PageOssVersions:
+		a.leaveOssVersions()
 	case ui.PageRdsDetail:
 		a.handleNavigation(ui.PageRdsList, a.rdsInstanceTable)
 	case ui.PageRdsDatabases:
@@ -158,6 +213,10 @@ func (a *App) handleBackKey(currentPageName string) {
 		a.handleNavigation(ui.PageRdsDatabases, a.rdsDatabaseTable)
 	case "rdsAccountDetail":
 		a.handleNavigation(ui.PageRdsAccounts, a.rdsAccountTable)
+	case ui.PageRdsNetInfo, ui.PageRdsWhitelists, ui.PageRdsParameters, ui.PageRdsBackups, ui.PageRdsReadOnly, ui.PageRdsSlowLogs:
+		a.handleNavigation(ui.PageRdsList, a.rdsInstanceTable)
+	case ui.PageRdsItemDetail:
+		a.handleNavigation(a.rdsInstancePage, a.rdsInstancePageTable)
 	case ui.PageRedisAccounts:
 		a.handleNavigation(ui.PageRedisList, a.redisInstanceTable)
 	case "redisDetail":
@@ -179,10 +238,13 @@ func (a *App) handleBackKey(currentPageName string) {
 
 // handleNavigation handles page navigation
 func (a *App) handleNavigation(targetPage string, focusItem tview.Primitive) {
+	// Leaving the page drops whatever it was loading
+	a.cancelLoad()
+
 	a.pages.SwitchToPage(targetPage)
 
 	// Update mode line with shortcuts for the current page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, targetPage)
+	a.updateListModeLine(targetPage)
 
 	if focusItem != nil {
 		a.tviewApp.SetFocus(focusItem)
@@ -191,17 +253,26 @@ func (a *App) handleNavigation(targetPage string, focusItem tview.Primitive) {
 	}
 }
 
+// reloadEcsListView fetches the ECS instances and shows them
+func (a *App) reloadEcsListView() {
+	loadRegional(a, "ECS instances",
+		func(ctx context.Context, s *Services) ([]ecs.Instance, error) { return s.ECS.FetchInstances(ctx) },
+		func(inst ecs.Instance) string { return inst.InstanceId },
+		func(instances []ecs.Instance) {
+			a.allECSInstances = instances
+			saveSnapshot(a, ui.PageEcsList, a.allECSInstances)
+			a.switchToEcsListView()
+		})
+}
+
 // switchToEcsListView switches to ECS list view
 func (a *App) switchToEcsListView() {
-	if a.allECSInstances == nil {
-		instances, err := a.services.ECS.FetchInstances()
-		if err != nil {
-			a.showErrorModal(err.Error())
-			return
-		}
-		a.allECSInstances = instances
+	if a.allECSInstances == nil && !restoreSnapshot(a, ui.PageEcsList, &a.allECSInstances) {
+		a.reloadEcsListView()
+		return
 	}
 	a.ecsInstanceTable = ui.CreateEcsListView(a.allECSInstances)
+	a.insertRegionColumn(a.ecsInstanceTable)
 	ui.SetupTableNavigationWithSearch(a.ecsInstanceTable, a, func(row, col int) {
 		instanceId := a.ecsInstanceTable.GetCell(row, 0).GetReference().(string)
 		var selectedInstance interface{}
@@ -244,20 +315,22 @@ func (a *App) switchToEcsListView() {
 		}
 
 		// Update mode line with shortcuts for ECS detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageEcsDetail)
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageEcsDetail)
 
 		a.tviewApp.SetFocus(a.ecsDetailView)
 	})
 
 	a.setupTableYankFunctionality(a.ecsInstanceTable, a.allECSInstances)
+	a.setupTableRefresh(ui.PageEcsList, a.ecsInstanceTable, a.reloadEcsListView)
 	a.setupEcsKeyHandlers(a.ecsInstanceTable)
 	ecsListFlex := ui.WrapTableInFlex(a.ecsInstanceTable)
 	a.pages.AddPage(ui.PageEcsList, ecsListFlex, true, true)
 
 	// Update mode line with shortcuts for ECS list page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageEcsList)
+	a.updateListModeLine(ui.PageEcsList)
 
 	a.tviewApp.SetFocus(a.ecsInstanceTable)
+	a.showRegionWarnings()
 }
 
 // setupEcsKeyHandlers sets up key handlers for ECS specific actions
@@ -276,6 +349,21 @@ func (a *App) setupEcsKeyHandlers(table *tview.Table) {
 				}
 			}
 			return nil
+		case 'c': // Check whether a source can reach the selected instance
+			a.showReachabilityDialog(table)
+			return nil
+		case 'S': // Start the selected instance
+			a.confirmStartInstance(table)
+			return nil
+		case 'X': // Stop the selected instance
+			a.confirmStopInstance(table)
+			return nil
+		case 'B': // Reboot the selected instance
+			a.confirmRebootInstance(table)
+			return nil
+		case 'M': // Modify the name and description of the selected instance
+			a.showModifyInstanceDialog(table)
+			return nil
 		}
 
 		// Call original input capture if it exists
@@ -286,17 +374,29 @@ func (a *App) setupEcsKeyHandlers(table *tview.Table) {
 	})
 }
 
+// reloadSecurityGroupsListView fetches the security groups and shows them
+func (a *App) reloadSecurityGroupsListView() {
+	loadRegional(a, "security groups",
+		func(ctx context.Context, s *Services) ([]ecs.SecurityGroup, error) {
+			return s.ECS.FetchSecurityGroups(ctx)
+		},
+		func(sg ecs.SecurityGroup) string { return sg.SecurityGroupId },
+		func(securityGroups []ecs.SecurityGroup) {
+			a.allSecurityGroups = securityGroups
+			saveSnapshot(a, ui.PageSecurityGroups, a.allSecurityGroups)
+			a.switchToSecurityGroupsListView()
+		})
+}
+
 // switchToSecurityGroupsListView switches to security groups list view
 func (a *App) switchToSecurityGroupsListView() {
-	if a.allSecurityGroups == nil {
-		securityGroups, err := a.services.ECS.FetchSecurityGroups()
-		if err != nil {
-			a.showErrorModal(err.Error())
-			return
-		}
-		a.allSecurityGroups = securityGroups
+	if a.allSecurityGroups == nil && !restoreSnapshot(a, ui.PageSecurityGroups, &a.allSecurityGroups) {
+		a.reloadSecurityGroupsListView()
+		return
 	}
+	a.sgReturnPage, a.sgReturnFocus = "", nil
 	a.securityGroupTable = ui.CreateSecurityGroupsListView(a.allSecurityGroups)
+	a.insertRegionColumn(a.securityGroupTable)
 	ui.SetupTableNavigationWithSearch(a.securityGroupTable, a, func(row, col int) {
 		securityGroupId := a.securityGroupTable.GetCell(row, 0).GetReference().(string)
 		// 回车键进入安全组规则列表
@@ -304,14 +404,16 @@ func (a *App) switchToSecurityGroupsListView() {
 	})
 
 	a.setupTableYankFunctionality(a.securityGroupTable, a.allSecurityGroups)
+	a.setupTableRefresh(ui.PageSecurityGroups, a.securityGroupTable, a.reloadSecurityGroupsListView)
 	a.setupSecurityGroupKeyHandlers(a.securityGroupTable)
 	securityGroupListFlex := ui.WrapTableInFlex(a.securityGroupTable)
 	a.pages.AddPage(ui.PageSecurityGroups, securityGroupListFlex, true, true)
 
 	// Update mode line with shortcuts for security groups page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSecurityGroups)
+	a.updateListModeLine(ui.PageSecurityGroups)
 
 	a.tviewApp.SetFocus(a.securityGroupTable)
+	a.showRegionWarnings()
 }
 
 // setupSecurityGroupKeyHandlers sets up key handlers for security group specific actions
@@ -330,6 +432,12 @@ func (a *App) setupSecurityGroupKeyHandlers(table *tview.Table) {
 				}
 			}
 			return nil
+		case 'a': // Audit every security group
+			a.reloadSecurityGroupAudit()
+			return nil
+		case 'd': // Compare the rules with another security group
+			a.showSecurityGroupCompareDialog(table)
+			return nil
 		}
 
 		// Call original input capture if it exists
@@ -340,35 +448,48 @@ func (a *App) setupSecurityGroupKeyHandlers(table *tview.Table) {
 	})
 }
 
-// switchToSecurityGroupRulesView switches to security group rules view
+// switchToSecurityGroupRulesView loads the rules of a security group and shows them
 func (a *App) switchToSecurityGroupRulesView(securityGroupId string) {
-	rulesResponse, err := a.services.ECS.FetchSecurityGroupRules(securityGroupId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch security group rules for %s: %v", securityGroupId, err))
-		return
-	}
+	services := a.servicesFor(securityGroupId)
+	loadAsync(a, fmt.Sprintf("security group rules for %s", securityGroupId),
+		func(ctx context.Context) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+			return services.ECS.FetchSecurityGroupRules(ctx, securityGroupId)
+		}, a.showSecurityGroupRulesView)
+}
 
+// showSecurityGroupRulesView switches to security group rules view
+func (a *App) showSecurityGroupRulesView(rulesResponse *ecs.DescribeSecurityGroupAttributeResponse) {
+	a.currentSecurityGroupRules = rulesResponse
 	a.securityGroupRulesTable = ui.CreateSecurityGroupRulesView(rulesResponse)
 	ui.SetupTableNavigationWithSearch(a.securityGroupRulesTable, a, nil)
 
 	a.setupTableYankFunctionality(a.securityGroupRulesTable, rulesResponse)
+	a.setupTableRefresh(ui.PageSecurityGroupRules, a.securityGroupRulesTable, func() {
+		a.switchToSecurityGroupRulesView(rulesResponse.SecurityGroupId)
+	})
+	a.setupSecurityGroupRuleKeyHandlers(a.securityGroupRulesTable)
 	securityGroupRulesListFlex := ui.WrapTableInFlex(a.securityGroupRulesTable)
 	a.pages.AddPage(ui.PageSecurityGroupRules, securityGroupRulesListFlex, true, true)
 
 	// Update mode line with shortcuts for security group rules page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSecurityGroupRules)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSecurityGroupRules)
 
 	a.tviewApp.SetFocus(a.securityGroupRulesTable)
 }
 
-// switchToSecurityGroupInstancesView switches to instances using a security group
+// switchToSecurityGroupInstancesView loads the instances using a security group and shows them
 func (a *App) switchToSecurityGroupInstancesView(securityGroupId string) {
-	instances, err := a.services.ECS.FetchInstancesBySecurityGroup(securityGroupId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch instances for security group %s: %v", securityGroupId, err))
-		return
-	}
+	services := a.servicesFor(securityGroupId)
+	loadAsync(a, fmt.Sprintf("instances for security group %s", securityGroupId),
+		func(ctx context.Context) ([]ecs.Instance, error) {
+			return services.ECS.FetchInstancesBySecurityGroup(ctx, securityGroupId)
+		}, func(instances []ecs.Instance) {
+			a.showSecurityGroupInstancesView(securityGroupId, instances)
+		})
+}
 
+// showSecurityGroupInstancesView switches to instances using a security group
+func (a *App) showSecurityGroupInstancesView(securityGroupId string, instances []ecs.Instance) {
 	a.securityGroupInstancesTable = ui.CreateSecurityGroupInstancesView(instances, securityGroupId)
 	ui.SetupTableNavigationWithSearch(a.securityGroupInstancesTable, a, func(row, col int) {
 		instanceId := a.securityGroupInstancesTable.GetCell(row, 0).GetReference().(string)
@@ -412,27 +533,39 @@ func (a *App) switchToSecurityGroupInstancesView(securityGroupId string) {
 		}
 
 		// Update mode line with shortcuts for ECS detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageEcsDetail)
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageEcsDetail)
 
 		a.tviewApp.SetFocus(a.ecsDetailView)
 	})
 
 	a.setupTableYankFunctionality(a.securityGroupInstancesTable, instances)
+	a.setupTableRefresh(ui.PageSecurityGroupInstances, a.securityGroupInstancesTable, func() {
+		a.switchToSecurityGroupInstancesView(securityGroupId)
+	})
 	securityGroupInstancesListFlex := ui.WrapTableInFlex(a.securityGroupInstancesTable)
 	a.pages.AddPage(ui.PageSecurityGroupInstances, securityGroupInstancesListFlex, true, true)
 
 	// Update mode line with shortcuts for security group instances page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSecurityGroupInstances)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSecurityGroupInstances)
 
 	a.tviewApp.SetFocus(a.securityGroupInstancesTable)
 }
 
-// switchToInstanceSecurityGroupsView switches to security groups for an instance
+// switchToInstanceSecurityGroupsView loads the security groups of an instance and shows them
 func (a *App) switchToInstanceSecurityGroupsView(instanceId string) {
-	securityGroups, err := a.services.ECS.FetchSecurityGroupsByInstance(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch security groups for instance %s: %v", instanceId, err))
-		return
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("security groups for instance %s", instanceId),
+		func(ctx context.Context) ([]ecs.SecurityGroup, error) {
+			return services.ECS.FetchSecurityGroupsByInstance(ctx, instanceId)
+		}, func(securityGroups []ecs.SecurityGroup) {
+			a.showInstanceSecurityGroupsView(instanceId, securityGroups)
+		})
+}
+
+// showInstanceSecurityGroupsView switches to security groups for an instance
+func (a *App) showInstanceSecurityGroupsView(instanceId string, securityGroups []ecs.SecurityGroup) {
+	for _, sg := range securityGroups {
+		a.inheritRegion(instanceId, sg.SecurityGroupId)
 	}
 
 	a.instanceSecurityGroupsTable = ui.CreateInstanceSecurityGroupsView(securityGroups, instanceId)
@@ -443,24 +576,35 @@ func (a *App) switchToInstanceSecurityGroupsView(instanceId string) {
 	})
 
 	a.setupTableYankFunctionality(a.instanceSecurityGroupsTable, securityGroups)
+	a.setupTableRefresh(ui.PageInstanceSecurityGroups, a.instanceSecurityGroupsTable, func() {
+		a.switchToInstanceSecurityGroupsView(instanceId)
+	})
 	instanceSecurityGroupsListFlex := ui.WrapTableInFlex(a.instanceSecurityGroupsTable)
 	a.pages.AddPage(ui.PageInstanceSecurityGroups, instanceSecurityGroupsListFlex, true, true)
 
 	// Update mode line with shortcuts for instance security groups page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageInstanceSecurityGroups)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageInstanceSecurityGroups)
 
 	a.tviewApp.SetFocus(a.instanceSecurityGroupsTable)
 }
 
+// reloadDnsDomainsListView fetches the DNS domains and shows them
+func (a *App) reloadDnsDomainsListView() {
+	services := a.services
+	loadAsync(a, "DNS domains", func(ctx context.Context) ([]alidns.DomainInDescribeDomains, error) {
+		return services.DNS.FetchDomains(ctx)
+	}, func(domains []alidns.DomainInDescribeDomains) {
+		a.allDomains = nonNil(domains)
+		saveSnapshot(a, ui.PageDnsDomains, a.allDomains)
+		a.switchToDnsDomainsListView()
+	})
+}
+
 // switchToDnsDomainsListView switches to DNS domains list view
 func (a *App) switchToDnsDomainsListView() {
-	if a.allDomains == nil {
-		domains, err := a.services.DNS.FetchDomains()
-		if err != nil {
-			a.showErrorModal(err.Error())
-			return
-		}
-		a.allDomains = domains
+	if a.allDomains == nil && !restoreSnapshot(a, ui.PageDnsDomains, &a.allDomains) {
+		a.reloadDnsDomainsListView()
+		return
 	}
 	a.dnsDomainsTable = ui.CreateDnsDomainsListView(a.allDomains)
 	ui.SetupTableNavigationWithSearch(a.dnsDomainsTable, a, func(row, col int) {
@@ -469,46 +613,69 @@ func (a *App) switchToDnsDomainsListView() {
 	})
 
 	a.setupTableYankFunctionality(a.dnsDomainsTable, a.allDomains)
+	a.setupTableRefresh(ui.PageDnsDomains, a.dnsDomainsTable, a.reloadDnsDomainsListView)
+	a.setupDnsDomainKeyHandlers(a.dnsDomainsTable)
 	dnsDomainsListFlex := ui.WrapTableInFlex(a.dnsDomainsTable)
 	a.pages.AddPage(ui.PageDnsDomains, dnsDomainsListFlex, true, true)
 
 	// Update mode line with shortcuts for DNS domains page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageDnsDomains)
+	a.updateListModeLine(ui.PageDnsDomains)
 
 	a.tviewApp.SetFocus(a.dnsDomainsTable)
 }
 
-// switchToDnsRecordsListView switches to DNS records list view
+// switchToDnsRecordsListView loads the records of a domain and shows them
 func (a *App) switchToDnsRecordsListView(domainName string) {
-	records, err := a.services.DNS.FetchDomainRecords(domainName)
-	if err != nil {
-		a.showErrorModal(err.Error())
-		return
-	}
+	services := a.services
+	loadAsync(a, fmt.Sprintf("DNS records for %s", domainName),
+		func(ctx context.Context) ([]alidns.Record, error) {
+			return services.DNS.FetchDomainRecords(ctx, domainName)
+		}, func(records []alidns.Record) {
+			a.showDnsRecordsListView(domainName, records)
+		})
+}
+
+// showDnsRecordsListView switches to DNS records list view
+func (a *App) showDnsRecordsListView(domainName string, records []alidns.Record) {
+	a.currentDomainName = domainName
+	a.currentDnsRecords = records
 	a.dnsRecordsTable = ui.CreateDnsRecordsListView(records, domainName)
 	ui.SetupTableNavigationWithSearch(a.dnsRecordsTable, a, nil)
 
 	a.setupTableYankFunctionality(a.dnsRecordsTable, records)
+	a.setupTableRefresh(ui.PageDnsRecords, a.dnsRecordsTable, func() {
+		a.switchToDnsRecordsListView(domainName)
+	})
+	a.setupDnsRecordKeyHandlers(a.dnsRecordsTable)
 	dnsRecordsListFlex := ui.WrapTableInFlex(a.dnsRecordsTable)
 	a.pages.AddPage(ui.PageDnsRecords, dnsRecordsListFlex, true, true)
 
 	// Update mode line with shortcuts for DNS records page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageDnsRecords)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageDnsRecords)
 
 	a.tviewApp.SetFocus(a.dnsRecordsTable)
 }
 
+// reloadSlbListView fetches the SLB instances and shows them
+func (a *App) reloadSlbListView() {
+	loadRegional(a, "SLB instances",
+		func(ctx context.Context, s *Services) ([]slb.LoadBalancer, error) { return s.SLB.FetchInstances(ctx) },
+		func(lb slb.LoadBalancer) string { return lb.LoadBalancerId },
+		func(slbs []slb.LoadBalancer) {
+			a.allSLBInstances = slbs
+			saveSnapshot(a, ui.PageSlbList, a.allSLBInstances)
+			a.switchToSlbListView()
+		})
+}
+
 // switchToSlbListView switches to SLB list view
 func (a *App) switchToSlbListView() {
-	if a.allSLBInstances == nil {
-		slbs, err := a.services.SLB.FetchInstances()
-		if err != nil {
-			a.showErrorModal(err.Error())
-			return
-		}
-		a.allSLBInstances = slbs
+	if a.allSLBInstances == nil && !restoreSnapshot(a, ui.PageSlbList, &a.allSLBInstances) {
+		a.reloadSlbListView()
+		return
 	}
 	a.slbInstanceTable = ui.CreateSlbListView(a.allSLBInstances)
+	a.insertRegionColumn(a.slbInstanceTable)
 	ui.SetupTableNavigationWithSearch(a.slbInstanceTable, a, func(row, col int) {
 		slbId := a.slbInstanceTable.GetCell(row, 0).GetReference().(string)
 		var selectedSlb interface{}
@@ -551,20 +718,22 @@ func (a *App) switchToSlbListView() {
 		}
 
 		// Update mode line with shortcuts for SLB detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSlbDetail)
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSlbDetail)
 
 		a.tviewApp.SetFocus(a.slbDetailView)
 	})
 
 	a.setupTableYankFunctionality(a.slbInstanceTable, a.allSLBInstances)
+	a.setupTableRefresh(ui.PageSlbList, a.slbInstanceTable, a.reloadSlbListView)
 	a.setupSlbKeyHandlers(a.slbInstanceTable)
 	slbListFlex := ui.WrapTableInFlex(a.slbInstanceTable)
 	a.pages.AddPage(ui.PageSlbList, slbListFlex, true, true)
 
 	// Update mode line with shortcuts for SLB list page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSlbList)
+	a.updateListModeLine(ui.PageSlbList)
 
 	a.tviewApp.SetFocus(a.slbInstanceTable)
+	a.showRegionWarnings()
 }
 
 // setupSlbKeyHandlers sets up key handlers for SLB specific actions
@@ -603,35 +772,90 @@ func (a *App) setupSlbKeyHandlers(table *tview.Table) {
 	})
 }
 
-// switchToSlbListenersView switches to SLB listeners view
+// switchToSlbListenersView loads the listeners of an SLB instance and shows them
 func (a *App) switchToSlbListenersView(loadBalancerId string) {
-	detailedListeners, err := a.services.SLB.FetchDetailedListeners(loadBalancerId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch listeners for SLB %s: %v", loadBalancerId, err))
-		return
-	}
+	services := a.servicesFor(loadBalancerId)
+	loadAsync(a, fmt.Sprintf("listeners for SLB %s", loadBalancerId),
+		func(ctx context.Context) ([]service.ListenerDetail, error) {
+			return services.SLB.FetchDetailedListeners(ctx, loadBalancerId)
+		}, func(detailedListeners []service.ListenerDetail) {
+			a.showSlbListenersView(loadBalancerId, detailedListeners)
+		})
+}
 
+// showSlbListenersView switches to SLB listeners view
+func (a *App) showSlbListenersView(loadBalancerId string, detailedListeners []service.ListenerDetail) {
 	a.slbListenersTable = ui.CreateSlbDetailedListenersView(detailedListeners, loadBalancerId)
-	ui.SetupTableNavigationWithSearch(a.slbListenersTable, a, nil)
+	ui.SetupTableNavigationWithSearch(a.slbListenersTable, a, func(row, col int) {
+		if reference, ok := ui.SelectedReference(a.slbListenersTable); ok {
+			a.switchToSlbListenerDetailView(loadBalancerId, reference)
+		}
+	})
 
 	a.setupTableYankFunctionality(a.slbListenersTable, detailedListeners)
+	a.setupTableRefresh(ui.PageSlbListeners, a.slbListenersTable, func() {
+		a.switchToSlbListenersView(loadBalancerId)
+	})
 	slbListenersListFlex := ui.WrapTableInFlex(a.slbListenersTable)
 	a.pages.AddPage(ui.PageSlbListeners, slbListenersListFlex, true, true)
 
 	// Update mode line with shortcuts for SLB listeners page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSlbListeners)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSlbListeners)
 
 	a.tviewApp.SetFocus(a.slbListenersTable)
 }
 
-// switchToSlbVServerGroupsView switches to SLB virtual server groups view
-func (a *App) switchToSlbVServerGroupsView(loadBalancerId string) {
-	detailedVServerGroups, err := a.services.SLB.FetchDetailedVServerGroups(loadBalancerId)
+// switchToSlbListenerDetailView loads the full configuration of a listener, referenced by
+// its protocol and port, and shows it
+func (a *App) switchToSlbListenerDetailView(loadBalancerId, reference string) {
+	protocol, portText, _ := strings.Cut(reference, ":")
+	port, err := strconv.Atoi(portText)
 	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch virtual server groups for SLB %s: %v", loadBalancerId, err))
+		a.showErrorModal(fmt.Sprintf("Invalid listener %q", reference))
 		return
 	}
 
+	services := a.servicesFor(loadBalancerId)
+	loadAsync(a, fmt.Sprintf("listener %s of SLB %s", reference, loadBalancerId),
+		func(ctx context.Context) (*service.ListenerAttributes, error) {
+			return services.SLB.FetchListenerAttributes(ctx, loadBalancerId, protocol, port)
+		}, func(attributes *service.ListenerAttributes) {
+			a.showSlbListenerDetailView(attributes)
+		})
+}
+
+// showSlbListenerDetailView switches to the full configuration of a listener
+func (a *App) showSlbListenerDetailView(attributes *service.ListenerAttributes) {
+	a.slbListenerDetailTable = ui.CreateSlbListenerDetailView(attributes)
+	ui.SetupTableNavigationWithSearch(a.slbListenerDetailTable, a, nil)
+
+	a.setupTableYankFunctionality(a.slbListenerDetailTable, attributes)
+	a.setupTableRefresh(ui.PageSlbListenerDetail, a.slbListenerDetailTable, func() {
+		a.switchToSlbListenerDetailView(attributes.LoadBalancerId, ui.SlbListenerReference(attributes.Protocol, attributes.Port))
+	})
+	a.pages.AddPage(ui.PageSlbListenerDetail, ui.WrapTableInFlex(a.slbListenerDetailTable), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSlbListenerDetail)
+
+	a.tviewApp.SetFocus(a.slbListenerDetailTable)
+}
+
+// switchToSlbVServerGroupsView loads the virtual server groups of an SLB instance and shows them
+func (a *App) switchToSlbVServerGroupsView(loadBalancerId string) {
+	services := a.servicesFor(loadBalancerId)
+	loadAsync(a, fmt.Sprintf("virtual server groups for SLB %s", loadBalancerId),
+		func(ctx context.Context) ([]service.VServerGroupDetail, error) {
+			return services.SLB.FetchDetailedVServerGroups(ctx, loadBalancerId)
+		}, func(detailedVServerGroups []service.VServerGroupDetail) {
+			a.showSlbVServerGroupsView(loadBalancerId, detailedVServerGroups)
+		})
+}
+
+// showSlbVServerGroupsView switches to SLB virtual server groups view
+func (a *App) showSlbVServerGroupsView(loadBalancerId string, detailedVServerGroups []service.VServerGroupDetail) {
+	for _, group := range detailedVServerGroups {
+		a.inheritRegion(loadBalancerId, group.VServerGroupId)
+	}
+
 	a.slbVServerGroupsTable = ui.CreateSlbDetailedVServerGroupsView(detailedVServerGroups, loadBalancerId)
 	ui.SetupTableNavigationWithSearch(a.slbVServerGroupsTable, a, func(row, col int) {
 		vServerGroupId := a.slbVServerGroupsTable.GetCell(row, 0).GetReference().(string)
@@ -639,45 +863,70 @@ func (a *App) switchToSlbVServerGroupsView(loadBalancerId string) {
 	})
 
 	a.setupTableYankFunctionality(a.slbVServerGroupsTable, detailedVServerGroups)
+	a.setupTableRefresh(ui.PageSlbVServerGroups, a.slbVServerGroupsTable, func() {
+		a.switchToSlbVServerGroupsView(loadBalancerId)
+	})
 	slbVServerGroupsListFlex := ui.WrapTableInFlex(a.slbVServerGroupsTable)
 	a.pages.AddPage(ui.PageSlbVServerGroups, slbVServerGroupsListFlex, true, true)
 
 	// Update mode line with shortcuts for SLB VServer groups page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSlbVServerGroups)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSlbVServerGroups)
 
 	a.tviewApp.SetFocus(a.slbVServerGroupsTable)
 }
 
-// switchToSlbVServerGroupBackendServersView switches to SLB virtual server group backend servers view
+// switchToSlbVServerGroupBackendServersView loads the backend servers of a virtual server group and shows them
 func (a *App) switchToSlbVServerGroupBackendServersView(vServerGroupId string) {
-	detailedBackendServers, err := a.services.SLB.FetchDetailedBackendServers(vServerGroupId, a.clients.ECS)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch backend servers for virtual server group %s: %v", vServerGroupId, err))
-		return
+	// The ECS client is only used to enrich backend servers; it is absent when running on fakes
+	clients, services := a.contextFor(vServerGroupId)
+	var ecsClient *ecs.Client
+	if clients != nil {
+		ecsClient = clients.ECS
 	}
 
+	loadAsync(a, fmt.Sprintf("backend servers for virtual server group %s", vServerGroupId),
+		func(ctx context.Context) ([]service.BackendServerDetail, error) {
+			return services.SLB.FetchDetailedBackendServers(ctx, vServerGroupId, ecsClient)
+		}, func(detailedBackendServers []service.BackendServerDetail) {
+			a.showSlbVServerGroupBackendServersView(vServerGroupId, detailedBackendServers)
+		})
+}
+
+// showSlbVServerGroupBackendServersView switches to SLB virtual server group backend servers view
+func (a *App) showSlbVServerGroupBackendServersView(vServerGroupId string, detailedBackendServers []service.BackendServerDetail) {
 	a.slbVServerGroupBackendServersTable = ui.CreateSlbDetailedBackendServersView(detailedBackendServers, vServerGroupId)
 	ui.SetupTableNavigationWithSearch(a.slbVServerGroupBackendServersTable, a, nil)
 
 	a.setupTableYankFunctionality(a.slbVServerGroupBackendServersTable, detailedBackendServers)
+	a.setupTableRefresh(ui.PageSlbVServerGroupBackendServers, a.slbVServerGroupBackendServersTable, func() {
+		a.switchToSlbVServerGroupBackendServersView(vServerGroupId)
+	})
 	slbVServerGroupBackendServersListFlex := ui.WrapTableInFlex(a.slbVServerGroupBackendServersTable)
 	a.pages.AddPage(ui.PageSlbVServerGroupBackendServers, slbVServerGroupBackendServersListFlex, true, true)
 
 	// Update mode line with shortcuts for SLB backend servers page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageSlbVServerGroupBackendServers)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSlbVServerGroupBackendServers)
 
 	a.tviewApp.SetFocus(a.slbVServerGroupBackendServersTable)
 }
 
+// reloadOssBucketListView fetches the OSS buckets and shows them
+func (a *App) reloadOssBucketListView() {
+	services := a.services
+	loadAsync(a, "OSS buckets", func(ctx context.Context) ([]oss.BucketProperties, error) {
+		return services.OSS.FetchBuckets(ctx)
+	}, func(buckets []oss.BucketProperties) {
+		a.allOssBuckets = nonNil(buckets)
+		saveSnapshot(a, ui.PageOssBuckets, a.allOssBuckets)
+		a.switchToOssBucketListView()
+	})
+}
+
 // switchToOssBucketListView switches to OSS bucket list view
 func (a *App) switchToOssBucketListView() {
-	if a.allOssBuckets == nil {
-		buckets, err := a.services.OSS.FetchBuckets()
-		if err != nil {
-			a.showErrorModal(err.Error())
-			return
-		}
-		a.allOssBuckets = buckets
+	if a.allOssBuckets == nil && !restoreSnapshot(a, ui.PageOssBuckets, &a.allOssBuckets) {
+		a.reloadOssBucketListView()
+		return
 	}
 	a.ossBucketTable = ui.CreateOssBucketListView(a.allOssBuckets)
 	ui.SetupTableNavigationWithSearch(a.ossBucketTable, a, func(row, col int) {
@@ -686,11 +935,13 @@ func (a *App) switchToOssBucketListView() {
 		a.switchToOssObjectListView(bucketName)
 	})
 
+	a.setupTableRefresh(ui.PageOssBuckets, a.ossBucketTable, a.reloadOssBucketListView)
+	a.setupOssBucketKeyHandlers(a.ossBucketTable)
 	ossBucketListFlex := ui.WrapTableInFlex(a.ossBucketTable)
 	a.pages.AddPage(ui.PageOssBuckets, ossBucketListFlex, true, true)
 
 	// Update mode line with shortcuts for OSS buckets page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageOssBuckets)
+	a.updateListModeLine(ui.PageOssBuckets)
 
 	a.tviewApp.SetFocus(a.ossBucketTable)
 }
@@ -699,6 +950,9 @@ func (a *App) switchToOssBucketListView() {
 func (a *App) switchToOssObjectListView(bucketName string) {
 	// Initialize pagination state
 	a.currentBucketName = bucketName
+	a.stopOssSearch()
+	a.ossCurrentPrefix = ""
+	a.ossFolderStack = nil
 	a.ossCurrentMarker = ""
 	a.ossPreviousMarkers = []string{}
 	a.ossCurrentPage = 1
@@ -711,75 +965,106 @@ func (a *App) switchToOssObjectListView(bucketName string) {
 
 // loadOssObjectPage loads the current page of OSS objects
 func (a *App) loadOssObjectPage() {
-	result, err := a.services.OSS.FetchObjects(a.currentBucketName, a.ossCurrentMarker, a.ossPageSize)
-	if err != nil {
-		a.showErrorModal(err.Error())
-		return
-	}
+	a.loadOssListing(a.ossCurrentPrefix, a.ossCurrentMarker, func() {})
+}
+
+// loadOssListing loads the page of the folder prefix starting at marker. The object list
+// moves there only once the page is loaded: move then updates the folder and page state,
+// so a load that fails or is cancelled leaves the list where it was.
+func (a *App) loadOssListing(prefix, marker string, move func()) {
+	services := a.services
+	bucketName, pageSize := a.currentBucketName, a.ossPageSize
+	loadAsync(a, fmt.Sprintf("objects in %s", bucketName),
+		func(ctx context.Context) (*service.ObjectListResult, error) {
+			return services.OSS.FetchFolder(ctx, bucketName, prefix, marker, pageSize)
+		},
+		func(result *service.ObjectListResult) {
+			move()
+			a.showOssObjectPage(result)
+		})
+}
 
+// showOssObjectPage shows a page of OSS objects
+func (a *App) showOssObjectPage(result *service.ObjectListResult) {
 	a.ossHasNextPage = result.IsTruncated
+	a.ossCurrentObjects = result.Objects
 	hasPrevious := len(a.ossPreviousMarkers) > 0
 
 	pageInfo := fmt.Sprintf("Page %d", a.ossCurrentPage)
 	if a.ossHasNextPage {
 		pageInfo += "+"
 	}
-	ui.UpdateModeLineWithPageInfoAndShortcuts(a.modeLine, a.currentProfile, ui.PageOssObjects, pageInfo)
+	ui.UpdateModeLineWithPageInfoAndShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssObjects, pageInfo)
 
-	ossObjectView := ui.CreateOssObjectPaginatedView(result.Objects, a.currentBucketName, a.ossCurrentPage, a.ossHasNextPage, hasPrevious)
+	ossObjectView := ui.CreateOssObjectPaginatedView(result.Folders, result.Objects, a.currentBucketName, a.ossCurrentPrefix, a.ossCurrentPage, a.ossHasNextPage, hasPrevious)
 
 	if ossObjectView.GetItemCount() > 0 {
 		a.ossObjectTable = ossObjectView.GetItem(0).(*tview.Table)
 	}
+	if a.ossSelectKey != "" {
+		selectReference(a.ossObjectTable, a.ossSelectKey)
+		a.ossSelectKey = ""
+	}
 
 	ui.SetupTableNavigationWithSearch(a.ossObjectTable, a, func(row, col int) {
 		objectKey := a.ossObjectTable.GetCell(row, 0).GetReference().(string)
+		if strings.HasSuffix(objectKey, service.OSSDelimiter) {
+			a.enterOssFolder(objectKey)
+			return
+		}
 		for _, obj := range result.Objects {
 			if obj.Key == objectKey {
-				a.currentDetailData = obj
-				view, _ := ui.CreateInteractiveJSONDetailViewWithSearch(
-					fmt.Sprintf("Object Details: %s", objectKey),
-					obj,
-					a,
-					func() {
-						err := ui.CopyToClipboard(obj)
-						if err != nil {
-							a.showErrorModal(fmt.Sprintf("Failed to copy: %v", err))
-						} else {
-							a.showErrorModal("Copied!")
-						}
-					},
-					func() {
-						err := ui.OpenInEditor(obj, a.tviewApp)
-						if err != nil {
-							a.showErrorModal(fmt.Sprintf("Failed to edit: %v", err))
-						}
-					},
-					func() {
-						err := ui.OpenInPager(obj, a.tviewApp)
-						if err != nil {
-							a.showErrorModal(fmt.Sprintf("Failed to open pager: %v", err))
-						}
-					},
-				)
-				a.ossDetailView = view
-				a.pages.AddPage("ossObjectDetail", a.ossDetailView, true, true)
-
-				// Update mode line with shortcuts for OSS object detail page
-				ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "ossObjectDetail")
-
-				a.tviewApp.SetFocus(a.ossDetailView)
+				a.showOssObjectDetail(obj, "ossObjectDetail")
 				break
 			}
 		}
 	})
 
 	a.setupTableYankFunctionality(a.ossObjectTable, result.Objects)
+	a.setupTableRefresh(ui.PageOssObjects, a.ossObjectTable, a.loadOssObjectPage)
+	a.setupOssObjectKeyHandlers(a.ossObjectTable)
 	a.setupOssPaginationNavigation(ossObjectView, result)
 	a.pages.AddPage(ui.PageOssObjects, ossObjectView, true, true)
 	a.tviewApp.SetFocus(a.ossObjectTable)
 }
 
+// showOssObjectDetail shows the metadata of an object as JSON on the given page
+func (a *App) showOssObjectDetail(obj oss.ObjectProperties, page string) {
+	a.currentDetailData = obj
+	view, _ := ui.CreateInteractiveJSONDetailViewWithSearch(
+		fmt.Sprintf("Object Details: %s", obj.Key),
+		obj,
+		a,
+		func() {
+			err := ui.CopyToClipboard(obj)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to copy: %v", err))
+			} else {
+				a.showErrorModal("Copied!")
+			}
+		},
+		func() {
+			err := ui.OpenInEditor(obj, a.tviewApp)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to edit: %v", err))
+			}
+		},
+		func() {
+			err := ui.OpenInPager(obj, a.tviewApp)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to open pager: %v", err))
+			}
+		},
+	)
+	a.ossDetailView = view
+	a.pages.AddPage(page, a.ossDetailView, true, true)
+
+	// Update mode line with shortcuts for OSS object detail page
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "ossObjectDetail")
+
+	a.tviewApp.SetFocus(a.ossDetailView)
+}
+
 // setupTableYankFunctionality adds yank (copy) functionality to tables
 func (a *App) setupTableYankFunctionality(table *tview.Table, data interface{}) {
 	originalInputCapture := table.GetInputCapture()
@@ -837,11 +1122,14 @@ func (a *App) setupTableYankFunctionality(table *tview.Table, data interface{})
 								rowData = items
 							case []service.ListenerDetail:
 								for _, listener := range items {
-									if fmt.Sprintf("%d", listener.Port) == ref.(string) {
+									if ui.SlbListenerReference(listener.Protocol, listener.Port) == ref.(string) {
 										rowData = listener
 										break
 									}
 								}
+							case *service.ListenerAttributes:
+								// Settings are copied with the whole listener
+								rowData = items
 							case []service.VServerGroupDetail:
 								for _, vsg := range items {
 									if vsg.VServerGroupId == ref.(string) {
@@ -891,6 +1179,56 @@ func (a *App) setupTableYankFunctionality(table *tview.Table, data interface{})
 										break
 									}
 								}
+							case []rds.DBInstanceNetInfo:
+								for _, info := range items {
+									if info.ConnectionString == ref.(string) {
+										rowData = info
+										break
+									}
+								}
+							case []rds.DBInstanceIPArray:
+								for _, group := range items {
+									if ui.RdsWhitelistReference(group) == ref.(string) {
+										rowData = group
+										break
+									}
+								}
+							case []service.RDSParameter:
+								for _, parameter := range items {
+									if parameter.Name == ref.(string) {
+										rowData = parameter
+										break
+									}
+								}
+							case []rds.Backup:
+								for _, backup := range items {
+									if backup.BackupId == ref.(string) {
+										rowData = backup
+										break
+									}
+								}
+							case []service.SecurityGroupFinding:
+								for _, finding := range items {
+									if ui.SecurityGroupFindingReference(finding) == ref.(string) {
+										rowData = finding
+										break
+									}
+								}
+							case []service.ReachabilityRule:
+								if index, err := strconv.Atoi(ref.(string)); err == nil && index < len(items) {
+									rowData = items[index]
+								}
+							case []service.SecurityGroupRuleDiff:
+								if index, err := strconv.Atoi(ref.(string)); err == nil && index < len(items) {
+									rowData = items[index]
+								}
+							case []rds.SQLSlowLog:
+								for _, log := range items {
+									if ui.RdsSlowLogReference(log) == ref.(string) {
+										rowData = log
+										break
+									}
+								}
 							case []r_kvstore.KVStoreInstance:
 								for _, inst := range items {
 									if inst.InstanceId == ref.(string) {
@@ -958,12 +1296,12 @@ func (a *App) goToNextOssPage(nextMarker string) {
 		return
 	}
 
-	// Save current marker to previous markers stack
-	a.ossPreviousMarkers = append(a.ossPreviousMarkers, a.ossCurrentMarker)
-	a.ossCurrentMarker = nextMarker
-	a.ossCurrentPage++
-
-	a.loadOssObjectPage()
+	a.loadOssListing(a.ossCurrentPrefix, nextMarker, func() {
+		// Save current marker to previous markers stack
+		a.ossPreviousMarkers = append(a.ossPreviousMarkers, a.ossCurrentMarker)
+		a.ossCurrentMarker = nextMarker
+		a.ossCurrentPage++
+	})
 }
 
 // goToPrevOssPage navigates to the previous page
@@ -972,22 +1310,70 @@ func (a *App) goToPrevOssPage() {
 		return
 	}
 
-	// Pop the last marker from the stack
 	lastIndex := len(a.ossPreviousMarkers) - 1
-	a.ossCurrentMarker = a.ossPreviousMarkers[lastIndex]
-	a.ossPreviousMarkers = a.ossPreviousMarkers[:lastIndex]
-	a.ossCurrentPage--
+	a.loadOssListing(a.ossCurrentPrefix, a.ossPreviousMarkers[lastIndex], func() {
+		// Pop the last marker from the stack
+		a.ossCurrentMarker = a.ossPreviousMarkers[lastIndex]
+		a.ossPreviousMarkers = a.ossPreviousMarkers[:lastIndex]
+		a.ossCurrentPage--
+	})
+}
 
-	a.loadOssObjectPage()
+// ossFolderState is where the object list was in a folder before entering one of its subfolders
+type ossFolderState struct {
+	prefix          string
+	marker          string
+	previousMarkers []string
+	page            int
+	selectedKey     string // The subfolder that was entered
+}
+
+// enterOssFolder lists the objects and subfolders of folder, starting on its first page.
+// The listing of the current folder is remembered so that leaving returns to the same page.
+func (a *App) enterOssFolder(folder string) {
+	parent := ossFolderState{
+		prefix:          a.ossCurrentPrefix,
+		marker:          a.ossCurrentMarker,
+		previousMarkers: a.ossPreviousMarkers,
+		page:            a.ossCurrentPage,
+		selectedKey:     folder,
+	}
+	a.loadOssListing(folder, "", func() {
+		a.ossFolderStack = append(a.ossFolderStack, parent)
+		a.ossCurrentPrefix = folder
+		a.ossCurrentMarker = ""
+		a.ossPreviousMarkers = []string{}
+		a.ossCurrentPage = 1
+	})
+}
+
+// leaveOssFolder returns to the parent folder with the folder just left selected. It
+// reports false at the top of the bucket.
+func (a *App) leaveOssFolder() bool {
+	if len(a.ossFolderStack) == 0 {
+		return false
+	}
+
+	lastIndex := len(a.ossFolderStack) - 1
+	state := a.ossFolderStack[lastIndex]
+	a.loadOssListing(state.prefix, state.marker, func() {
+		a.ossFolderStack = a.ossFolderStack[:lastIndex]
+		a.ossCurrentPrefix = state.prefix
+		a.ossCurrentMarker = state.marker
+		a.ossPreviousMarkers = state.previousMarkers
+		a.ossCurrentPage = state.page
+		a.ossSelectKey = state.selectedKey
+	})
+	return true
 }
 
 // goToFirstOssPage navigates to the first page
 func (a *App) goToFirstOssPage() {
-	a.ossCurrentMarker = ""
-	a.ossPreviousMarkers = []string{}
-	a.ossCurrentPage = 1
-
-	a.loadOssObjectPage()
+	a.loadOssListing(a.ossCurrentPrefix, "", func() {
+		a.ossCurrentMarker = ""
+		a.ossPreviousMarkers = []string{}
+		a.ossCurrentPage = 1
+	})
 }
 
 // showProfileSelectionDialog shows the profile selection dialog
@@ -1016,6 +1402,8 @@ func (a *App) showProfileSelectionDialog() {
 
 // switchToProfile switches to the selected profile and reinitializes the application
 func (a *App) switchToProfile(profileName string) {
+	a.cancelLoad()
+
 	if profileName == a.currentProfile {
 		// Same profile, just restore focus
 		_, prim := a.pages.GetFrontPage()
@@ -1045,16 +1433,12 @@ func (a *App) switchToProfile(profileName string) {
 		a.showErrorModal(fmt.Sprintf("Failed to load new configuration: %v", err))
 		return
 	}
-
-	// Create new clients with the new configuration
-	clientConfig := &client.Config{
-		AccessKeyID:     cfg.AccessKeyID,
-		AccessKeySecret: cfg.AccessKeySecret,
-		RegionID:        cfg.RegionID,
-		OssEndpoint:     cfg.OssEndpoint,
+	if a.forceReadOnly {
+		cfg.SetReadOnly()
 	}
 
-	newClients, err := client.NewAliyunClients(clientConfig)
+	// Create new clients with the new configuration
+	newClients, err := client.NewAliyunClients(client.NewConfig(cfg))
 	if err != nil {
 		// Rollback profile change
 		config.SwitchProfile(originalProfile)
@@ -1062,24 +1446,18 @@ func (a *App) switchToProfile(profileName string) {
 		return
 	}
 
-	// Create new services with the new clients
-	newServices := &Services{
-		ECS:      service.NewECSService(newClients.ECS),
-		DNS:      service.NewDNSService(newClients.DNS),
-		SLB:      service.NewSLBService(newClients.SLB),
-		RDS:      service.NewRDSService(newClients.RDS),
-		OSS:      service.NewOSSServiceWithCredentials(newClients.OSS, cfg.AccessKeyID, cfg.AccessKeySecret, cfg.OssEndpoint),
-		Redis:    service.NewRedisService(newClients.Redis),
-		RocketMQ: service.NewRocketMQService(newClients.RocketMQ),
-	}
-
-	// Update application state
+	// Update application state with services built on the new clients
 	a.clients = newClients
-	a.services = newServices
+	a.services = NewServices(newClients, cfg)
 	a.currentProfile = profileName
+	a.currentRegion = cfg.RegionID
+	a.allRegionsMode = false
+	a.allRegions = nil
+	a.regionPool = newRegionPool(profileName, a.forceReadOnly)
+	a.production = cfg.Production
 
 	// Update mode line
-	ui.UpdateModeLine(a.modeLine, a.currentProfile)
+	ui.UpdateModeLine(a.modeLine, a.modeLineContext())
 
 	// Clear cached data to force reload with new profile
 	a.clearCachedData()
@@ -1102,12 +1480,38 @@ func (a *App) clearCachedData() {
 	a.allRedisInstances = nil
 	a.allRocketMQInstances = nil
 	a.allOssBuckets = nil
+	a.snapshotTimes = make(map[string]time.Time)
 	a.currentBucketName = ""
+	a.currentDomainName = ""
+	a.currentDnsRecords = nil
+	a.currentSecurityGroupRules = nil
+	a.sgAuditTable = nil
+	a.sgAuditFindings = nil
+	a.reachTable = nil
+	a.reachResult = nil
+	a.sgDiffTable = nil
+	a.sgDiffLeft, a.sgDiffRight, a.sgDiffLines = nil, nil, nil
+	a.sgReturnPage, a.sgReturnFocus = "", nil
 	a.currentRdsInstanceId = ""
 	a.currentRedisInstanceId = ""
 	a.currentRocketMQInstanceId = ""
+	a.resourceRegions = nil
+	a.regionWarnings = nil
 
 	// Reset OSS pagination state
+	a.ossCurrentPrefix = ""
+	a.ossCurrentObjects = nil
+	a.ossFolderStack = nil
+	a.ossSelectKey = ""
+	a.stopOssSearch()
+	a.stopOssUsage()
+	a.ossSearchTable = nil
+	a.ossSearchResults = nil
+	a.ossSearchValues = nil
+	a.ossSearchBucket = ""
+	a.ossVersionsTable = nil
+	a.ossVersions = nil
+	a.ossVersionsRestored = false
 	a.ossCurrentMarker = ""
 	a.ossPreviousMarkers = []string{}
 	a.ossCurrentPage = 0
@@ -1115,17 +1519,26 @@ func (a *App) clearCachedData() {
 	a.ossHasNextPage = false
 }
 
+// reloadRdsListView fetches the RDS instances and shows them
+func (a *App) reloadRdsListView() {
+	loadRegional(a, "RDS instances",
+		func(ctx context.Context, s *Services) ([]rds.DBInstance, error) { return s.RDS.FetchInstances(ctx) },
+		func(inst rds.DBInstance) string { return inst.DBInstanceId },
+		func(instances []rds.DBInstance) {
+			a.allRDSInstances = instances
+			saveSnapshot(a, ui.PageRdsList, a.allRDSInstances)
+			a.switchToRdsListView()
+		})
+}
+
 // switchToRdsListView switches to RDS list view
 func (a *App) switchToRdsListView() {
-	if a.allRDSInstances == nil {
-		instances, err := a.services.RDS.FetchInstances()
-		if err != nil {
-			a.showErrorModal(fmt.Sprintf("Failed to fetch RDS instances: %v", err))
-			return
-		}
-		a.allRDSInstances = instances
+	if a.allRDSInstances == nil && !restoreSnapshot(a, ui.PageRdsList, &a.allRDSInstances) {
+		a.reloadRdsListView()
+		return
 	}
 	a.rdsInstanceTable = ui.CreateRdsListView(a.allRDSInstances)
+	a.insertRegionColumn(a.rdsInstanceTable)
 	ui.SetupTableNavigationWithSearch(a.rdsInstanceTable, a, func(row, col int) {
 		cell := a.rdsInstanceTable.GetCell(row, 0)
 		instanceId, ok := cell.GetReference().(string)
@@ -1172,20 +1585,22 @@ func (a *App) switchToRdsListView() {
 		}
 
 		// Update mode line with shortcuts for RDS detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRdsDetail)
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRdsDetail)
 
 		a.tviewApp.SetFocus(a.rdsDetailView)
 	})
 
 	a.setupTableYankFunctionality(a.rdsInstanceTable, a.allRDSInstances)
+	a.setupTableRefresh(ui.PageRdsList, a.rdsInstanceTable, a.reloadRdsListView)
 	a.setupRdsKeyHandlers(a.rdsInstanceTable)
 	rdsListFlex := ui.WrapTableInFlex(a.rdsInstanceTable)
 	a.pages.AddPage(ui.PageRdsList, rdsListFlex, true, true)
 
 	// Update mode line with shortcuts for RDS list page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRdsList)
+	a.updateListModeLine(ui.PageRdsList)
 
 	a.tviewApp.SetFocus(a.rdsInstanceTable)
+	a.showRegionWarnings()
 }
 
 // setupRdsKeyHandlers sets up key handlers for RDS specific actions
@@ -1214,6 +1629,36 @@ func (a *App) setupRdsKeyHandlers(table *tview.Table) {
 				}
 			}
 			return nil
+		case 'C': // Connection strings and ports
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsNetInfoView(instanceId)
+			}
+			return nil
+		case 'W': // IP whitelists
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsWhitelistView(instanceId)
+			}
+			return nil
+		case 'P': // Parameters
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsParametersView(instanceId)
+			}
+			return nil
+		case 'B': // Backups and backup policy
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsBackupsView(instanceId)
+			}
+			return nil
+		case 'o': // Read-only instances
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsReadOnlyView(instanceId)
+			}
+			return nil
+		case 'L': // Slow query statistics
+			if instanceId, ok := ui.SelectedReference(table); ok {
+				a.switchToRdsSlowLogsView(instanceId)
+			}
+			return nil
 		}
 
 		// Call original input capture if it exists
@@ -1224,14 +1669,19 @@ func (a *App) setupRdsKeyHandlers(table *tview.Table) {
 	})
 }
 
-// switchToRdsDatabasesView switches to RDS databases view
+// switchToRdsDatabasesView loads the databases of an instance and shows them
 func (a *App) switchToRdsDatabasesView(instanceId string) {
-	databases, err := a.services.RDS.FetchDatabases(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch databases for instance %s: %v", instanceId, err))
-		return
-	}
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("databases for instance %s", instanceId),
+		func(ctx context.Context) ([]rds.Database, error) {
+			return services.RDS.FetchDatabases(ctx, instanceId)
+		}, func(databases []rds.Database) {
+			a.showRdsDatabasesView(instanceId, databases)
+		})
+}
 
+// showRdsDatabasesView switches to RDS databases view
+func (a *App) showRdsDatabasesView(instanceId string, databases []rds.Database) {
 	a.currentRdsInstanceId = instanceId
 	a.rdsDatabaseTable = ui.CreateRdsDatabasesListView(databases, instanceId)
 
@@ -1274,29 +1724,37 @@ func (a *App) switchToRdsDatabasesView(instanceId string) {
 		a.pages.AddPage("rdsDatabaseDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for RDS database detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "rdsDatabaseDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "rdsDatabaseDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.rdsDatabaseTable, databases)
+	a.setupTableRefresh(ui.PageRdsDatabases, a.rdsDatabaseTable, func() {
+		a.switchToRdsDatabasesView(instanceId)
+	})
 	rdsDatabaseListFlex := ui.WrapTableInFlex(a.rdsDatabaseTable)
 	a.pages.AddPage(ui.PageRdsDatabases, rdsDatabaseListFlex, true, true)
 
 	// Update mode line with shortcuts for RDS databases page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRdsDatabases)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRdsDatabases)
 
 	a.tviewApp.SetFocus(a.rdsDatabaseTable)
 }
 
-// switchToRdsAccountsView switches to RDS accounts view
+// switchToRdsAccountsView loads the accounts of an instance and shows them
 func (a *App) switchToRdsAccountsView(instanceId string) {
-	accounts, err := a.services.RDS.FetchAccounts(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch accounts for instance %s: %v", instanceId, err))
-		return
-	}
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("accounts for instance %s", instanceId),
+		func(ctx context.Context) ([]rds.DBInstanceAccount, error) {
+			return services.RDS.FetchAccounts(ctx, instanceId)
+		}, func(accounts []rds.DBInstanceAccount) {
+			a.showRdsAccountsView(instanceId, accounts)
+		})
+}
 
+// showRdsAccountsView switches to RDS accounts view
+func (a *App) showRdsAccountsView(instanceId string, accounts []rds.DBInstanceAccount) {
 	a.currentRdsInstanceId = instanceId
 	a.rdsAccountTable = ui.CreateRdsAccountsListView(accounts, instanceId)
 
@@ -1339,30 +1797,47 @@ func (a *App) switchToRdsAccountsView(instanceId string) {
 		a.pages.AddPage("rdsAccountDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for RDS account detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "rdsAccountDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "rdsAccountDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.rdsAccountTable, accounts)
+	a.setupTableRefresh(ui.PageRdsAccounts, a.rdsAccountTable, func() {
+		a.switchToRdsAccountsView(instanceId)
+	})
 	rdsAccountListFlex := ui.WrapTableInFlex(a.rdsAccountTable)
 	a.pages.AddPage(ui.PageRdsAccounts, rdsAccountListFlex, true, true)
 
 	// Update mode line with shortcuts for RDS accounts page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRdsAccounts)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRdsAccounts)
 
 	a.tviewApp.SetFocus(a.rdsAccountTable)
 }
 
+// reloadRedisListView fetches the Redis instances and shows them
+func (a *App) reloadRedisListView() {
+	loadRegional(a, "Redis instances",
+		func(ctx context.Context, s *Services) ([]r_kvstore.KVStoreInstance, error) {
+			return s.Redis.FetchInstances(ctx)
+		},
+		func(inst r_kvstore.KVStoreInstance) string { return inst.InstanceId },
+		func(instances []r_kvstore.KVStoreInstance) {
+			a.allRedisInstances = instances
+			saveSnapshot(a, ui.PageRedisList, a.allRedisInstances)
+			a.switchToRedisListView()
+		})
+}
+
 // switchToRedisListView switches to Redis list view
 func (a *App) switchToRedisListView() {
-	instances, err := a.services.Redis.FetchInstances()
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch Redis instances: %v", err))
+	if a.allRedisInstances == nil && !restoreSnapshot(a, ui.PageRedisList, &a.allRedisInstances) {
+		a.reloadRedisListView()
 		return
 	}
-	a.allRedisInstances = instances
 
+	// The Redis list already has a Region column, so no column is inserted in the all-regions mode
+	instances := a.allRedisInstances
 	a.redisInstanceTable = ui.CreateRedisListView(instances)
 	searchHandler := ui.SetupTableNavigationWithSearch(a.redisInstanceTable, a, func(row, col int) {
 		instanceId := a.redisInstanceTable.GetCell(row, 0).GetReference().(string)
@@ -1403,21 +1878,23 @@ func (a *App) switchToRedisListView() {
 		a.pages.AddPage("redisDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for Redis detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "redisDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "redisDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.redisInstanceTable, instances)
+	a.setupTableRefresh(ui.PageRedisList, a.redisInstanceTable, a.reloadRedisListView)
 	a.setupRedisKeyHandlers(a.redisInstanceTable, searchHandler)
 
 	redisListFlex := ui.WrapTableInFlex(a.redisInstanceTable)
 	a.pages.AddPage(ui.PageRedisList, redisListFlex, true, true)
 
 	// Update mode line with shortcuts for Redis list page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRedisList)
+	a.updateListModeLine(ui.PageRedisList)
 
 	a.tviewApp.SetFocus(a.redisInstanceTable)
+	a.showRegionWarnings()
 }
 
 // setupRedisKeyHandlers sets up 'A' key for Redis instance list
@@ -1459,14 +1936,19 @@ func (a *App) setupRedisKeyHandlers(table *tview.Table, searchHandler *ui.VimSea
 	})
 }
 
-// switchToRedisAccountsView switches to Redis accounts view for a given instance
+// switchToRedisAccountsView loads the accounts of an instance and shows them
 func (a *App) switchToRedisAccountsView(instanceId string) {
-	accounts, err := a.services.Redis.FetchAccounts(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch accounts for Redis instance %s: %v", instanceId, err))
-		return
-	}
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("accounts for Redis instance %s", instanceId),
+		func(ctx context.Context) ([]r_kvstore.Account, error) {
+			return services.Redis.FetchAccounts(ctx, instanceId)
+		}, func(accounts []r_kvstore.Account) {
+			a.showRedisAccountsView(instanceId, accounts)
+		})
+}
 
+// showRedisAccountsView switches to Redis accounts view for a given instance
+func (a *App) showRedisAccountsView(instanceId string, accounts []r_kvstore.Account) {
 	a.currentRedisInstanceId = instanceId
 	a.redisAccountTable = ui.CreateRedisAccountsListView(accounts, instanceId)
 
@@ -1509,34 +1991,48 @@ func (a *App) switchToRedisAccountsView(instanceId string) {
 		a.pages.AddPage("redisAccountDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for Redis account detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "redisAccountDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "redisAccountDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.redisAccountTable, accounts)
+	a.setupTableRefresh(ui.PageRedisAccounts, a.redisAccountTable, func() {
+		a.switchToRedisAccountsView(instanceId)
+	})
 
 	redisAccountListFlex := ui.WrapTableInFlex(a.redisAccountTable)
 	a.pages.AddPage(ui.PageRedisAccounts, redisAccountListFlex, true, true)
 
 	// Update mode line with shortcuts for Redis accounts page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRedisAccounts)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRedisAccounts)
 
 	a.tviewApp.SetFocus(a.redisAccountTable)
 }
 
+// reloadRocketMQListView fetches the RocketMQ instances and shows them
+func (a *App) reloadRocketMQListView() {
+	loadRegional(a, "RocketMQ instances",
+		func(ctx context.Context, s *Services) ([]service.RocketMQInstance, error) {
+			return s.RocketMQ.FetchInstances(ctx)
+		},
+		func(inst service.RocketMQInstance) string { return inst.InstanceId },
+		func(instances []service.RocketMQInstance) {
+			a.allRocketMQInstances = instances
+			saveSnapshot(a, ui.PageRocketMQList, a.allRocketMQInstances)
+			a.switchToRocketMQListView()
+		})
+}
+
 // switchToRocketMQListView switches to RocketMQ list view
 func (a *App) switchToRocketMQListView() {
-	if a.allRocketMQInstances == nil {
-		instances, err := a.services.RocketMQ.FetchInstances()
-		if err != nil {
-			a.showErrorModal(fmt.Sprintf("Failed to fetch RocketMQ instances: %v", err))
-			return
-		}
-		a.allRocketMQInstances = instances
+	if a.allRocketMQInstances == nil && !restoreSnapshot(a, ui.PageRocketMQList, &a.allRocketMQInstances) {
+		a.reloadRocketMQListView()
+		return
 	}
 
 	a.rocketmqInstanceTable = ui.CreateRocketMQListView(a.allRocketMQInstances)
+	a.insertRegionColumn(a.rocketmqInstanceTable)
 	searchHandler := ui.SetupTableNavigationWithSearch(a.rocketmqInstanceTable, a, func(row, col int) {
 		instanceId := a.rocketmqInstanceTable.GetCell(row, 0).GetReference().(string)
 		var selectedInstance interface{}
@@ -1576,21 +2072,23 @@ func (a *App) switchToRocketMQListView() {
 		a.pages.AddPage("rocketmqDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for RocketMQ detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "rocketmqDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "rocketmqDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.rocketmqInstanceTable, a.allRocketMQInstances)
+	a.setupTableRefresh(ui.PageRocketMQList, a.rocketmqInstanceTable, a.reloadRocketMQListView)
 	a.setupRocketMQKeyHandlers(a.rocketmqInstanceTable, searchHandler)
 
 	rocketmqListFlex := ui.WrapTableInFlex(a.rocketmqInstanceTable)
 	a.pages.AddPage(ui.PageRocketMQList, rocketmqListFlex, true, true)
 
 	// Update mode line with shortcuts for RocketMQ list page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRocketMQList)
+	a.updateListModeLine(ui.PageRocketMQList)
 
 	a.tviewApp.SetFocus(a.rocketmqInstanceTable)
+	a.showRegionWarnings()
 }
 
 // setupRocketMQKeyHandlers sets up 'T' and 'G' keys for RocketMQ instance list
@@ -1641,14 +2139,19 @@ func (a *App) setupRocketMQKeyHandlers(table *tview.Table, searchHandler *ui.Vim
 	})
 }
 
-// switchToRocketMQTopicsView switches to RocketMQ topics view for a given instance
+// switchToRocketMQTopicsView loads the topics of an instance and shows them
 func (a *App) switchToRocketMQTopicsView(instanceId string) {
-	topics, err := a.services.RocketMQ.FetchTopics(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch topics for RocketMQ instance %s: %v", instanceId, err))
-		return
-	}
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("topics for RocketMQ instance %s", instanceId),
+		func(ctx context.Context) ([]service.RocketMQTopic, error) {
+			return services.RocketMQ.FetchTopics(ctx, instanceId)
+		}, func(topics []service.RocketMQTopic) {
+			a.showRocketMQTopicsView(instanceId, topics)
+		})
+}
 
+// showRocketMQTopicsView switches to RocketMQ topics view for a given instance
+func (a *App) showRocketMQTopicsView(instanceId string, topics []service.RocketMQTopic) {
 	a.currentRocketMQInstanceId = instanceId
 	a.rocketmqTopicsTable = ui.CreateRocketMQTopicsListView(topics, instanceId)
 
@@ -1691,30 +2194,38 @@ func (a *App) switchToRocketMQTopicsView(instanceId string) {
 		a.pages.AddPage("rocketmqTopicDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for RocketMQ topic detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "rocketmqTopicDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "rocketmqTopicDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.rocketmqTopicsTable, topics)
+	a.setupTableRefresh(ui.PageRocketMQTopics, a.rocketmqTopicsTable, func() {
+		a.switchToRocketMQTopicsView(instanceId)
+	})
 
 	rocketmqTopicsListFlex := ui.WrapTableInFlex(a.rocketmqTopicsTable)
 	a.pages.AddPage(ui.PageRocketMQTopics, rocketmqTopicsListFlex, true, true)
 
 	// Update mode line with shortcuts for RocketMQ topics page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRocketMQTopics)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRocketMQTopics)
 
 	a.tviewApp.SetFocus(a.rocketmqTopicsTable)
 }
 
-// switchToRocketMQGroupsView switches to RocketMQ groups view for a given instance
+// switchToRocketMQGroupsView loads the groups of an instance and shows them
 func (a *App) switchToRocketMQGroupsView(instanceId string) {
-	groups, err := a.services.RocketMQ.FetchGroups(instanceId)
-	if err != nil {
-		a.showErrorModal(fmt.Sprintf("Failed to fetch groups for RocketMQ instance %s: %v", instanceId, err))
-		return
-	}
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("groups for RocketMQ instance %s", instanceId),
+		func(ctx context.Context) ([]service.RocketMQGroup, error) {
+			return services.RocketMQ.FetchGroups(ctx, instanceId)
+		}, func(groups []service.RocketMQGroup) {
+			a.showRocketMQGroupsView(instanceId, groups)
+		})
+}
 
+// showRocketMQGroupsView switches to RocketMQ groups view for a given instance
+func (a *App) showRocketMQGroupsView(instanceId string, groups []service.RocketMQGroup) {
 	a.currentRocketMQInstanceId = instanceId
 	a.rocketmqGroupsTable = ui.CreateRocketMQGroupsListView(groups, instanceId)
 
@@ -1757,18 +2268,21 @@ func (a *App) switchToRocketMQGroupsView(instanceId string) {
 		a.pages.AddPage("rocketmqGroupDetail", detailViewWithInstructions, true, true)
 
 		// Update mode line with shortcuts for RocketMQ group detail page
-		ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, "rocketmqGroupDetail")
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), "rocketmqGroupDetail")
 
 		a.tviewApp.SetFocus(detailView)
 	})
 
 	a.setupTableYankFunctionality(a.rocketmqGroupsTable, groups)
+	a.setupTableRefresh(ui.PageRocketMQGroups, a.rocketmqGroupsTable, func() {
+		a.switchToRocketMQGroupsView(instanceId)
+	})
 
 	rocketmqGroupsListFlex := ui.WrapTableInFlex(a.rocketmqGroupsTable)
 	a.pages.AddPage(ui.PageRocketMQGroups, rocketmqGroupsListFlex, true, true)
 
 	// Update mode line with shortcuts for RocketMQ groups page
-	ui.UpdateModeLineWithShortcuts(a.modeLine, a.currentProfile, ui.PageRocketMQGroups)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRocketMQGroups)
 
 	a.tviewApp.SetFocus(a.rocketmqGroupsTable)
 }
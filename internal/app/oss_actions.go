@@ -0,0 +1,394 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// ossPreviewLimit is how much of an object the inline preview reads
+const ossPreviewLimit = 64 << 10
+
+// defaultSignedURLExpiry is the validity offered for a presigned URL
+const defaultSignedURLExpiry = "24h"
+
+// transferUpdateInterval limits how often the progress of a transfer is redrawn
+const transferUpdateInterval = 100 * time.Millisecond
+
+// setupOssObjectKeyHandlers sets up key handlers for previewing, downloading and uploading objects
+func (a *App) setupOssObjectKeyHandlers(table *tview.Table) {
+	originalInputCapture := table.GetInputCapture()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'P': // Preview the beginning of the selected object
+			if object, ok := a.selectedOssObject(table); ok {
+				a.previewOssObject(object)
+			}
+			return nil
+		case 'U': // Upload a local file into the current prefix
+			a.showUploadOssObjectDialog(table)
+			return nil
+		case 'F': // Find objects anywhere in the bucket
+			a.showOssSearchDialog(table)
+			return nil
+		case 'u': // Show what takes up the space of the current folder
+			a.showOssUsage(a.currentBucketName, a.ossCurrentPrefix, ui.PageOssObjects, table)
+			return nil
+		case 'H': // Show the versions of an object, also of a deleted one
+			a.showOssVersionsDialog(table)
+			return nil
+		}
+		if object, ok := a.selectedOssObject(table); ok && a.handleOssObjectKey(event, table, object) {
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// handleOssObjectKey handles the keys that act on one object in any list of objects, and
+// reports whether the key was one of them
+func (a *App) handleOssObjectKey(event *tcell.EventKey, table *tview.Table, object oss.ObjectProperties) bool {
+	switch event.Rune() {
+	case 'V': // Stream the object into the pager
+		a.pageOssObject(a.currentBucketName, object.Key)
+	case 'D': // Download the object
+		a.showDownloadOssObjectDialog(table, object)
+	case 'S': // Share the object with a presigned URL
+		a.showSignOssObjectDialog(table, object)
+	default:
+		return false
+	}
+	return true
+}
+
+// selectedOssObject returns the object of the selected row of the object list
+func (a *App) selectedOssObject(table *tview.Table) (oss.ObjectProperties, bool) {
+	objectKey, ok := ui.SelectedReference(table)
+	if !ok {
+		return oss.ObjectProperties{}, false
+	}
+	for _, object := range a.ossCurrentObjects {
+		if object.Key == objectKey {
+			return object, true
+		}
+	}
+	return oss.ObjectProperties{}, false
+}
+
+// previewOssObject shows the first ossPreviewLimit bytes of an object if it is text
+func (a *App) previewOssObject(object oss.ObjectProperties) {
+	services := a.services
+	bucketName := a.currentBucketName
+	loadAsync(a, fmt.Sprintf("preview of %s", object.Key), func(ctx context.Context) ([]byte, error) {
+		body, err := services.OSS.OpenObject(ctx, bucketName, object.Key, ossPreviewLimit)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(io.LimitReader(body, ossPreviewLimit))
+	}, func(content []byte) {
+		if !isText(content) {
+			a.showErrorModal(fmt.Sprintf("%s looks like a binary object (%s). Download it with D instead.",
+				object.Key, ui.FormatBytes(object.Size)))
+			return
+		}
+
+		title := fmt.Sprintf("Preview: %s", object.Key)
+		if int64(len(content)) < object.Size {
+			title = fmt.Sprintf("Preview: %s (first %s of %s)", object.Key, ui.FormatBytes(int64(len(content))), ui.FormatBytes(object.Size))
+		}
+		view := ui.CreateOssObjectPreviewView(title, string(content))
+		view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Rune() == 'v' { // The whole object, in the pager
+				a.pageOssObject(bucketName, object.Key)
+				return nil
+			}
+			return event
+		})
+
+		a.pages.AddPage(ui.PageOssObjectPreview, view, true, true)
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssObjectPreview)
+		a.tviewApp.SetFocus(view)
+	})
+}
+
+// isText reports whether content looks like text: valid UTF-8 without NUL bytes. A rune cut
+// off at the end of a partial read is allowed.
+func isText(content []byte) bool {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < utf8.UTFMax && len(content) > 0; i++ {
+		if utf8.Valid(content) {
+			return true
+		}
+		content = content[:len(content)-1]
+	}
+	return utf8.Valid(content)
+}
+
+// pageOssObject streams a whole object into the configured pager. The body is opened with
+// the load's context, which loadAsync keeps valid until the pager has quit.
+func (a *App) pageOssObject(bucketName, objectKey string) {
+	services := a.services
+	loadAsync(a, fmt.Sprintf("content of %s", objectKey), func(ctx context.Context) (io.ReadCloser, error) {
+		return services.OSS.OpenObject(ctx, bucketName, objectKey, 0)
+	}, func(body io.ReadCloser) {
+		defer body.Close()
+		if err := ui.PipeToPager(body, a.tviewApp); err != nil {
+			a.showErrorModal(fmt.Sprintf("Failed to open pager: %v", err))
+		}
+	})
+}
+
+// showDownloadOssObjectDialog asks where to download an object
+func (a *App) showDownloadOssObjectDialog(table *tview.Table, object oss.ObjectProperties) {
+	bucketName := a.currentBucketName
+	a.askOssDownloadPath(table, fmt.Sprintf("Download %s (%s)", object.Key, ui.FormatBytes(object.Size)), object.Key,
+		func(filePath string) { a.downloadOssObject(table, bucketName, object, filePath) })
+}
+
+// askOssDownloadPath asks for the local path to download an object to. A directory keeps
+// the object's file name; an existing file is only replaced after confirmation, unless it
+// is an interrupted download being resumed.
+func (a *App) askOssDownloadPath(table *tview.Table, title, objectKey string, download func(filePath string)) {
+	ui.ShowFormDialog(a.pages, a.tviewApp, title,
+		[]string{"Local path"}, []string{path.Base(objectKey)},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			filePath := strings.TrimSpace(values[0])
+			if filePath == "" {
+				return
+			}
+			if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+				filePath = filepath.Join(filePath, path.Base(objectKey))
+			}
+
+			_, err := os.Stat(filePath)
+			_, cpErr := os.Stat(filePath + ".cp")
+			if err == nil && cpErr != nil {
+				ui.ShowConfirmModal(a.pages, a.tviewApp, fmt.Sprintf("%s already exists. Replace it?", filePath), []string{"Replace"},
+					func(string) {
+						a.tviewApp.SetFocus(table)
+						download(filePath)
+					},
+					func() { a.tviewApp.SetFocus(table) })
+				return
+			}
+			download(filePath)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// downloadOssObject downloads an object with a progress dialog
+func (a *App) downloadOssObject(table *tview.Table, bucketName string, object oss.ObjectProperties, filePath string) {
+	services := a.services
+	a.runTransfer(fmt.Sprintf("Downloading %s", object.Key), table,
+		func(ctx context.Context, progress service.TransferProgress) error {
+			return services.OSS.DownloadObject(ctx, bucketName, object.Key, filePath, progress)
+		},
+		func(err error) {
+			switch {
+			case errors.Is(err, context.Canceled):
+				a.showErrorModal(fmt.Sprintf("Download of %s cancelled. Downloading it to %s again resumes it.", object.Key, filePath))
+			case err != nil:
+				a.showErrorModal(fmt.Sprintf("Failed to download: %v", err))
+			default:
+				ui.ShowMessageModal(a.pages, a.tviewApp,
+					fmt.Sprintf("Downloaded oss://%s/%s to %s (%s)", bucketName, object.Key, filePath, ui.FormatBytes(object.Size)),
+					func() { a.tviewApp.SetFocus(table) })
+			}
+		})
+}
+
+// showUploadOssObjectDialog asks for a local file to upload into the current prefix of the
+// bucket. Replacing an existing object is confirmed like a destructive action.
+func (a *App) showUploadOssObjectDialog(table *tview.Table) {
+	if !a.checkWritable() {
+		return
+	}
+	bucketName := a.currentBucketName
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Upload to oss://%s/", bucketName),
+		[]string{"Local file", "Prefix"}, []string{"", a.ossCurrentPrefix},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			filePath, prefix := strings.TrimSpace(values[0]), strings.TrimSpace(values[1])
+			if filePath == "" {
+				return
+			}
+			info, err := os.Stat(filePath)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Cannot upload: %v", err))
+				return
+			}
+			if info.IsDir() {
+				a.showErrorModal(fmt.Sprintf("Cannot upload %s: it is a directory", filePath))
+				return
+			}
+			if prefix != "" && !strings.HasSuffix(prefix, "/") {
+				prefix += "/"
+			}
+			objectKey := prefix + filepath.Base(filePath)
+
+			// The listing shows one folder and page at most, so ask OSS whether the key exists
+			services := a.services
+			loadAsync(a, "oss://"+bucketName+"/"+objectKey, func(ctx context.Context) (*oss.ObjectProperties, error) {
+				return services.OSS.StatObject(ctx, bucketName, objectKey)
+			}, func(existing *oss.ObjectProperties) {
+				message := fmt.Sprintf("Upload %s (%s) to oss://%s/%s?", filePath, ui.FormatBytes(info.Size()), bucketName, objectKey)
+				upload := func(string) { a.uploadOssObject(table, bucketName, objectKey, filePath) }
+				if existing != nil {
+					message += fmt.Sprintf("\n\nThe existing object (%s, modified %s) is replaced.",
+						ui.FormatBytes(existing.Size), existing.LastModified.Format("2006-01-02 15:04:05"))
+					a.confirmDestructive(table, message, path.Base(objectKey), []string{"Upload"}, upload)
+					return
+				}
+				ui.ShowConfirmModal(a.pages, a.tviewApp, message, []string{"Upload"},
+					func(action string) {
+						a.tviewApp.SetFocus(table)
+						upload(action)
+					},
+					func() { a.tviewApp.SetFocus(table) })
+			})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// uploadOssObject uploads a file with a progress dialog and refreshes the object list
+func (a *App) uploadOssObject(table *tview.Table, bucketName, objectKey, filePath string) {
+	services := a.services
+	a.runTransfer(fmt.Sprintf("Uploading %s", filePath), table,
+		func(ctx context.Context, progress service.TransferProgress) error {
+			return services.OSS.UploadObject(ctx, bucketName, objectKey, filePath, progress)
+		},
+		func(err error) {
+			switch {
+			case errors.Is(err, context.Canceled):
+				a.showErrorModal(fmt.Sprintf("Upload of %s cancelled. Uploading it to the same key again resumes it.", filePath))
+			case err != nil:
+				a.showErrorModal(fmt.Sprintf("Failed to upload: %v", err))
+			default:
+				if page, _ := a.pages.GetFrontPage(); page == ui.PageOssObjects && a.currentBucketName == bucketName {
+					a.refreshPage(ui.PageOssObjects)
+				}
+			}
+		})
+}
+
+// showSignOssObjectDialog asks how long a presigned GET URL for an object stays valid,
+// then copies the URL to the clipboard and shows it
+func (a *App) showSignOssObjectDialog(table *tview.Table, object oss.ObjectProperties) {
+	services := a.services
+	bucketName := a.currentBucketName
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Presigned URL for %s", object.Key),
+		[]string{"Expires in (e.g. 30m, 24h, 7d)"}, []string{defaultSignedURLExpiry},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			expiry, err := parseExpiry(strings.TrimSpace(values[0]))
+			if err != nil {
+				a.showErrorModal(err.Error())
+				return
+			}
+			loadAsync(a, fmt.Sprintf("presigned URL for %s", object.Key), func(ctx context.Context) (*service.SignedURL, error) {
+				return services.OSS.SignObjectURL(ctx, bucketName, object.Key, expiry)
+			}, func(signed *service.SignedURL) {
+				deadline := "until " + signed.Expires.Format("2006-01-02 15:04:05")
+				if signed.Temporary {
+					deadline = fmt.Sprintf("until %s at the latest: it is signed with temporary credentials and stops working as soon as they expire, which may be much sooner",
+						signed.Expires.Format("2006-01-02 15:04:05"))
+				}
+				message := fmt.Sprintf("URL copied to the clipboard. Anyone holding it can download oss://%s/%s %s.\n\n%s",
+					bucketName, object.Key, deadline, signed.URL)
+				if err := ui.CopyToClipboard(signed.URL); err != nil {
+					message = fmt.Sprintf("Failed to copy the URL (%v). It is valid %s:\n\n%s", err, deadline, signed.URL)
+				}
+				ui.ShowMessageModal(a.pages, a.tviewApp, message, func() { a.tviewApp.SetFocus(table) })
+			})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// parseExpiry parses the validity of a presigned URL: a duration like "90m" or "24h", or a
+// number of days like "7d"
+func parseExpiry(text string) (time.Duration, error) {
+	var expiry time.Duration
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid expiry %q: %w", text, err)
+		}
+		expiry = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid expiry %q: %w", text, err)
+		}
+		expiry = d
+	}
+	if expiry < time.Second {
+		return 0, fmt.Errorf("invalid expiry %q: must be at least one second", text)
+	}
+	return expiry, nil
+}
+
+// runTransfer runs a download or upload on a background goroutine behind a progress dialog
+// whose Cancel button aborts it. onDone is called on the UI goroutine once the transfer
+// ends, with context.Canceled if it was cancelled; the focus then returns to focusAfter.
+// Like a write, a transfer is not cancelled by navigating away.
+func (a *App) runTransfer(title string, focusAfter tview.Primitive, transfer func(ctx context.Context, progress service.TransferProgress) error, onDone func(err error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dialog := ui.ShowProgressDialog(a.pages, a.tviewApp, title, cancel)
+
+	var mu sync.Mutex
+	var lastUpdate time.Time
+	progress := func(transferred, total int64) {
+		mu.Lock()
+		now := time.Now()
+		due := transferred >= total || now.Sub(lastUpdate) >= transferUpdateInterval
+		if due {
+			lastUpdate = now
+		}
+		mu.Unlock()
+		if due {
+			a.tviewApp.QueueUpdateDraw(func() { dialog.Update(transferred, total) })
+		}
+	}
+
+	go func() {
+		err := transfer(ctx, progress)
+		if ctx.Err() != nil {
+			err = context.Canceled
+		}
+		cancel()
+		a.tviewApp.QueueUpdateDraw(func() {
+			dialog.Close()
+			a.tviewApp.SetFocus(focusAfter)
+			onDone(err)
+		})
+	}()
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// setupOssBucketKeyHandlers sets up key handlers for bucket specific actions
+func (a *App) setupOssBucketKeyHandlers(table *tview.Table) {
+	originalInputCapture := table.GetInputCapture()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'I': // Inspect the configuration of the selected bucket
+			if bucketName, ok := ui.SelectedReference(table); ok {
+				a.showOssBucketConfig(bucketName)
+			}
+			return nil
+		case 'u': // Show what takes up the space of the selected bucket
+			if bucketName, ok := ui.SelectedReference(table); ok {
+				a.showOssUsage(bucketName, "", ui.PageOssBuckets, table)
+			}
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// showOssBucketConfig loads the configuration of a bucket and shows it as one JSON
+// document with a section per API call
+func (a *App) showOssBucketConfig(bucketName string) {
+	services := a.services
+	loadAsync(a, fmt.Sprintf("configuration of bucket %s", bucketName),
+		func(ctx context.Context) (*service.BucketConfig, error) {
+			return services.OSS.FetchBucketConfig(ctx, bucketName)
+		},
+		func(config *service.BucketConfig) {
+			a.currentDetailData = config
+			title := fmt.Sprintf("Bucket Configuration: %s", bucketName)
+			if len(config.Errors) > 0 {
+				unreadable := slices.Sorted(maps.Keys(config.Errors))
+				title = fmt.Sprintf("Bucket Configuration: %s (unreadable: %s)", bucketName, strings.Join(unreadable, ", "))
+			}
+			detailView, _ := ui.CreateInteractiveJSONDetailViewWithSearch(
+				title,
+				config,
+				a,
+				func() {
+					err := ui.CopyToClipboard(config)
+					if err != nil {
+						a.showErrorModal(fmt.Sprintf("Copy failed: %v", err))
+					} else {
+						a.showErrorModal("Copied!")
+					}
+				},
+				func() {
+					err := ui.OpenInEditor(config, a.tviewApp)
+					if err != nil {
+						a.showErrorModal(fmt.Sprintf("Edit failed: %v", err))
+					}
+				},
+				func() {
+					err := ui.OpenInPager(config, a.tviewApp)
+					if err != nil {
+						a.showErrorModal(fmt.Sprintf("Failed to open pager: %v", err))
+					}
+				},
+			)
+			originalInputCapture := detailView.GetInputCapture()
+			detailView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Rune() == 'r' { // Read the configuration again
+					a.showOssBucketConfig(bucketName)
+					return nil
+				}
+				if originalInputCapture != nil {
+					return originalInputCapture(event)
+				}
+				return event
+			})
+
+			a.pages.AddPage(ui.PageOssBucketConfig, ui.CreateDetailViewWithInstructions(detailView), true, true)
+			ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssBucketConfig)
+			a.tviewApp.SetFocus(detailView)
+		})
+}
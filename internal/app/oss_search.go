@@ -0,0 +1,218 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// ossSearchFields are the fields of the search dialog, in the order of ossSearchValues
+var ossSearchFields = []string{
+	"Prefix",
+	"Key (glob or re:regexp, whole key)",
+	"Min size (e.g. 1G)",
+	"Max size",
+	"Modified from (YYYY-MM-DD[ HH:MM])",
+	"Modified before",
+	"Storage class",
+}
+
+// ossSearchMaxResults stops a search that matches more objects than a table can usefully show
+const ossSearchMaxResults = 10000
+
+// ossSearchTimeLayouts are the accepted formats of the modification times of a search, in
+// local time
+var ossSearchTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// showOssSearchDialog asks for the filters of a bucket-wide search, starting from the last
+// search of the bucket or, for a new one, the current prefix
+func (a *App) showOssSearchDialog(focusAfter tview.Primitive) {
+	values := a.ossSearchValues
+	if len(values) != len(ossSearchFields) || a.ossSearchBucket != a.currentBucketName {
+		values = make([]string, len(ossSearchFields))
+		values[0] = a.ossCurrentPrefix
+	}
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Find objects in %s", a.currentBucketName),
+		ossSearchFields, values,
+		func(values []string) {
+			a.tviewApp.SetFocus(focusAfter)
+			a.ossSearchValues = values
+			a.ossSearchBucket = a.currentBucketName
+			filter, err := parseOssSearchFilter(values[1:])
+			if err != nil {
+				a.showErrorModal(err.Error())
+				return
+			}
+			a.startOssSearch(a.currentBucketName, strings.TrimSpace(values[0]), filter)
+		},
+		func() { a.tviewApp.SetFocus(focusAfter) })
+}
+
+// parseOssSearchFilter parses the filter fields of the search dialog
+func parseOssSearchFilter(values []string) (service.ObjectFilter, error) {
+	var filter service.ObjectFilter
+	var err error
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	if filter.Key, err = service.ParseKeyPattern(values[0]); err != nil {
+		return filter, err
+	}
+	if values[1] != "" {
+		if filter.MinSize, err = ui.ParseBytes(values[1]); err != nil {
+			return filter, err
+		}
+	}
+	if values[2] != "" {
+		if filter.MaxSize, err = ui.ParseBytes(values[2]); err != nil {
+			return filter, err
+		}
+	}
+	if filter.ModifiedFrom, err = parseOssSearchTime(values[3]); err != nil {
+		return filter, err
+	}
+	if filter.ModifiedBefore, err = parseOssSearchTime(values[4]); err != nil {
+		return filter, err
+	}
+	filter.StorageClass = values[5]
+	return filter, nil
+}
+
+// parseOssSearchTime parses a modification time of the search dialog; empty is the zero time
+func parseOssSearchTime(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range ossSearchTimeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected YYYY-MM-DD or YYYY-MM-DD HH:MM", text)
+}
+
+// startOssSearch lists every object under prefix in the background and adds the ones
+// passing filter to the search results page as they are found. The search runs until it
+// is done, stopped with c, or the results page is left.
+func (a *App) startOssSearch(bucketName, prefix string, filter service.ObjectFilter) {
+	a.stopOssSearch()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.ossSearchCancel = cancel
+	a.ossSearchResults = nil
+
+	table := ui.CreateOssSearchResultsView()
+	a.ossSearchTable = table
+	where := fmt.Sprintf("oss://%s/%s", bucketName, prefix)
+	var scanned int
+	var truncated bool
+	setTitle := func(state string) {
+		table.SetTitle(fmt.Sprintf("Find in %s: %d matches in %d objects%s", where, len(a.ossSearchResults), scanned, state))
+	}
+	setTitle(" (searching...)")
+
+	ui.SetupTableNavigationWithSearch(table, a, func(row, col int) {
+		if object, ok := a.selectedOssSearchResult(table); ok {
+			a.showOssObjectDetail(object, ui.PageOssSearchDetail)
+		}
+	})
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c': // Stop the search, keeping the results
+			a.stopOssSearch()
+			return nil
+		case 'F': // Search again with other filters
+			a.showOssSearchDialog(table)
+			return nil
+		}
+		if object, ok := a.selectedOssSearchResult(table); ok && a.handleOssObjectKey(event, table, object) {
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	a.pages.AddPage(ui.PageOssSearch, ui.WrapTableInFlex(table), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssSearch)
+	a.tviewApp.SetFocus(table)
+
+	services := a.services
+	go func() {
+		err := services.OSS.WalkObjects(ctx, bucketName, prefix, func(objects []oss.ObjectProperties) error {
+			var matches []oss.ObjectProperties
+			for _, object := range objects {
+				if filter.Match(object) {
+					matches = append(matches, object)
+				}
+			}
+			a.tviewApp.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return // Stopped, or replaced by another search
+				}
+				scanned += len(objects)
+				for _, object := range matches {
+					if len(a.ossSearchResults) == ossSearchMaxResults {
+						truncated = true
+						cancel()
+						break
+					}
+					a.ossSearchResults = append(a.ossSearchResults, object)
+					ui.AddOssSearchResult(table, object)
+				}
+				setTitle(" (searching...)")
+			})
+			return nil
+		})
+		a.tviewApp.QueueUpdateDraw(func() {
+			if a.ossSearchTable != table {
+				return
+			}
+			switch {
+			case truncated:
+				setTitle(fmt.Sprintf(" (stopped at %d matches, narrow the search)", ossSearchMaxResults))
+			case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+				setTitle(" (stopped)")
+			case err != nil:
+				setTitle(" (failed)")
+				a.showErrorModal(fmt.Sprintf("Search failed: %v", err))
+			default:
+				setTitle("")
+			}
+			cancel()
+		})
+	}()
+}
+
+// stopOssSearch stops the search in progress, if any
+func (a *App) stopOssSearch() {
+	if a.ossSearchCancel != nil {
+		a.ossSearchCancel()
+		a.ossSearchCancel = nil
+	}
+}
+
+// selectedOssSearchResult returns the object of the selected row of the search results
+func (a *App) selectedOssSearchResult(table *tview.Table) (oss.ObjectProperties, bool) {
+	objectKey, ok := ui.SelectedReference(table)
+	if !ok {
+		return oss.ObjectProperties{}, false
+	}
+	for _, object := range a.ossSearchResults {
+		if object.Key == objectKey {
+			return object, true
+		}
+	}
+	return oss.ObjectProperties{}, false
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// ossUsageView is one level of the usage pages: the summary of the objects under a prefix,
+// counted in the background
+type ossUsageView struct {
+	usage  *service.BucketUsage
+	table  *tview.Table
+	view   tview.Primitive
+	cancel context.CancelFunc // Stops the counting
+}
+
+// showOssUsage opens the usage summary of the objects under prefix. The pages it was
+// opened from are returned to once every usage page is left.
+func (a *App) showOssUsage(bucketName, prefix string, returnPage string, returnFocus tview.Primitive) {
+	a.stopOssUsage()
+	a.ossUsageReturnPage, a.ossUsageReturnFocus = returnPage, returnFocus
+	a.pushOssUsage(bucketName, prefix)
+}
+
+// pushOssUsage shows the usage of a folder on top of the usage pages shown so far and
+// starts counting its objects. The folders above keep counting.
+func (a *App) pushOssUsage(bucketName, prefix string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := &ossUsageView{
+		usage:  service.NewBucketUsage(prefix),
+		table:  ui.CreateOssUsageView(),
+		cancel: cancel,
+	}
+	u.view = ui.WrapTableInFlex(u.table)
+	a.ossUsageStack = append(a.ossUsageStack, u)
+	ui.RenderOssUsage(u.table, bucketName, u.usage, " (counting...)")
+
+	ui.SetupTableNavigationWithSearch(u.table, a, func(row, col int) {
+		if folder, ok := ui.SelectedReference(u.table); ok && folder != u.usage.Prefix {
+			a.pushOssUsage(bucketName, folder)
+		}
+	})
+	originalInputCapture := u.table.GetInputCapture()
+	u.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'c' { // Stop counting, keeping the totals so far
+			u.cancel()
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+	a.showOssUsageView(u)
+
+	services := a.services
+	go func() {
+		err := services.OSS.WalkObjects(ctx, bucketName, prefix, func(objects []oss.ObjectProperties) error {
+			a.tviewApp.QueueUpdateDraw(func() {
+				if ctx.Err() == nil {
+					u.usage.Add(objects)
+					ui.RenderOssUsage(u.table, bucketName, u.usage, " (counting...)")
+				}
+			})
+			return nil
+		})
+		a.tviewApp.QueueUpdateDraw(func() {
+			switch {
+			case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+				ui.RenderOssUsage(u.table, bucketName, u.usage, " (stopped, incomplete)")
+			case err != nil:
+				ui.RenderOssUsage(u.table, bucketName, u.usage, " (failed, incomplete)")
+				if a.ossUsageTop() == u {
+					a.showErrorModal(fmt.Sprintf("Counting objects failed: %v", err))
+				}
+			default:
+				ui.RenderOssUsage(u.table, bucketName, u.usage, "")
+			}
+			cancel()
+		})
+	}()
+}
+
+// showOssUsageView shows a level of the usage pages
+func (a *App) showOssUsageView(u *ossUsageView) {
+	a.pages.AddPage(ui.PageOssUsage, u.view, true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssUsage)
+	a.tviewApp.SetFocus(u.table)
+}
+
+// ossUsageTop returns the usage page shown, or nil
+func (a *App) ossUsageTop() *ossUsageView {
+	if len(a.ossUsageStack) == 0 {
+		return nil
+	}
+	return a.ossUsageStack[len(a.ossUsageStack)-1]
+}
+
+// leaveOssUsage goes back to the usage of the parent folder, stopping the counting of the
+// folder left, or to the page the usage was opened from
+func (a *App) leaveOssUsage() {
+	left := ""
+	if u := a.ossUsageTop(); u != nil {
+		u.cancel()
+		left = u.usage.Prefix
+		a.ossUsageStack = a.ossUsageStack[:len(a.ossUsageStack)-1]
+	}
+	if parent := a.ossUsageTop(); parent != nil {
+		a.showOssUsageView(parent)
+		selectReference(parent.table, left)
+		return
+	}
+	a.stopOssUsage()
+	a.handleNavigation(a.ossUsageReturnPage, a.ossUsageReturnFocus)
+}
+
+// stopOssUsage stops counting every folder of the usage pages and forgets them
+func (a *App) stopOssUsage() {
+	for _, u := range a.ossUsageStack {
+		u.cancel()
+	}
+	a.ossUsageStack = nil
+}
+
+// selectReference selects the row of a table whose first cell has the given reference
+func selectReference(table *tview.Table, reference string) {
+	for row := 1; row < table.GetRowCount(); row++ {
+		if ref, ok := table.GetCell(row, 0).GetReference().(string); ok && ref == reference {
+			table.Select(row, 0)
+			return
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// showOssVersionsDialog asks for the key whose versions to show, starting from the
+// selected object. A deleted object is no longer listed, so its key can be typed.
+func (a *App) showOssVersionsDialog(table *tview.Table) {
+	objectKey := a.ossCurrentPrefix
+	if object, ok := a.selectedOssObject(table); ok {
+		objectKey = object.Key
+	}
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, fmt.Sprintf("Versions in %s", a.currentBucketName),
+		[]string{"Object key"}, []string{objectKey},
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			if objectKey := strings.TrimSpace(values[0]); objectKey != "" {
+				a.showOssVersions(a.currentBucketName, objectKey)
+			}
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// showOssVersions loads and shows the versions and delete markers of an object
+func (a *App) showOssVersions(bucketName, objectKey string) {
+	services := a.services
+	loadAsync(a, fmt.Sprintf("versions of %s", objectKey),
+		func(ctx context.Context) ([]service.ObjectVersion, error) {
+			return services.OSS.FetchObjectVersions(ctx, bucketName, objectKey)
+		},
+		func(versions []service.ObjectVersion) {
+			selected := ""
+			if a.ossVersionsTable != nil {
+				selected, _ = ui.SelectedReference(a.ossVersionsTable)
+			}
+			table := ui.CreateOssObjectVersionsView(bucketName, objectKey, versions)
+			a.ossVersionsTable = table
+			a.ossVersions = versions
+			selectReference(table, selected)
+
+			ui.SetupTableNavigationWithSearch(table, a, nil)
+			originalInputCapture := table.GetInputCapture()
+			table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				switch event.Rune() {
+				case 'D': // Download the selected version
+					if version, ok := a.selectedOssVersion(table); ok {
+						a.showDownloadOssVersionDialog(table, bucketName, version)
+					}
+					return nil
+				case 'C': // Make the selected version the current one
+					if version, ok := a.selectedOssVersion(table); ok {
+						a.restoreOssVersion(table, bucketName, version)
+					}
+					return nil
+				case 'r': // List the versions again
+					a.showOssVersions(bucketName, objectKey)
+					return nil
+				}
+				if originalInputCapture != nil {
+					return originalInputCapture(event)
+				}
+				return event
+			})
+
+			a.pages.AddPage(ui.PageOssVersions, ui.WrapTableInFlex(table), true, true)
+			ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageOssVersions)
+			a.tviewApp.SetFocus(table)
+		})
+}
+
+// selectedOssVersion returns the version of the selected row of the versions page
+func (a *App) selectedOssVersion(table *tview.Table) (service.ObjectVersion, bool) {
+	versionId, ok := ui.SelectedReference(table)
+	if !ok {
+		return service.ObjectVersion{}, false
+	}
+	for _, version := range a.ossVersions {
+		if version.VersionId == versionId {
+			return version, true
+		}
+	}
+	return service.ObjectVersion{}, false
+}
+
+// showDownloadOssVersionDialog asks where to download a version of an object and downloads
+// it with a progress dialog
+func (a *App) showDownloadOssVersionDialog(table *tview.Table, bucketName string, version service.ObjectVersion) {
+	if version.IsDeleteMarker {
+		a.showErrorModal("A delete marker has no content. Download one of the versions before it.")
+		return
+	}
+	services := a.services
+	title := fmt.Sprintf("Download %s as of %s (%s)", version.Key, version.LastModified.Format("2006-01-02 15:04:05"), ui.FormatBytes(version.Size))
+	a.askOssDownloadPath(table, title, version.Key, func(filePath string) {
+		a.runTransfer(fmt.Sprintf("Downloading %s", version.Key), table,
+			func(ctx context.Context, progress service.TransferProgress) error {
+				return services.OSS.DownloadObjectVersion(ctx, bucketName, version.Key, version.VersionId, filePath, progress)
+			},
+			func(err error) {
+				switch {
+				case errors.Is(err, context.Canceled):
+					a.showErrorModal(fmt.Sprintf("Download of %s cancelled. Downloading the version to %s again resumes it.", version.Key, filePath))
+				case err != nil:
+					a.showErrorModal(fmt.Sprintf("Failed to download: %v", err))
+				default:
+					ui.ShowMessageModal(a.pages, a.tviewApp,
+						fmt.Sprintf("Downloaded version %s of oss://%s/%s to %s (%s)", version.VersionId, bucketName, version.Key, filePath, ui.FormatBytes(version.Size)),
+						func() { a.tviewApp.SetFocus(table) })
+				}
+			})
+	})
+}
+
+// restoreOssVersion makes a previous version the current one after confirmation, then
+// lists the versions again
+func (a *App) restoreOssVersion(table *tview.Table, bucketName string, version service.ObjectVersion) {
+	switch {
+	case version.IsDeleteMarker:
+		a.showErrorModal("A delete marker has no content. Restore the version before it to undelete the object.")
+		return
+	case version.IsLatest:
+		a.showErrorModal(fmt.Sprintf("Version %s is already the current version.", version.VersionId))
+		return
+	case version.Size > service.OSSMaxCopySize:
+		a.showErrorModal(fmt.Sprintf("Version %s is %s, too large to restore in place (the limit is %s). Download it with D and upload it instead.",
+			version.VersionId, ui.FormatBytes(version.Size), ui.FormatBytes(service.OSSMaxCopySize)))
+		return
+	}
+	if !a.checkWritable() {
+		return
+	}
+
+	message := fmt.Sprintf("Restore oss://%s/%s as of %s (%s)?\n\nThe version is copied over the object as a new current version; the versions after it are kept.",
+		bucketName, version.Key, version.LastModified.Format("2006-01-02 15:04:05"), ui.FormatBytes(version.Size))
+	services := a.services
+	a.confirmDestructive(table, message, path.Base(version.Key), []string{"Restore"}, func(string) {
+		a.runWrite(fmt.Sprintf("restore %s", version.Key), func(ctx context.Context) error {
+			return services.OSS.RestoreObjectVersion(ctx, bucketName, version.Key, version.VersionId)
+		}, func() {
+			a.ossVersionsRestored = true
+			if page, _ := a.pages.GetFrontPage(); page == ui.PageOssVersions && a.ossVersionsTable == table {
+				a.showOssVersions(bucketName, version.Key)
+			}
+		})
+	})
+}
+
+// leaveOssVersions goes back to the object list, refreshing it if a version was restored
+func (a *App) leaveOssVersions() {
+	a.handleNavigation(ui.PageOssObjects, a.ossObjectTable)
+	if a.ossVersionsRestored {
+		a.ossVersionsRestored = false
+		a.refreshPage(ui.PageOssObjects)
+	}
+}
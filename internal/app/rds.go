@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// rdsSlowLogDays is how many days of slow query statistics the slow log page shows, today included
+const rdsSlowLogDays = 7
+
+// switchToRdsNetInfoView loads the connection strings of an instance and shows them
+func (a *App) switchToRdsNetInfoView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("connection strings for instance %s", instanceId),
+		func(ctx context.Context) ([]rds.DBInstanceNetInfo, error) {
+			return services.RDS.FetchNetInfo(ctx, instanceId)
+		}, func(netInfos []rds.DBInstanceNetInfo) {
+			showRdsInstancePage(a, ui.PageRdsNetInfo, instanceId, ui.CreateRdsNetInfoView(netInfos, instanceId), netInfos,
+				func(info rds.DBInstanceNetInfo) string { return info.ConnectionString },
+				func() { a.switchToRdsNetInfoView(instanceId) })
+		})
+}
+
+// switchToRdsWhitelistView loads the IP whitelists of an instance and shows them
+func (a *App) switchToRdsWhitelistView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("IP whitelists for instance %s", instanceId),
+		func(ctx context.Context) ([]rds.DBInstanceIPArray, error) {
+			return services.RDS.FetchIPWhitelists(ctx, instanceId)
+		}, func(groups []rds.DBInstanceIPArray) {
+			showRdsInstancePage(a, ui.PageRdsWhitelists, instanceId, ui.CreateRdsWhitelistView(groups, instanceId), groups,
+				ui.RdsWhitelistReference,
+				func() { a.switchToRdsWhitelistView(instanceId) })
+		})
+}
+
+// switchToRdsParametersView loads the parameters of an instance and shows them
+func (a *App) switchToRdsParametersView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("parameters for instance %s", instanceId),
+		func(ctx context.Context) ([]service.RDSParameter, error) {
+			return services.RDS.FetchParameters(ctx, instanceId)
+		}, func(parameters []service.RDSParameter) {
+			showRdsInstancePage(a, ui.PageRdsParameters, instanceId, ui.CreateRdsParametersView(parameters, instanceId), parameters,
+				func(parameter service.RDSParameter) string { return parameter.Name },
+				func() { a.switchToRdsParametersView(instanceId) })
+		})
+}
+
+// rdsBackups is the backup sets of an instance with its backup policy
+type rdsBackups struct {
+	backups []rds.Backup
+	policy  *rds.DescribeBackupPolicyResponse
+}
+
+// switchToRdsBackupsView loads the backups and backup policy of an instance and shows
+// them. P on the backups shows the whole policy.
+func (a *App) switchToRdsBackupsView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("backups for instance %s", instanceId),
+		func(ctx context.Context) (rdsBackups, error) {
+			policy, err := services.RDS.FetchBackupPolicy(ctx, instanceId)
+			if err != nil {
+				return rdsBackups{}, err
+			}
+			backups, err := services.RDS.FetchBackups(ctx, instanceId)
+			return rdsBackups{backups: backups, policy: policy}, err
+		}, func(result rdsBackups) {
+			table := ui.CreateRdsBackupsView(result.backups, result.policy, instanceId)
+			showRdsInstancePage(a, ui.PageRdsBackups, instanceId, table, result.backups,
+				func(backup rds.Backup) string { return backup.BackupId },
+				func() { a.switchToRdsBackupsView(instanceId) })
+
+			originalInputCapture := table.GetInputCapture()
+			table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Rune() == 'P' { // The whole backup policy
+					a.showRdsItemDetail(fmt.Sprintf("Backup Policy: %s", instanceId), result.policy)
+					return nil
+				}
+				if originalInputCapture != nil {
+					return originalInputCapture(event)
+				}
+				return event
+			})
+		})
+}
+
+// switchToRdsReadOnlyView loads the read-only instances of a primary instance and shows them
+func (a *App) switchToRdsReadOnlyView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	loadAsync(a, fmt.Sprintf("read-only instances of %s", instanceId),
+		func(ctx context.Context) ([]rds.DBInstance, error) {
+			return services.RDS.FetchReadOnlyInstances(ctx, instanceId)
+		}, func(instances []rds.DBInstance) {
+			showRdsInstancePage(a, ui.PageRdsReadOnly, instanceId, ui.CreateRdsReadOnlyView(instances, instanceId), instances,
+				func(inst rds.DBInstance) string { return inst.DBInstanceId },
+				func() { a.switchToRdsReadOnlyView(instanceId) })
+		})
+}
+
+// switchToRdsSlowLogsView loads the slow query statistics of the last rdsSlowLogDays days
+// of an instance and shows them
+func (a *App) switchToRdsSlowLogsView(instanceId string) {
+	services := a.servicesFor(instanceId)
+	end := time.Now()
+	start := end.AddDate(0, 0, 1-rdsSlowLogDays)
+	loadAsync(a, fmt.Sprintf("slow logs for instance %s", instanceId),
+		func(ctx context.Context) ([]rds.SQLSlowLog, error) {
+			return services.RDS.FetchSlowLogs(ctx, instanceId, start, end)
+		}, func(logs []rds.SQLSlowLog) {
+			showRdsInstancePage(a, ui.PageRdsSlowLogs, instanceId, ui.CreateRdsSlowLogsView(logs, instanceId, rdsSlowLogDays), logs,
+				ui.RdsSlowLogReference,
+				func() { a.switchToRdsSlowLogsView(instanceId) })
+		})
+}
+
+// showRdsInstancePage shows a table about one RDS instance. Enter shows the item of the
+// selected row, the one whose key is the row's reference, as JSON.
+func showRdsInstancePage[T any](a *App, page, instanceId string, table *tview.Table, items []T, key func(T) string, reload func()) {
+	a.currentRdsInstanceId = instanceId
+	a.rdsInstancePage, a.rdsInstancePageTable = page, table
+
+	ui.SetupTableNavigationWithSearch(table, a, func(row, col int) {
+		ref, ok := ui.SelectedReference(table)
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			if key(item) == ref {
+				a.showRdsItemDetail(fmt.Sprintf("Details: %s", ref), item)
+				return
+			}
+		}
+	})
+
+	a.setupTableYankFunctionality(table, items)
+	a.setupTableRefresh(page, table, reload)
+	a.pages.AddPage(page, ui.WrapTableInFlex(table), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), page)
+	a.tviewApp.SetFocus(table)
+}
+
+// showRdsItemDetail shows an item of an RDS instance page as JSON
+func (a *App) showRdsItemDetail(title string, item interface{}) {
+	a.currentDetailData = item
+	detailView, _ := ui.CreateInteractiveJSONDetailViewWithSearch(
+		title,
+		item,
+		a,
+		func() {
+			err := ui.CopyToClipboard(item)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Copy failed: %v", err))
+			} else {
+				a.showErrorModal("Copied!")
+			}
+		},
+		func() {
+			err := ui.OpenInEditor(item, a.tviewApp)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Edit failed: %v", err))
+			}
+		},
+		func() {
+			err := ui.OpenInPager(item, a.tviewApp)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to open pager: %v", err))
+			}
+		},
+	)
+	a.pages.AddPage(ui.PageRdsItemDetail, ui.CreateDetailViewWithInstructions(detailView), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageRdsItemDetail)
+	a.tviewApp.SetFocus(detailView)
+}
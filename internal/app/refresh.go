@@ -0,0 +1,104 @@
+package app
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// refreshTarget is a list page that can be refreshed with r or Ctrl-R
+type refreshTarget struct {
+	table    *tview.Table
+	reload   func()
+	snapshot ui.TableSnapshot // Cell texts as rendered, before any highlighting
+}
+
+// pendingRefresh carries the state of a list page across a refresh
+type pendingRefresh struct {
+	page       string
+	selectedID string
+	snapshot   ui.TableSnapshot
+}
+
+// SetRefreshInterval sets the interval at which the list page in front is refreshed
+// automatically. Zero disables auto-refresh. It must be called before Run.
+func (a *App) SetRefreshInterval(interval time.Duration) {
+	a.refreshInterval = interval
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageMainMenu)
+}
+
+// setupTableRefresh makes a list page refreshable: r or Ctrl-R on its table calls reload,
+// which must render the page again. When the page is rendered by a refresh, the row that
+// was selected before is selected again and the cells that changed are highlighted.
+func (a *App) setupTableRefresh(page string, table *tview.Table, reload func()) {
+	snapshot := ui.SnapshotTable(table)
+	if pending := a.pendingRefresh; pending != nil && pending.page == page {
+		a.pendingRefresh = nil
+		ui.HighlightTableChanges(table, pending.snapshot)
+		if pending.selectedID != "" {
+			ui.SelectRowByReference(table, pending.selectedID)
+		}
+	}
+	a.refreshTargets[page] = &refreshTarget{table: table, reload: reload, snapshot: snapshot}
+
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlR || event.Rune() == 'r' {
+			a.refreshPage(page)
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// refreshPage reloads a list page, remembering its selection and contents
+func (a *App) refreshPage(page string) {
+	target, ok := a.refreshTargets[page]
+	if !ok {
+		return
+	}
+	selectedID, _ := ui.SelectedReference(target.table)
+	a.pendingRefresh = &pendingRefresh{page: page, selectedID: selectedID, snapshot: target.snapshot}
+	target.reload()
+}
+
+// startAutoRefresh refreshes the list page in front every refreshInterval until the
+// returned function is called
+func (a *App) startAutoRefresh() (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(a.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				a.tviewApp.QueueUpdateDraw(a.autoRefresh)
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// autoRefresh refreshes the list page in front, unless the user is busy with something
+// else: a load in flight, a dialog, the search bar or a page that is not a list
+func (a *App) autoRefresh() {
+	if a.isLoading() {
+		return
+	}
+	page, _ := a.pages.GetFrontPage()
+	target, ok := a.refreshTargets[page]
+	if !ok || a.tviewApp.GetFocus() != target.table {
+		return
+	}
+	a.refreshPage(page)
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/client"
+	"aliyun-tui-viewer/internal/config"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// maxRegionFetchers limits the concurrent API calls in the all-regions mode
+const maxRegionFetchers = 8
+
+// regionContext holds the clients and services of one region
+type regionContext struct {
+	clients    *client.AliyunClients
+	services   *Services
+	production bool // The profile is a production profile, see confirmDestructive
+}
+
+// modeLineContext returns the profile, region, auto-refresh interval and read-only badge
+// shown in the mode line
+func (a *App) modeLineContext() string {
+	region := a.currentRegion
+	if a.allRegionsMode {
+		region = "all regions"
+	}
+	text := a.currentProfile
+	if region != "" {
+		text = fmt.Sprintf("%s | Region: %s", text, region)
+	}
+	if a.refreshInterval > 0 {
+		text = fmt.Sprintf("%s | Auto-refresh: %s", text, a.refreshInterval)
+	}
+	if a.services.Writes.ReadOnly() {
+		text = fmt.Sprintf("%s | %s", text, ui.ReadOnlyBadge)
+	}
+	return text
+}
+
+// showRegionSelectionDialog shows the region selection dialog, loading the regions first
+// if needed
+func (a *App) showRegionSelectionDialog() {
+	if a.clients == nil {
+		a.showErrorModal("Switching regions requires Alibaba Cloud credentials.")
+		return
+	}
+
+	if a.allRegions == nil {
+		services := a.services
+		loadAsync(a, "regions", func(ctx context.Context) ([]ecs.Region, error) {
+			return fetchSortedRegions(ctx, services)
+		}, func(regions []ecs.Region) {
+			a.allRegions = regions
+			a.showRegionSelectionDialog()
+		})
+		return
+	}
+
+	currentRegion := a.currentRegion
+	if a.allRegionsMode {
+		currentRegion = ui.AllRegions
+	}
+
+	ui.ShowRegionSelectionDialog(a.pages, a.tviewApp, a.allRegions, currentRegion,
+		func(selectedRegion string) {
+			// Region selected callback
+			a.switchToRegion(selectedRegion)
+		},
+		func() {
+			// Cancel callback - restore focus to current page
+			a.restoreFocus()
+		})
+}
+
+// fetchSortedRegions fetches the available regions ordered by region ID
+func fetchSortedRegions(ctx context.Context, services *Services) ([]ecs.Region, error) {
+	regions, err := services.ECS.FetchRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading regions: %w", err)
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i].RegionId < regions[j].RegionId })
+	return regions, nil
+}
+
+// switchToRegion rebuilds the regional clients for the selected region, or enables the
+// all-regions mode when ui.AllRegions is selected
+func (a *App) switchToRegion(regionId string) {
+	a.cancelLoad()
+
+	switch {
+	case regionId == ui.AllRegions:
+		if a.allRegionsMode {
+			a.restoreFocus()
+			return
+		}
+		a.allRegionsMode = true
+	case regionId == a.currentRegion && !a.allRegionsMode:
+		a.restoreFocus()
+		return
+	default:
+		regional, err := a.regionalContext(regionId)
+		if err != nil {
+			a.showErrorModal(fmt.Sprintf("Failed to switch to region %s: %v", regionId, err))
+			return
+		}
+		a.clients = regional.clients
+		a.services = regional.services
+		a.currentRegion = regionId
+		a.allRegionsMode = false
+	}
+
+	// Clear cached data to force reload in the new region(s)
+	a.clearCachedData()
+
+	// Navigate back to main menu, the same as after a profile switch
+	a.pages.SwitchToPage(ui.PageMainMenu)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageMainMenu)
+	a.tviewApp.SetFocus(a.mainMenu)
+}
+
+// restoreFocus focuses the front page, or the main menu if there is none
+func (a *App) restoreFocus() {
+	_, prim := a.pages.GetFrontPage()
+	if prim != nil {
+		a.tviewApp.SetFocus(prim)
+	} else {
+		a.tviewApp.SetFocus(a.mainMenu)
+	}
+}
+
+// regionalContext returns the clients and services of a region, creating them on first use
+func (a *App) regionalContext(regionId string) (*regionContext, error) {
+	if regionId == a.currentRegion && !a.allRegionsMode && a.clients != nil {
+		return &regionContext{clients: a.clients, services: a.services}, nil
+	}
+	return a.regionPool.get(regionId)
+}
+
+// regionPool caches the regional clients and services of one profile. It is safe for
+// concurrent use, so background loads can share it; a profile switch replaces the pool.
+type regionPool struct {
+	profile  string
+	readOnly bool // Refuse writes whatever the profile allows, see Options.ReadOnly
+	mu       sync.Mutex
+	contexts map[string]*regionContext
+}
+
+// newRegionPool creates an empty pool for the given profile
+func newRegionPool(profile string, readOnly bool) *regionPool {
+	return &regionPool{profile: profile, readOnly: readOnly, contexts: make(map[string]*regionContext)}
+}
+
+// get returns the clients and services of a region, creating them on first use
+func (p *regionPool) get(regionId string) (*regionContext, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if regional, ok := p.contexts[regionId]; ok {
+		return regional, nil
+	}
+
+	cfg, err := config.LoadProfileConfig(p.profile, regionId)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if p.readOnly {
+		cfg.SetReadOnly()
+	}
+	clients, err := client.NewAliyunClients(client.NewConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("creating clients: %w", err)
+	}
+
+	regional := &regionContext{clients: clients, services: NewServices(clients, cfg), production: cfg.Production}
+	p.contexts[regionId] = regional
+	return regional, nil
+}
+
+// contextFor returns the clients and services for a resource. In the all-regions mode
+// this is the region the resource was listed in; otherwise it is the current region.
+func (a *App) contextFor(resourceId string) (*client.AliyunClients, *Services) {
+	if a.allRegionsMode {
+		if regionId, ok := a.resourceRegions[resourceId]; ok {
+			if regional, err := a.regionalContext(regionId); err == nil {
+				return regional.clients, regional.services
+			}
+		}
+	}
+	return a.clients, a.services
+}
+
+// servicesFor returns the services for a resource, see contextFor
+func (a *App) servicesFor(resourceId string) *Services {
+	_, services := a.contextFor(resourceId)
+	return services
+}
+
+// inheritRegion records that child resources live in the same region as their parent,
+// so drilling down from them keeps using the parent's regional clients
+func (a *App) inheritRegion(parentId string, childIds ...string) {
+	regionId, ok := a.resourceRegions[parentId]
+	if !a.allRegionsMode || !ok {
+		return
+	}
+	for _, childId := range childIds {
+		a.resourceRegions[childId] = regionId
+	}
+}
+
+// insertRegionColumn adds the Region column to a list table in the all-regions mode
+func (a *App) insertRegionColumn(table *tview.Table) {
+	if a.allRegionsMode {
+		ui.InsertRegionColumn(table, a.resourceRegions)
+	}
+}
+
+// regionalResult is the outcome of a fetch in the all-regions mode
+type regionalResult[T any] struct {
+	items           []T
+	regions         []ecs.Region
+	resourceRegions map[string]string
+	failures        []string
+}
+
+// loadRegional loads a resource list with loadAsync from the current region, or in the
+// all-regions mode from every region concurrently. Results are merged in region order and
+// the region of each resource is recorded by the ID returned by idOf. Regions that fail
+// are reported by showRegionWarnings; the load fails only if every region failed.
+func loadRegional[T any](a *App, label string, fetch func(context.Context, *Services) ([]T, error), idOf func(T) string, render func([]T)) {
+	services := a.services
+	if !a.allRegionsMode {
+		loadAsync(a, label, func(ctx context.Context) ([]T, error) {
+			items, err := fetch(ctx, services)
+			return nonNil(items), err
+		}, render)
+		return
+	}
+
+	pool, regions := a.regionPool, a.allRegions
+	loadAsync(a, label, func(ctx context.Context) (*regionalResult[T], error) {
+		return fetchRegional(ctx, services, pool, regions, fetch, idOf)
+	}, func(result *regionalResult[T]) {
+		a.allRegions = result.regions
+		if a.resourceRegions == nil {
+			a.resourceRegions = make(map[string]string)
+		}
+		for id, regionId := range result.resourceRegions {
+			a.resourceRegions[id] = regionId
+		}
+		a.regionWarnings = result.failures
+		render(result.items)
+	})
+}
+
+// fetchRegional fetches a resource list from every region concurrently. It runs on a
+// background goroutine and therefore only uses the state passed in; regions is loaded
+// first when it is nil.
+func fetchRegional[T any](ctx context.Context, services *Services, pool *regionPool, regions []ecs.Region, fetch func(context.Context, *Services) ([]T, error), idOf func(T) string) (*regionalResult[T], error) {
+	if regions == nil {
+		var err error
+		if regions, err = fetchSortedRegions(ctx, services); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([][]T, len(regions))
+	errs := make([]error, len(regions))
+	semaphore := make(chan struct{}, maxRegionFetchers)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, regionId string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if errs[i] = ctx.Err(); errs[i] != nil {
+				return
+			}
+			regional, err := pool.get(regionId)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i], errs[i] = fetch(ctx, regional.services)
+		}(i, region.RegionId)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &regionalResult[T]{
+		items:           []T{},
+		regions:         regions,
+		resourceRegions: make(map[string]string),
+	}
+	for i, region := range regions {
+		if errs[i] != nil {
+			result.failures = append(result.failures, fmt.Sprintf("%s: %v", region.RegionId, errs[i]))
+			continue
+		}
+		for _, item := range results[i] {
+			result.resourceRegions[idOf(item)] = region.RegionId
+		}
+		result.items = append(result.items, results[i]...)
+	}
+
+	if len(result.failures) == len(regions) && len(regions) > 0 {
+		return nil, fmt.Errorf("fetching from all regions failed:\n%s", strings.Join(result.failures, "\n"))
+	}
+	return result, nil
+}
+
+// showRegionWarnings reports the regions that failed during the last all-regions fetch
+func (a *App) showRegionWarnings() {
+	if len(a.regionWarnings) == 0 {
+		return
+	}
+	message := fmt.Sprintf("Some regions could not be loaded:\n%s", strings.Join(a.regionWarnings, "\n"))
+	a.regionWarnings = nil
+	a.showErrorModal(message)
+}
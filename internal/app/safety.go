@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// checkWritable reports whether write operations are allowed and explains why not
+// otherwise. The services refuse writes on their own; checking first only spares the
+// user a confirmation that would fail anyway.
+func (a *App) checkWritable() bool {
+	if !a.services.Writes.ReadOnly() {
+		return true
+	}
+	a.showErrorModal(fmt.Sprintf("Read-only mode: %s.", a.services.Writes.Reason()))
+	return false
+}
+
+// confirmDestructive asks for confirmation of an action that destroys data or interrupts
+// service. On production profiles the user has to type resourceId; elsewhere a plain
+// confirmation modal is shown. The focus returns to focusAfter once it is dismissed.
+func (a *App) confirmDestructive(focusAfter tview.Primitive, message, resourceId string, actions []string, onConfirm func(action string)) {
+	a.confirmDestructiveOn(a.production, focusAfter, message, resourceId, actions, onConfirm)
+}
+
+// confirmDestructiveOn is confirmDestructive for a resource that may belong to another
+// profile than the current one; production tells whether that profile is a production one
+func (a *App) confirmDestructiveOn(production bool, focusAfter tview.Primitive, message, resourceId string, actions []string, onConfirm func(action string)) {
+	confirm := func(action string) {
+		a.tviewApp.SetFocus(focusAfter)
+		onConfirm(action)
+	}
+	cancel := func() { a.tviewApp.SetFocus(focusAfter) }
+
+	if production {
+		ui.ShowTypedConfirmModal(a.pages, a.tviewApp, message, resourceId, actions, confirm, cancel)
+		return
+	}
+	ui.ShowConfirmModal(a.pages, a.tviewApp, message, actions, confirm, cancel)
+}
+
+// runWrite sends a write operation on a background goroutine and calls onDone on the UI
+// goroutine once it succeeded; failures are shown in a modal. Unlike a load, a write is not
+// cancelled by navigating away, since it may already have been applied.
+func (a *App) runWrite(description string, write func(ctx context.Context) error, onDone func()) {
+	go func() {
+		err := write(context.Background())
+		a.tviewApp.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to %s: %v", description, err))
+				return
+			}
+			if onDone != nil {
+				onDone()
+			}
+		})
+	}()
+}
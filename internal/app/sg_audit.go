@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// reloadSecurityGroupAudit audits every security group and shows the findings
+func (a *App) reloadSecurityGroupAudit() {
+	loadRegional(a, "security group audit",
+		func(ctx context.Context, s *Services) ([]service.SecurityGroupFinding, error) {
+			return service.AuditSecurityGroups(ctx, s.ECS)
+		},
+		func(finding service.SecurityGroupFinding) string { return finding.SecurityGroupId },
+		func(findings []service.SecurityGroupFinding) {
+			a.sgAuditFindings = findings
+			a.showSecurityGroupAudit()
+		})
+}
+
+// showSecurityGroupAudit shows the findings of the last audit in the order chosen with o
+// and i, keeping the selected finding selected
+func (a *App) showSecurityGroupAudit() {
+	selected := ""
+	if a.sgAuditTable != nil {
+		selected, _ = ui.SelectedReference(a.sgAuditTable)
+	}
+	if a.sgAuditSortBy == "" {
+		a.sgAuditSortBy = service.FindingSortSeverity
+	}
+	service.SortSecurityGroupFindings(a.sgAuditFindings, a.sgAuditSortBy, a.sgAuditReverse)
+
+	table := ui.CreateSecurityGroupAuditView(a.sgAuditFindings, a.sgAuditSortBy, a.sgAuditReverse)
+	a.sgAuditTable = table
+	selectReference(table, selected)
+
+	ui.SetupTableNavigationWithSearch(table, a, func(row, col int) {
+		if finding, ok := a.selectedSecurityGroupFinding(table); ok {
+			a.sgReturnPage, a.sgReturnFocus = ui.PageSecurityGroupAudit, table
+			a.showSecurityGroupRule(finding.SecurityGroupId, finding.RuleId)
+		}
+	})
+	a.setupTableYankFunctionality(table, a.sgAuditFindings)
+	a.setupTableRefresh(ui.PageSecurityGroupAudit, table, a.reloadSecurityGroupAudit)
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'o': // Sort by the next column
+			next := (slices.Index(service.FindingSortKeys, a.sgAuditSortBy) + 1) % len(service.FindingSortKeys)
+			a.sgAuditSortBy = service.FindingSortKeys[next]
+			a.showSecurityGroupAudit()
+			return nil
+		case 'i': // Reverse the order
+			a.sgAuditReverse = !a.sgAuditReverse
+			a.showSecurityGroupAudit()
+			return nil
+		case 's': // Instances the finding affects
+			if finding, ok := a.selectedSecurityGroupFinding(table); ok {
+				a.sgReturnPage, a.sgReturnFocus = ui.PageSecurityGroupAudit, table
+				a.switchToSecurityGroupInstancesView(finding.SecurityGroupId)
+			}
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	a.pages.AddPage(ui.PageSecurityGroupAudit, ui.WrapTableInFlex(table), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSecurityGroupAudit)
+	a.tviewApp.SetFocus(table)
+	a.showRegionWarnings()
+}
+
+// selectedSecurityGroupFinding returns the finding of the selected row of the audit
+func (a *App) selectedSecurityGroupFinding(table *tview.Table) (service.SecurityGroupFinding, bool) {
+	reference, ok := ui.SelectedReference(table)
+	if !ok {
+		return service.SecurityGroupFinding{}, false
+	}
+	for _, finding := range a.sgAuditFindings {
+		if ui.SecurityGroupFindingReference(finding) == reference {
+			return finding, true
+		}
+	}
+	return service.SecurityGroupFinding{}, false
+}
+
+// showSecurityGroupRule opens the rules of a security group with one rule selected
+func (a *App) showSecurityGroupRule(securityGroupId, ruleId string) {
+	services := a.servicesFor(securityGroupId)
+	loadAsync(a, fmt.Sprintf("security group rules for %s", securityGroupId),
+		func(ctx context.Context) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+			return services.ECS.FetchSecurityGroupRules(ctx, securityGroupId)
+		},
+		func(rulesResponse *ecs.DescribeSecurityGroupAttributeResponse) {
+			a.showSecurityGroupRulesView(rulesResponse)
+			selectReference(a.securityGroupRulesTable, ruleId)
+		})
+}
+
+// leaveSecurityGroupPage goes back from the rules or the instances of a security group to
+// the page they were opened from, see sgReturnPage
+func (a *App) leaveSecurityGroupPage() {
+	if a.sgReturnPage != "" {
+		a.handleNavigation(a.sgReturnPage, a.sgReturnFocus)
+		return
+	}
+	a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
+}
+
+// leaveSecurityGroupAudit goes back from the audit to the security group list
+func (a *App) leaveSecurityGroupAudit() {
+	a.sgReturnPage, a.sgReturnFocus = "", nil
+	a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/config"
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// showSecurityGroupCompareDialog asks which security group to compare the selected one
+// with, offering the group of the last comparison again
+func (a *App) showSecurityGroupCompareDialog(table *tview.Table) {
+	securityGroupId, ok := ui.SelectedReference(table)
+	if !ok {
+		return
+	}
+	left := service.SecurityGroupRef{Profile: a.currentProfile, Region: a.currentRegion, SecurityGroupId: securityGroupId}
+	if regionId, ok := a.resourceRegions[securityGroupId]; ok && a.allRegionsMode {
+		left.Region = regionId
+	}
+
+	profiles, err := config.ListAllProfiles()
+	if err != nil || !slices.Contains(profiles, a.currentProfile) {
+		profiles = append(profiles, a.currentProfile)
+	}
+	target := a.sgDiffTarget
+	if target.SecurityGroupId == "" {
+		target = service.SecurityGroupRef{Profile: a.currentProfile, Region: left.Region}
+	}
+
+	ui.ShowSecurityGroupCompareDialog(a.pages, a.tviewApp, fmt.Sprintf("Compare %s with", securityGroupId), profiles, target,
+		func(right service.SecurityGroupRef) {
+			a.tviewApp.SetFocus(table)
+			right = right.Resolve(a.currentProfile, a.currentRegion)
+			a.sgDiffTarget = right
+			a.compareSecurityGroups(left, right)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// securityGroupServices returns the services for a security group of any profile and
+// region, and whether its profile is a production profile, see confirmDestructive. The
+// clients of other profiles are created on first use and kept for the session.
+func (a *App) securityGroupServices(ref service.SecurityGroupRef) (*Services, bool, error) {
+	if ref.Profile == a.currentProfile {
+		if ref.Region == a.currentRegion {
+			return a.services, a.production, nil
+		}
+		regional, err := a.regionPool.get(ref.Region)
+		if err != nil {
+			return nil, false, err
+		}
+		return regional.services, a.production, nil
+	}
+
+	pool, ok := a.profilePools[ref.Profile]
+	if !ok {
+		pool = newRegionPool(ref.Profile, a.forceReadOnly)
+		if a.profilePools == nil {
+			a.profilePools = make(map[string]*regionPool)
+		}
+		a.profilePools[ref.Profile] = pool
+	}
+	regional, err := pool.get(ref.Region)
+	if err != nil {
+		return nil, false, err
+	}
+	return regional.services, regional.production, nil
+}
+
+// compareSecurityGroups fetches the rules of two security groups and shows their diff
+func (a *App) compareSecurityGroups(left, right service.SecurityGroupRef) {
+	leftServices, _, err := a.securityGroupServices(left)
+	if err != nil {
+		a.showErrorModal(fmt.Sprintf("Failed to compare %s with %s: %v", left, right, err))
+		return
+	}
+	rightServices, _, err := a.securityGroupServices(right)
+	if err != nil {
+		a.showErrorModal(fmt.Sprintf("Failed to compare %s with %s: %v", left, right, err))
+		return
+	}
+
+	loadAsync(a, fmt.Sprintf("rules of %s and %s", left.SecurityGroupId, right.SecurityGroupId),
+		func(ctx context.Context) ([2]*service.SecurityGroupRuleSet, error) {
+			leftRules, err := service.FetchSecurityGroupRuleSet(ctx, leftServices.ECS, left)
+			if err != nil {
+				return [2]*service.SecurityGroupRuleSet{}, fmt.Errorf("fetching the rules of %s: %w", left, err)
+			}
+			rightRules, err := service.FetchSecurityGroupRuleSet(ctx, rightServices.ECS, right)
+			if err != nil {
+				return [2]*service.SecurityGroupRuleSet{}, fmt.Errorf("fetching the rules of %s: %w", right, err)
+			}
+			return [2]*service.SecurityGroupRuleSet{leftRules, rightRules}, nil
+		},
+		func(sets [2]*service.SecurityGroupRuleSet) {
+			a.sgDiffLeft, a.sgDiffRight = sets[0], sets[1]
+			a.sgDiffLines = service.DiffSecurityGroupRules(a.sgDiffLeft, a.sgDiffRight)
+			a.showSecurityGroupDiff()
+		})
+}
+
+// showSecurityGroupDiff shows the diff of the last comparison
+func (a *App) showSecurityGroupDiff() {
+	table := ui.CreateSecurityGroupDiffView(a.sgDiffLeft, a.sgDiffRight, a.sgDiffLines, a.sgDiffHideSame)
+	a.sgDiffTable = table
+
+	ui.SetupTableNavigationWithSearch(table, a, nil)
+	a.setupTableYankFunctionality(table, a.sgDiffLines)
+	a.setupTableRefresh(ui.PageSecurityGroupDiff, table, func() {
+		a.compareSecurityGroups(a.sgDiffLeft.Ref, a.sgDiffRight.Ref)
+	})
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'C': // Clone the rules of the left group into the right one
+			a.cloneSecurityGroupRules(table)
+			return nil
+		case 'w': // Swap the sides
+			a.sgDiffLeft, a.sgDiffRight = a.sgDiffRight, a.sgDiffLeft
+			a.sgDiffLines = service.DiffSecurityGroupRules(a.sgDiffLeft, a.sgDiffRight)
+			a.showSecurityGroupDiff()
+			return nil
+		case 'h': // Hide or show the rules both groups have
+			a.sgDiffHideSame = !a.sgDiffHideSame
+			a.showSecurityGroupDiff()
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	a.pages.AddPage(ui.PageSecurityGroupDiff, ui.WrapTableInFlex(table), true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageSecurityGroupDiff)
+	a.tviewApp.SetFocus(table)
+}
+
+// cloneSecurityGroupRules gives the right group of the diff every rule of the left one,
+// after confirmation, and compares them again. Rules only the right group has are kept.
+func (a *App) cloneSecurityGroupRules(table *tview.Table) {
+	source, target := a.sgDiffLeft, a.sgDiffRight
+	targetServices, production, err := a.securityGroupServices(target.Ref)
+	if err != nil {
+		a.showErrorModal(fmt.Sprintf("Failed to clone into %s: %v", target.Ref, err))
+		return
+	}
+	if targetServices.Writes.ReadOnly() {
+		a.showErrorModal(fmt.Sprintf("Read-only mode: %s.", targetServices.Writes.Reason()))
+		return
+	}
+
+	plan := service.PlanSecurityGroupClone(source, target)
+	var added, modified int
+	var skipped []service.CloneStep
+	for _, step := range plan.Steps {
+		switch step.Action {
+		case service.CloneAdd:
+			added++
+		case service.CloneModify:
+			modified++
+		default:
+			skipped = append(skipped, step)
+		}
+	}
+	skippedText := ""
+	if len(skipped) > 0 {
+		skippedText = fmt.Sprintf("\n%d rules are skipped, e.g. %s: %s", len(skipped), service.DescribeRule(skipped[0].Spec), skipped[0].Reason)
+	}
+	if plan.Changes() == 0 && len(skipped) == 0 {
+		a.showErrorModal(fmt.Sprintf("Nothing to clone: %s already has every rule of %s.", target.Ref, source.Ref))
+		return
+	}
+	if plan.Changes() == 0 {
+		a.showErrorModal(fmt.Sprintf("Nothing can be cloned into %s.%s", target.Ref, skippedText))
+		return
+	}
+
+	message := fmt.Sprintf("Clone the rules of %s into %s?\nThis adds %d and modifies %d rules; rules only the target has are kept.%s",
+		source.Ref, target.Ref, added, modified, skippedText)
+	a.confirmDestructiveOn(production, table, message, target.Ref.SecurityGroupId, []string{"Clone"}, func(string) {
+		a.runWrite(fmt.Sprintf("clone the rules of %s into %s", source.Ref, target.Ref),
+			func(ctx context.Context) error {
+				_, err := service.ApplySecurityGroupClone(ctx, targetServices.ECS, plan)
+				return err
+			},
+			func() { a.compareSecurityGroups(source.Ref, target.Ref) })
+	})
+}
+
+// leaveSecurityGroupDiff goes back from the diff to the security group list
+func (a *App) leaveSecurityGroupDiff() {
+	a.handleNavigation(ui.PageSecurityGroups, a.securityGroupTable)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// reachFields are the fields of the reachability dialog
+var reachFields = []string{"Source (instance ID, IP or CIDR)", "Destination instance", "Protocol (TCP, UDP or ICMP)", "Port"}
+
+// showReachabilityDialog asks what to check, with the selected instance as the
+// destination and the other fields as last entered
+func (a *App) showReachabilityDialog(table *tview.Table) {
+	values := []string{"", "", "TCP", ""}
+	if a.reachValues != nil {
+		values = append([]string(nil), a.reachValues...)
+	}
+	if instanceId, ok := ui.SelectedReference(table); ok {
+		values[1] = instanceId
+	}
+
+	ui.ShowFormDialog(a.pages, a.tviewApp, "Can the source reach the destination?", reachFields, values,
+		func(values []string) {
+			a.tviewApp.SetFocus(table)
+			a.reachValues = values
+			query, err := parseReachabilityQuery(values)
+			if err == nil {
+				err = service.ValidateReachabilityQuery(query)
+			}
+			if err != nil {
+				a.showErrorModal(err.Error())
+				return
+			}
+			a.checkReachability(query)
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// parseReachabilityQuery reads the fields of the reachability dialog
+func parseReachabilityQuery(values []string) (service.ReachabilityQuery, error) {
+	query := service.ReachabilityQuery{
+		Source:     strings.TrimSpace(values[0]),
+		InstanceId: strings.TrimSpace(values[1]),
+		Protocol:   strings.ToUpper(strings.TrimSpace(values[2])),
+	}
+	if port := strings.TrimSpace(values[3]); port != "" && query.Protocol != "ICMP" {
+		var err error
+		if query.Port, err = strconv.Atoi(port); err != nil {
+			return query, fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return query, nil
+}
+
+// checkReachability evaluates the security groups of the destination and shows the
+// outcome
+func (a *App) checkReachability(query service.ReachabilityQuery) {
+	services := a.servicesFor(query.InstanceId)
+	loadAsync(a, fmt.Sprintf("reachability of %s", query.InstanceId),
+		func(ctx context.Context) (*service.Reachability, error) {
+			return service.CheckReachability(ctx, services.ECS, query)
+		},
+		func(reachability *service.Reachability) {
+			a.showReachability(reachability, query)
+		})
+}
+
+// showReachability shows the outcome of a reachability check
+func (a *App) showReachability(reachability *service.Reachability, query service.ReachabilityQuery) {
+	view, table := ui.CreateReachabilityView(reachability, query.Traffic())
+	a.reachTable = table
+	a.reachResult = reachability
+
+	ui.SetupTableNavigationWithSearch(table, a, func(row, col int) {
+		if rule, ok := a.selectedReachabilityRule(table); ok && rule.RuleId != service.IntraGroupPolicyId {
+			a.sgReturnPage, a.sgReturnFocus = ui.PageReachability, table
+			a.showSecurityGroupRule(rule.SecurityGroupId, rule.RuleId)
+		}
+	})
+	a.setupTableYankFunctionality(table, reachability.Rules)
+	a.setupTableRefresh(ui.PageReachability, table, func() {
+		a.checkReachability(query)
+	})
+	originalInputCapture := table.GetInputCapture()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 's' { // Instances of the group of the selected rule
+			if rule, ok := a.selectedReachabilityRule(table); ok {
+				a.sgReturnPage, a.sgReturnFocus = ui.PageReachability, table
+				a.switchToSecurityGroupInstancesView(rule.SecurityGroupId)
+			}
+			return nil
+		}
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	a.pages.AddPage(ui.PageReachability, view, true, true)
+	ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), ui.PageReachability)
+	a.tviewApp.SetFocus(table)
+}
+
+// selectedReachabilityRule returns the rule of the selected row of the reachability page
+func (a *App) selectedReachabilityRule(table *tview.Table) (service.ReachabilityRule, bool) {
+	reference, ok := ui.SelectedReference(table)
+	if !ok || a.reachResult == nil {
+		return service.ReachabilityRule{}, false
+	}
+	index, err := strconv.Atoi(reference)
+	if err != nil || index >= len(a.reachResult.Rules) {
+		return service.ReachabilityRule{}, false
+	}
+	return a.reachResult.Rules[index], true
+}
+
+// leaveReachability goes back from the reachability page to the ECS list
+func (a *App) leaveReachability() {
+	a.sgReturnPage, a.sgReturnFocus = "", nil
+	a.handleNavigation(ui.PageEcsList, a.ecsInstanceTable)
+}
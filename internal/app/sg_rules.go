@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// defaultRulePriority is the priority offered for new rules, the one evaluated first
+const defaultRulePriority = 1
+
+// setupSecurityGroupRuleKeyHandlers sets up key handlers for editing the rules of a
+// security group
+func (a *App) setupSecurityGroupRuleKeyHandlers(table *tview.Table) {
+	originalInputCapture := table.GetInputCapture()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'A': // Add a rule
+			a.showAddSecurityGroupRuleForm(table)
+			return nil
+		case 'E': // Edit the selected rule
+			a.showEditSecurityGroupRuleForm(table)
+			return nil
+		case 'D': // Revoke the selected rule
+			a.confirmRevokeSecurityGroupRule(table)
+			return nil
+		}
+
+		// Call original input capture if it exists
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+}
+
+// selectedSecurityGroupRule returns the rule of the selected row of the rules list. Rules
+// are edited and revoked by ID, so a rule without one is reported and not returned.
+func (a *App) selectedSecurityGroupRule(table *tview.Table) (ecs.Permission, bool) {
+	ruleId, ok := ui.SelectedReference(table)
+	if !ok || a.currentSecurityGroupRules == nil {
+		return ecs.Permission{}, false
+	}
+	if ruleId == "" {
+		a.showErrorModal("This rule has no rule ID and cannot be changed from here.")
+		return ecs.Permission{}, false
+	}
+	for _, rule := range a.currentSecurityGroupRules.Permissions.Permission {
+		if rule.SecurityGroupRuleId == ruleId {
+			return rule, true
+		}
+	}
+	return ecs.Permission{}, false
+}
+
+// openToInternet reports whether a rule accepts traffic from or to any address
+func openToInternet(spec service.SecurityGroupRuleSpec) bool {
+	return spec.Policy == "accept" && service.OpenToAnyAddress(spec.Peer)
+}
+
+// showAddSecurityGroupRuleForm asks for a new rule of the current security group and
+// previews the call adding it
+func (a *App) showAddSecurityGroupRuleForm(table *tview.Table) {
+	if a.currentSecurityGroupRules == nil || !a.checkWritable() {
+		return
+	}
+	securityGroupId := a.currentSecurityGroupRules.SecurityGroupId
+	spec := service.SecurityGroupRuleSpec{
+		Direction:  service.SecurityGroupRuleIngress,
+		IpProtocol: "TCP",
+		Policy:     "accept",
+		Priority:   defaultRulePriority,
+	}
+
+	ui.ShowSecurityGroupRuleForm(a.pages, a.tviewApp, fmt.Sprintf("Add rule to %s", securityGroupId), spec, false,
+		func(entered service.SecurityGroupRuleSpec) {
+			call, err := service.AuthorizeSecurityGroupRuleCall(securityGroupId, entered)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to build the request: %v", err))
+				return
+			}
+			message := fmt.Sprintf("Add rule to security group %s?\n\n%s", securityGroupId, service.DescribeRule(entered))
+			if openToInternet(entered) {
+				message += "\n\nThe rule applies to every address on the internet."
+			}
+			a.confirmSecurityGroupRuleCall(table, message, call, "Add",
+				func(ctx context.Context, services *Services) error {
+					return services.ECS.AuthorizeSecurityGroupRule(ctx, securityGroupId, entered)
+				})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// showEditSecurityGroupRuleForm edits the selected rule in place and previews the call
+// changing it
+func (a *App) showEditSecurityGroupRuleForm(table *tview.Table) {
+	rule, ok := a.selectedSecurityGroupRule(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	securityGroupId := a.currentSecurityGroupRules.SecurityGroupId
+	current := service.SecurityGroupRuleSpecOf(rule)
+
+	ui.ShowSecurityGroupRuleForm(a.pages, a.tviewApp, fmt.Sprintf("Edit rule %s", rule.SecurityGroupRuleId), current, true,
+		func(entered service.SecurityGroupRuleSpec) {
+			if entered == current {
+				a.tviewApp.SetFocus(table)
+				return
+			}
+			call, err := service.ModifySecurityGroupRuleCall(securityGroupId, rule.SecurityGroupRuleId, entered)
+			if err != nil {
+				a.showErrorModal(fmt.Sprintf("Failed to build the request: %v", err))
+				return
+			}
+			message := fmt.Sprintf("Change rule %s of security group %s?\n\nFrom: %s\nTo:   %s",
+				rule.SecurityGroupRuleId, securityGroupId,
+				service.DescribeRule(current), service.DescribeRule(entered))
+			if openToInternet(entered) && !openToInternet(current) {
+				message += "\n\nThe rule applies to every address on the internet."
+			}
+			a.confirmSecurityGroupRuleCall(table, message, call, "Change",
+				func(ctx context.Context, services *Services) error {
+					return services.ECS.ModifySecurityGroupRule(ctx, securityGroupId, rule.SecurityGroupRuleId, entered)
+				})
+		},
+		func() { a.tviewApp.SetFocus(table) })
+}
+
+// confirmRevokeSecurityGroupRule asks to revoke the selected rule, a destructive action
+// since it may cut off traffic, and previews the call
+func (a *App) confirmRevokeSecurityGroupRule(table *tview.Table) {
+	rule, ok := a.selectedSecurityGroupRule(table)
+	if !ok || !a.checkWritable() {
+		return
+	}
+	securityGroupId := a.currentSecurityGroupRules.SecurityGroupId
+	spec := service.SecurityGroupRuleSpecOf(rule)
+	call, err := service.RevokeSecurityGroupRuleCall(securityGroupId, rule.SecurityGroupRuleId, spec.Direction)
+	if err != nil {
+		a.showErrorModal(fmt.Sprintf("Failed to build the request: %v", err))
+		return
+	}
+
+	message := fmt.Sprintf("Revoke rule %s of security group %s?\n\n%s\n\nRequest:\n%s",
+		rule.SecurityGroupRuleId, securityGroupId, service.DescribeRule(spec), call)
+	a.confirmDestructive(table, message, rule.SecurityGroupRuleId, []string{"Revoke"}, func(string) {
+		services := a.servicesFor(securityGroupId)
+		a.runWrite(fmt.Sprintf("revoke rule %s", rule.SecurityGroupRuleId),
+			func(ctx context.Context) error {
+				return services.ECS.RevokeSecurityGroupRule(ctx, securityGroupId, rule.SecurityGroupRuleId, spec.Direction)
+			},
+			func() { a.refreshSecurityGroupRules(securityGroupId) })
+	})
+}
+
+// confirmSecurityGroupRuleCall shows the request a rule change sends, parameter by
+// parameter, and sends it once confirmed. A rule change can open a port to the internet
+// as easily as a revoke can cut off traffic, so production profiles ask to type the
+// security group ID like for other destructive actions.
+func (a *App) confirmSecurityGroupRuleCall(table *tview.Table, message string, call service.APICall, action string, send func(ctx context.Context, services *Services) error) {
+	securityGroupId := a.currentSecurityGroupRules.SecurityGroupId
+	message = fmt.Sprintf("%s\n\nRequest:\n%s", message, call)
+	a.confirmDestructive(table, message, securityGroupId, []string{action}, func(string) {
+		services := a.servicesFor(securityGroupId)
+		a.runWrite(fmt.Sprintf("%s on security group %s", call.Action, securityGroupId),
+			func(ctx context.Context) error { return send(ctx, services) },
+			func() { a.refreshSecurityGroupRules(securityGroupId) })
+	})
+}
+
+// refreshSecurityGroupRules refreshes the rules list after a write, unless the user has
+// moved on to another page or security group meanwhile
+func (a *App) refreshSecurityGroupRules(securityGroupId string) {
+	if page, _ := a.pages.GetFrontPage(); page != ui.PageSecurityGroupRules ||
+		a.currentSecurityGroupRules == nil || a.currentSecurityGroupRules.SecurityGroupId != securityGroupId {
+		return
+	}
+	a.refreshPage(ui.PageSecurityGroupRules)
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/ui"
+)
+
+// listSnapshot is the on-disk form of a cached resource list
+type listSnapshot[T any] struct {
+	Items []T `json:"items"`
+	// Region of each resource, only saved in the all-regions mode
+	Regions map[string]string `json:"regions,omitempty"`
+}
+
+// SetCacheTTL enables the on-disk cache of the list pages. Cached lists older than ttl are
+// refreshed in the background after they are shown.
+func (a *App) SetCacheTTL(ttl time.Duration) {
+	a.cacheEnabled = true
+	a.cacheTTL = ttl
+}
+
+// diskCache returns the on-disk cache of the current profile and region, or nil if
+// caching is disabled. The all-regions mode has a cache of its own.
+func (a *App) diskCache() *service.Cache {
+	if !a.cacheEnabled {
+		return nil
+	}
+	region := a.currentRegion
+	if a.allRegionsMode {
+		region = ui.AllRegions
+	}
+	cache, err := service.NewCache(a.currentProfile, region)
+	if err != nil {
+		return nil
+	}
+	return cache
+}
+
+// restoreSnapshot fills target with the cached list of a page and reports whether there
+// was one. The page shows the age of the snapshot and is refreshed in the background once
+// the snapshot is older than the cache TTL.
+func restoreSnapshot[T any](a *App, page string, target *[]T) bool {
+	cache := a.diskCache()
+	if cache == nil {
+		return false
+	}
+
+	var snap listSnapshot[T]
+	savedAt, err := cache.Load(page, &snap)
+	if err != nil {
+		// A missing or unreadable snapshot just means loading from the API
+		return false
+	}
+
+	*target = nonNil(snap.Items)
+	if len(snap.Regions) > 0 {
+		if a.resourceRegions == nil {
+			a.resourceRegions = make(map[string]string)
+		}
+		for id, regionId := range snap.Regions {
+			a.resourceRegions[id] = regionId
+		}
+	}
+	a.snapshotTimes[page] = savedAt
+
+	if time.Since(savedAt) >= a.cacheTTL {
+		// Refresh once the page has been rendered, unless the user has moved on by then
+		go a.tviewApp.QueueUpdateDraw(func() {
+			if front, _ := a.pages.GetFrontPage(); front == page && !a.isLoading() {
+				a.refreshPage(page)
+			}
+		})
+	}
+	return true
+}
+
+// saveSnapshot stores freshly loaded items as the cached list of a page. Failing to write
+// the cache is not an error worth interrupting the user for, so it is ignored.
+func saveSnapshot[T any](a *App, page string, items []T) {
+	delete(a.snapshotTimes, page)
+
+	cache := a.diskCache()
+	if cache == nil {
+		return
+	}
+	snap := listSnapshot[T]{Items: items}
+	if a.allRegionsMode {
+		snap.Regions = a.resourceRegions
+	}
+	_ = cache.Save(page, snap)
+}
+
+// updateListModeLine updates the mode line for a page, including the age of the cached
+// data it shows, if any
+func (a *App) updateListModeLine(page string) {
+	savedAt, ok := a.snapshotTimes[page]
+	if !ok {
+		ui.UpdateModeLineWithShortcuts(a.modeLine, a.modeLineContext(), page)
+		return
+	}
+	pageInfo := fmt.Sprintf("Cached %s ago", formatAge(time.Since(savedAt)))
+	ui.UpdateModeLineWithPageInfoAndShortcuts(a.modeLine, a.modeLineContext(), page, pageInfo)
+}
+
+// formatAge formats a duration in its largest whole unit, e.g. "3m" or "2d"
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,328 @@
+// Package cli implements tali's command line: the headless subcommands that print
+// resources for scripts and CI, and the flags shared with the interactive TUI.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"aliyun-tui-viewer/internal/app"
+	"aliyun-tui-viewer/internal/client"
+	"aliyun-tui-viewer/internal/config"
+	"aliyun-tui-viewer/internal/service"
+)
+
+// options holds the parsed command line flags
+type options struct {
+	output   string
+	profile  string
+	region   string
+	watch    time.Duration
+	readOnly bool
+	yes      bool
+	confirm  string // Resource ID confirming the changes of a command, see confirmApply
+}
+
+// Run parses the command line arguments (without the program name). Without a
+// subcommand it starts the TUI; otherwise it runs the subcommand and prints the
+// result to stdout.
+func Run(args []string) error {
+	opts := &options{}
+	flags := flag.NewFlagSet("tali", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.output, "output", OutputTable, "")
+	flags.StringVar(&opts.output, "o", OutputTable, "")
+	flags.StringVar(&opts.profile, "profile", "", "")
+	flags.StringVar(&opts.region, "region", "", "")
+	flags.DurationVar(&opts.watch, "watch", 0, "")
+	flags.BoolVar(&opts.readOnly, "read-only", false, "")
+	flags.BoolVar(&opts.yes, "yes", false, "")
+	flags.BoolVar(&opts.yes, "y", false, "")
+	flags.StringVar(&opts.confirm, "confirm", "", "")
+
+	positional, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(os.Stdout)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w (run 'tali --help' for usage)", err)
+	}
+
+	if opts.watch < 0 {
+		return fmt.Errorf("invalid --watch interval %s", opts.watch)
+	}
+	if len(positional) == 0 {
+		return runTUI(opts)
+	}
+	if positional[0] == "help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	if err := validateOutput(opts.output); err != nil {
+		return err
+	}
+	if opts.watch != 0 {
+		return errors.New("--watch only applies to the interactive TUI")
+	}
+
+	cmd, cmdArgs, err := resolveCommand(positional)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(opts.profile, opts.region, opts.readOnly)
+	if err != nil {
+		return err
+	}
+	services, err := newServices(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels the requests that are still in flight
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var result *Result
+	if cmd.RunGroups != nil {
+		result, err = cmd.RunGroups(ctx, newGroupResolver(opts, cfg, services), cmdArgs)
+	} else {
+		result, err = cmd.Run(ctx, services, cmdArgs)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeResult(os.Stdout, opts.output, result); err != nil {
+		return err
+	}
+	if cmd.Apply == nil || result.Confirm == "" {
+		return nil
+	}
+
+	// The plan above doubles as a dry run in read-only mode
+	production := cfg.Production
+	if result.Services != nil {
+		services, production = result.Services, result.Production
+	}
+	if services.Writes.ReadOnly() {
+		return fmt.Errorf("not applied: %w: %s", service.ErrReadOnly, services.Writes.Reason())
+	}
+	confirmed, err := confirmApply(os.Stdin, os.Stderr, opts, production, result)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Fprintln(os.Stderr, "Nothing applied")
+		return nil
+	}
+	return cmd.Apply(ctx, services, result)
+}
+
+// confirmApply asks whether to apply the changes a command planned. Production profiles
+// want the resource ID typed, or passed with --confirm, like destructive actions in the
+// TUI, and refuse --yes; elsewhere --yes skips the question. A --confirm that does not
+// match the resource ID is an error wherever it is given.
+func confirmApply(r io.Reader, w io.Writer, opts *options, production bool, result *Result) (bool, error) {
+	switch {
+	case opts.confirm != "":
+		if opts.confirm != result.ConfirmId {
+			return false, fmt.Errorf("not applied: --confirm=%s does not match %s", opts.confirm, result.ConfirmId)
+		}
+		return true, nil
+	case production && opts.yes:
+		return false, fmt.Errorf("not applied: --yes does not apply to production profiles, pass --confirm=%s instead", result.ConfirmId)
+	case production:
+		return confirmTyped(r, w, result.Confirm, result.ConfirmId), nil
+	case opts.yes:
+		return true, nil
+	}
+	return confirm(r, w, result.Confirm), nil
+}
+
+// confirmTyped asks a question on w that is only answered yes by typing expected, read
+// from r
+func confirmTyped(r io.Reader, w io.Writer, question, expected string) bool {
+	fmt.Fprintf(w, "%s\nThe profile is a production profile. Type %s to confirm: ", question, expected)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	return strings.TrimSpace(answer) == expected
+}
+
+// confirm asks a yes/no question on w and reads the answer from r. Anything but "y" or
+// "yes", including end of input, is a no.
+func confirm(r io.Reader, w io.Writer, question string) bool {
+	fmt.Fprintf(w, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// parseInterspersed parses flags that may appear before, between or after the
+// positional arguments, so both "tali --output json ecs list" and
+// "tali ecs list --output json" work
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveCommand finds the subcommand named by the positional arguments and checks its arguments
+func resolveCommand(positional []string) (*command, []string, error) {
+	group := positional[0]
+	if !hasGroup(group) {
+		return nil, nil, fmt.Errorf("unknown command '%s' (run 'tali --help' for usage)", group)
+	}
+	if len(positional) < 2 {
+		return nil, nil, fmt.Errorf("missing subcommand for '%s' (run 'tali --help' for usage)", group)
+	}
+
+	cmd := findCommand(group, positional[1])
+	if cmd == nil {
+		return nil, nil, fmt.Errorf("unknown command '%s %s' (run 'tali --help' for usage)", group, positional[1])
+	}
+
+	cmdArgs := positional[2:]
+	if len(cmdArgs) != len(cmd.Args) {
+		return nil, nil, fmt.Errorf("usage: tali %s", cmd.usage())
+	}
+	return cmd, cmdArgs, nil
+}
+
+// loadConfig loads the configuration of a profile and region, the current ones when empty
+func loadConfig(profile, region string, readOnly bool) (*config.Config, error) {
+	cfg, err := config.LoadProfileConfig(profile, region)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if readOnly {
+		cfg.SetReadOnly()
+	}
+	return cfg, nil
+}
+
+// newServices creates SDK-backed services for a configuration
+func newServices(cfg *config.Config) (*app.Services, error) {
+	clients, err := client.NewAliyunClients(client.NewConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("creating clients: %w", err)
+	}
+	return app.NewServices(clients, cfg), nil
+}
+
+// groupResolver returns the services for a security group reference, whether their
+// profile is a production profile, and the reference with its profile and region filled in
+type groupResolver func(ref service.SecurityGroupRef) (*app.Services, bool, service.SecurityGroupRef, error)
+
+// newGroupResolver returns the groupResolver of the command line. References naming
+// neither another profile nor another region get services, the command line's; the
+// services of the others are created on first use, a profile's region being the one
+// selected on the command line when the reference names none.
+func newGroupResolver(opts *options, cfg *config.Config, services *app.Services) groupResolver {
+	type resolved struct {
+		services        *app.Services
+		production      bool
+		profile, region string
+	}
+	byRef := map[[2]string]resolved{}
+
+	return func(ref service.SecurityGroupRef) (*app.Services, bool, service.SecurityGroupRef, error) {
+		if (ref.Profile == "" || ref.Profile == cfg.Profile) && (ref.Region == "" || ref.Region == cfg.RegionID) {
+			return services, cfg.Production, ref.Resolve(cfg.Profile, cfg.RegionID), nil
+		}
+
+		key := [2]string{ref.Profile, ref.Region}
+		group, ok := byRef[key]
+		if !ok {
+			profile, region := opts.profile, opts.region
+			if ref.Profile != "" {
+				profile = ref.Profile
+			}
+			if ref.Region != "" {
+				region = ref.Region
+			}
+			groupCfg, err := loadConfig(profile, region, opts.readOnly)
+			if err != nil {
+				return nil, false, ref, fmt.Errorf("%s: %w", ref, err)
+			}
+			groupServices, err := newServices(groupCfg)
+			if err != nil {
+				return nil, false, ref, fmt.Errorf("%s: %w", ref, err)
+			}
+			group = resolved{services: groupServices, production: groupCfg.Production, profile: groupCfg.Profile, region: groupCfg.RegionID}
+			byRef[key] = group
+		}
+		return group.services, group.production, ref.Resolve(group.profile, group.region), nil
+	}
+}
+
+// runTUI starts the interactive application
+func runTUI(opts *options) error {
+	application, err := app.New(app.Options{
+		Profile:  opts.profile,
+		Region:   opts.region,
+		Watch:    opts.watch,
+		ReadOnly: opts.readOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("initializing application: %w", err)
+	}
+
+	if err := application.Run(); err != nil {
+		return fmt.Errorf("running application: %w", err)
+	}
+	return nil
+}
+
+// printUsage prints the command line help
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  tali [flags]                 Start the interactive TUI")
+	fmt.Fprintln(w, "  tali <command> [flags]       Print resources and exit")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	width := 0
+	for _, cmd := range commands {
+		if len(cmd.usage()) > width {
+			width = len(cmd.usage())
+		}
+	}
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s%s  %s\n", cmd.usage(), strings.Repeat(" ", width-len(cmd.usage())), cmd.Summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  -o, --output <format>   Output format: table, json, yaml or csv (default table)")
+	fmt.Fprintln(w, "      --profile <name>    Use this profile instead of the current one")
+	fmt.Fprintln(w, "      --region <id>       Use this region instead of the profile's region_id")
+	fmt.Fprintln(w, "      --watch <interval>  Refresh the list page in front every interval, e.g. 10s (TUI only)")
+	fmt.Fprintln(w, "      --read-only         Refuse every write operation, whatever the profile allows")
+	fmt.Fprintln(w, "  -y, --yes               Apply changes without asking for confirmation (not on production profiles)")
+	fmt.Fprintln(w, "      --confirm <id>      Apply changes to this resource without asking, e.g. a domain or security group")
+	fmt.Fprintln(w, "  -h, --help              Show this help")
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		output     string
+		yes        bool
+		err        string
+	}{
+		{name: "flags first", args: []string{"--output", "json", "ecs", "list"}, positional: []string{"ecs", "list"}, output: "json"},
+		{name: "flags last", args: []string{"ecs", "list", "-o", "yaml"}, positional: []string{"ecs", "list"}, output: "yaml"},
+		{name: "flags between", args: []string{"dns", "-y", "import", "example.com", "--output=csv", "zone.txt"}, positional: []string{"dns", "import", "example.com", "zone.txt"}, output: "csv", yes: true},
+		{name: "after --", args: []string{"oss", "ls", "--", "-o"}, positional: []string{"oss", "ls", "-o"}, output: "table"},
+		{name: "no arguments", output: "table"},
+		{name: "unknown flag", args: []string{"ecs", "list", "--colour"}, err: "flag provided but not defined: -colour"},
+		{name: "missing value", args: []string{"ecs", "list", "-o"}, err: "flag needs an argument: -o"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &options{}
+			flags := flag.NewFlagSet("tali", flag.ContinueOnError)
+			flags.SetOutput(io.Discard)
+			flags.StringVar(&opts.output, "output", OutputTable, "")
+			flags.StringVar(&opts.output, "o", OutputTable, "")
+			flags.BoolVar(&opts.yes, "y", false, "")
+
+			positional, err := parseInterspersed(flags, tt.args)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("got %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) || opts.output != tt.output || opts.yes != tt.yes {
+				t.Errorf("got %q, output %s, yes %v", positional, opts.output, opts.yes)
+			}
+		})
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	tests := []struct {
+		positional []string
+		command    string
+		args       []string
+		err        string
+	}{
+		{positional: []string{"ecs", "list"}, command: "ecs list"},
+		{positional: []string{"dns", "records", "example.com"}, command: "dns records <domain>", args: []string{"example.com"}},
+		{positional: []string{"ec2", "list"}, err: "unknown command 'ec2' (run 'tali --help' for usage)"},
+		{positional: []string{"ecs"}, err: "missing subcommand for 'ecs' (run 'tali --help' for usage)"},
+		{positional: []string{"ecs", "ls"}, err: "unknown command 'ecs ls' (run 'tali --help' for usage)"},
+		{positional: []string{"dns", "records"}, err: "usage: tali dns records <domain>"},
+		{positional: []string{"ecs", "list", "extra"}, err: "usage: tali ecs list"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.positional, " "), func(t *testing.T) {
+			cmd, args, err := resolveCommand(tt.positional)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("got %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(args) == 0 {
+				args = nil
+			}
+			if cmd.usage() != tt.command || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("got %s with %q, want %s with %q", cmd.usage(), args, tt.command, tt.args)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"yep\n", false},
+		{"", false}, // End of input
+	}
+	for _, tt := range tests {
+		var prompt bytes.Buffer
+		if got := confirm(strings.NewReader(tt.input), &prompt, "Apply 2 changes?"); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.input, got, tt.want)
+		}
+		if prompt.String() != "Apply 2 changes? [y/N] " {
+			t.Errorf("prompt %q", prompt.String())
+		}
+	}
+}
+
+func TestConfirmApply(t *testing.T) {
+	result := &Result{Confirm: "Apply 2 changes to example.com?", ConfirmId: "example.com"}
+	tests := []struct {
+		name       string
+		opts       options
+		production bool
+		input      string
+		want       bool
+		err        string
+	}{
+		{name: "yes answered", input: "y\n", want: true},
+		{name: "no answered", input: "\n"},
+		{name: "--yes", opts: options{yes: true}, want: true},
+		{name: "--confirm", opts: options{confirm: "example.com"}, want: true},
+		{name: "--confirm of another resource", opts: options{confirm: "example.org"}, err: "--confirm=example.org does not match example.com"},
+		{name: "production, ID typed", production: true, input: "example.com\n", want: true},
+		{name: "production, yes answered", production: true, input: "y\n"},
+		{name: "production, --yes", production: true, opts: options{yes: true}, err: "pass --confirm=example.com instead"},
+		{name: "production, --confirm", production: true, opts: options{yes: true, confirm: "example.com"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confirmApply(strings.NewReader(tt.input), io.Discard, &tt.opts, tt.production, result)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got %v, %v; want an error with %q", got, err, tt.err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,825 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"aliyun-tui-viewer/internal/app"
+	"aliyun-tui-viewer/internal/service"
+)
+
+// command is a headless subcommand such as "ecs list"
+type command struct {
+	Group   string // Service group, e.g. "ecs"
+	Name    string // Subcommand name, e.g. "list"
+	Args    []string
+	Summary string
+	Run     func(ctx context.Context, services *app.Services, args []string) (*Result, error)
+
+	// RunGroups is set instead of Run by commands naming security groups as
+	// [profile:][region/]sg-id, which may be in other profiles and regions than the
+	// command line's; groups resolves them to their services
+	RunGroups func(ctx context.Context, groups groupResolver, args []string) (*Result, error)
+
+	// Apply, if set, carries out the changes planned by Run once they are confirmed
+	Apply func(ctx context.Context, services *app.Services, plan *Result) error
+}
+
+// usage returns the command line synopsis of the command
+func (c *command) usage() string {
+	parts := []string{c.Group, c.Name}
+	for _, arg := range c.Args {
+		parts = append(parts, "<"+arg+">")
+	}
+	return strings.Join(parts, " ")
+}
+
+// ossListPageSize is the page size used when listing all objects under a prefix
+const ossListPageSize = 1000
+
+// commands lists every headless subcommand in the order shown in the usage text
+var commands = []*command{
+	{Group: "ecs", Name: "list", Summary: "List ECS instances", Run: runEcsList},
+	{Group: "ecs", Name: "security-groups", Summary: "List security groups", Run: runEcsSecurityGroups},
+	{Group: "ecs", Name: "rules", Args: []string{"security-group-id"}, Summary: "List the rules of a security group", Run: runEcsRules},
+	{Group: "ecs", Name: "audit", Summary: "Audit every security group for exposed ports, wide port ranges, unused groups and duplicate or shadowed rules", Run: runEcsAudit},
+	{Group: "ecs", Name: "reach", Args: []string{"source", "instance-id", "port"}, Summary: "Check whether the security groups of an instance let traffic from an instance, IP or CIDR in; port is 443, udp/53 or icmp", Run: runEcsReach},
+	{Group: "ecs", Name: "diff", Args: []string{"security-group", "security-group"}, Summary: "Compare the rules of two security groups; a group is [profile:][region/]sg-id", RunGroups: runEcsDiff},
+	{Group: "ecs", Name: "clone", Args: []string{"source-group", "target-group"}, Summary: "Show and apply the changes that give the target security group every rule of the source", RunGroups: runEcsClone, Apply: applyEcsClone},
+	{Group: "dns", Name: "domains", Summary: "List DNS domains", Run: runDnsDomains},
+	{Group: "dns", Name: "records", Args: []string{"domain"}, Summary: "List the records of a domain", Run: runDnsRecords},
+	{Group: "dns", Name: "export", Args: []string{"domain"}, Summary: "Print the records of a domain as a BIND zone file (YAML with -o yaml)", Run: runDnsExport},
+	{Group: "dns", Name: "import", Args: []string{"domain", "file"}, Summary: "Show and apply the changes that make a domain match a zone file", Run: runDnsImport, Apply: applyDnsImport},
+	{Group: "slb", Name: "list", Summary: "List SLB instances", Run: runSlbList},
+	{Group: "slb", Name: "listeners", Args: []string{"load-balancer-id"}, Summary: "List the listeners of an SLB instance", Run: runSlbListeners},
+	{Group: "slb", Name: "vserver-groups", Args: []string{"load-balancer-id"}, Summary: "List the virtual server groups of an SLB instance", Run: runSlbVServerGroups},
+	{Group: "oss", Name: "buckets", Summary: "List OSS buckets", Run: runOssBuckets},
+	{Group: "oss", Name: "ls", Args: []string{"bucket[/prefix]"}, Summary: "List the objects in a bucket, optionally under a prefix", Run: runOssLs},
+	{Group: "oss", Name: "upload", Args: []string{"file", "bucket/key"}, Summary: "Upload a local file as an object; a key ending in / gets the file name appended", Run: runOssUpload, Apply: applyOssUpload},
+	{Group: "rds", Name: "list", Summary: "List RDS instances", Run: runRdsList},
+	{Group: "rds", Name: "databases", Args: []string{"instance-id"}, Summary: "List the databases of an RDS instance", Run: runRdsDatabases},
+	{Group: "rds", Name: "accounts", Args: []string{"instance-id"}, Summary: "List the accounts of an RDS instance", Run: runRdsAccounts},
+	{Group: "redis", Name: "list", Summary: "List Redis instances", Run: runRedisList},
+	{Group: "redis", Name: "accounts", Args: []string{"instance-id"}, Summary: "List the accounts of a Redis instance", Run: runRedisAccounts},
+	{Group: "rocketmq", Name: "list", Summary: "List RocketMQ instances", Run: runRocketMQList},
+	{Group: "rocketmq", Name: "topics", Args: []string{"instance-id"}, Summary: "List the topics of a RocketMQ instance", Run: runRocketMQTopics},
+	{Group: "rocketmq", Name: "groups", Args: []string{"instance-id"}, Summary: "List the consumer groups of a RocketMQ instance", Run: runRocketMQGroups},
+}
+
+// findCommand looks up a subcommand by group and name
+func findCommand(group, name string) *command {
+	for _, cmd := range commands {
+		if cmd.Group == group && cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// hasGroup reports whether any subcommand belongs to the group
+func hasGroup(group string) bool {
+	for _, cmd := range commands {
+		if cmd.Group == group {
+			return true
+		}
+	}
+	return false
+}
+
+func runEcsList(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	instances, err := services.ECS.FetchInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    instances,
+		Headers: []string{"Instance ID", "Status", "Zone", "CPU/RAM", "Private IP", "Public IP", "Name", "Expired Time"},
+	}
+	for _, instance := range instances {
+		privateIP := ""
+		if len(instance.VpcAttributes.PrivateIpAddress.IpAddress) > 0 {
+			privateIP = instance.VpcAttributes.PrivateIpAddress.IpAddress[0]
+		} else if len(instance.InnerIpAddress.IpAddress) > 0 {
+			privateIP = instance.InnerIpAddress.IpAddress[0]
+		}
+
+		publicIP := ""
+		if len(instance.PublicIpAddress.IpAddress) > 0 {
+			publicIP = instance.PublicIpAddress.IpAddress[0]
+		} else if instance.EipAddress.IpAddress != "" {
+			publicIP = instance.EipAddress.IpAddress
+		}
+
+		result.Rows = append(result.Rows, []string{
+			instance.InstanceId,
+			instance.Status,
+			instance.ZoneId,
+			fmt.Sprintf("%dC/%dG", instance.Cpu, instance.Memory/1024),
+			privateIP,
+			publicIP,
+			instance.InstanceName,
+			instance.ExpiredTime,
+		})
+	}
+	return result, nil
+}
+
+func runEcsSecurityGroups(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	securityGroups, err := services.ECS.FetchSecurityGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    securityGroups,
+		Headers: []string{"Security Group ID", "Name", "Description", "VPC ID", "Type", "Creation Time"},
+	}
+	for _, sg := range securityGroups {
+		result.Rows = append(result.Rows, []string{sg.SecurityGroupId, sg.SecurityGroupName, sg.Description, sg.VpcId, sg.SecurityGroupType, sg.CreationTime})
+	}
+	return result, nil
+}
+
+func runEcsRules(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	response, err := services.ECS.FetchSecurityGroupRules(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    response.Permissions.Permission,
+		Headers: []string{"Direction", "Protocol", "Port Range", "Source/Dest", "Policy", "Priority", "Description"},
+	}
+	for _, rule := range response.Permissions.Permission {
+		spec := service.SecurityGroupRuleSpecOf(rule)
+		direction := "Ingress"
+		if spec.Direction == service.SecurityGroupRuleEgress {
+			direction = "Egress"
+		}
+		result.Rows = append(result.Rows, []string{direction, spec.IpProtocol, spec.PortRange, spec.Peer, spec.Policy, strconv.Itoa(spec.Priority), spec.Description})
+	}
+	return result, nil
+}
+
+func runEcsAudit(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	findings, err := service.AuditSecurityGroups(ctx, services.ECS)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    findings,
+		Headers: []string{"Severity", "Finding", "Security Group ID", "Name", "Rule ID", "Rule", "Detail", "Instances"},
+	}
+	for _, finding := range findings {
+		result.Rows = append(result.Rows, []string{
+			finding.Severity,
+			finding.Kind,
+			finding.SecurityGroupId,
+			finding.SecurityGroupName,
+			finding.RuleId,
+			finding.Rule,
+			finding.Detail,
+			strings.Join(finding.InstanceIds, ","),
+		})
+	}
+	return result, nil
+}
+
+func runEcsReach(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	query := service.ReachabilityQuery{Source: args[0], InstanceId: args[1]}
+	var err error
+	if query.Protocol, query.Port, err = parseReachabilityPort(args[2]); err != nil {
+		return nil, err
+	}
+	reachability, err := service.CheckReachability(ctx, services.ECS, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    reachability,
+		Headers: []string{"Security Group ID", "Rule ID", "Rule", "Match", "Reason"},
+	}
+	for _, rule := range reachability.Rules {
+		match := rule.Match
+		if rule.Decides {
+			match += " (decides)"
+		}
+		result.Rows = append(result.Rows, []string{rule.SecurityGroupId, rule.RuleId, rule.Rule, match, rule.Reason})
+	}
+
+	var text strings.Builder
+	verdict := "DROPPED"
+	if reachability.Allowed {
+		verdict = "ALLOWED"
+	}
+	fmt.Fprintf(&text, "%s %s -> %s %s\n%s\n", verdict, reachability.Source, reachability.Destination, query.Traffic(), reachability.Verdict)
+	for _, note := range reachability.Notes {
+		fmt.Fprintf(&text, "Note: %s\n", note)
+	}
+	text.WriteString("\n")
+	if err := writeTable(&text, result.Headers, result.Rows); err != nil {
+		return nil, err
+	}
+	result.Text = text.String()
+	return result, nil
+}
+
+// parseReachabilityPort parses the port of a reachability check: "443" for TCP, or the
+// protocol first as in "udp/53" and "icmp"
+func parseReachabilityPort(text string) (protocol string, port int, err error) {
+	protocol, portText, found := strings.Cut(strings.ToUpper(text), "/")
+	if !found {
+		protocol, portText = "TCP", text
+		if _, err := strconv.Atoi(text); err != nil {
+			protocol, portText = strings.ToUpper(text), ""
+		}
+	}
+	if portText != "" {
+		if port, err = strconv.Atoi(portText); err != nil {
+			return "", 0, fmt.Errorf("invalid port %q", text)
+		}
+	}
+	return protocol, port, nil
+}
+
+func runEcsDiff(ctx context.Context, groups groupResolver, args []string) (*Result, error) {
+	left, right, _, _, err := fetchRuleSets(ctx, groups, args)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := service.DiffSecurityGroupRules(left, right)
+	result := &Result{
+		Data:    diff,
+		Headers: []string{"Status", "Direction", "Protocol", "Port Range", "Source/Dest", "Left", "Right"},
+	}
+	for _, line := range diff {
+		spec := line.Spec()
+		result.Rows = append(result.Rows, []string{
+			line.Kind,
+			spec.Direction,
+			spec.IpProtocol,
+			spec.PortRange,
+			spec.Peer,
+			service.DescribeRuleDecision(line.Left),
+			service.DescribeRuleDecision(line.Right),
+		})
+	}
+
+	counts := service.CountRuleDiffs(diff)
+	var text strings.Builder
+	fmt.Fprintf(&text, "Left:  %s %s\nRight: %s %s\n", left.Ref, left.Name, right.Ref, right.Name)
+	fmt.Fprintf(&text, "%d same, %d changed, %d only in left, %d only in right\n\n", counts[service.RuleDiffSame],
+		counts[service.RuleDiffChanged], counts[service.RuleDiffLeftOnly], counts[service.RuleDiffRightOnly])
+	if err := writeTable(&text, result.Headers, result.Rows); err != nil {
+		return nil, err
+	}
+	result.Text = text.String()
+	return result, nil
+}
+
+func runEcsClone(ctx context.Context, groups groupResolver, args []string) (*Result, error) {
+	source, target, targetServices, production, err := fetchRuleSets(ctx, groups, args)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := service.PlanSecurityGroupClone(source, target)
+	result := &Result{
+		Data:      plan,
+		Headers:   []string{"Action", "Rule ID", "Direction", "Protocol", "Port Range", "Source/Dest", "Policy", "Priority", "Description", "Reason"},
+		ConfirmId: plan.Target.SecurityGroupId,
+		// The rules are cloned with the services that fetched the target's
+		Services:   targetServices,
+		Production: production,
+	}
+	for _, step := range plan.Steps {
+		spec := step.Spec
+		result.Rows = append(result.Rows, []string{
+			step.Action,
+			step.RuleId,
+			spec.Direction,
+			spec.IpProtocol,
+			spec.PortRange,
+			spec.Peer,
+			spec.Policy,
+			strconv.Itoa(spec.Priority),
+			spec.Description,
+			step.Reason,
+		})
+	}
+	if changes := plan.Changes(); changes > 0 {
+		result.Confirm = fmt.Sprintf("Apply %d changes to %s (%d skipped)?", changes, plan.Target, len(plan.Steps)-changes)
+	}
+	return result, nil
+}
+
+func applyEcsClone(ctx context.Context, services *app.Services, plan *Result) error {
+	clone := plan.Data.(*service.SecurityGroupClonePlan)
+	applied, err := service.ApplySecurityGroupClone(ctx, services.ECS, clone)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Applied %d changes to %s\n", applied, clone.Target)
+	return nil
+}
+
+// fetchRuleSets fetches the rules of the two security groups named by args, each in its
+// own profile and region, and returns them with the services of the right one and whether
+// its profile is a production profile
+func fetchRuleSets(ctx context.Context, groups groupResolver, args []string) (left, right *service.SecurityGroupRuleSet, rightServices *app.Services, rightProduction bool, err error) {
+	sets := make([]*service.SecurityGroupRuleSet, len(args))
+	for i, arg := range args {
+		ref, err := service.ParseSecurityGroupRef(arg)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		services, production, ref, err := groups(ref)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		if sets[i], err = service.FetchSecurityGroupRuleSet(ctx, services.ECS, ref); err != nil {
+			return nil, nil, nil, false, fmt.Errorf("fetching the rules of %s: %w", ref, err)
+		}
+		rightServices, rightProduction = services, production
+	}
+	return sets[0], sets[1], rightServices, rightProduction, nil
+}
+
+func runDnsDomains(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	domains, err := services.DNS.FetchDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    domains,
+		Headers: []string{"Domain Name", "Record Count", "Version Code"},
+	}
+	for _, domain := range domains {
+		result.Rows = append(result.Rows, []string{domain.DomainName, strconv.FormatInt(domain.RecordCount, 10), domain.VersionCode})
+	}
+	return result, nil
+}
+
+func runDnsRecords(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	records, err := services.DNS.FetchDomainRecords(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    records,
+		Headers: []string{"Record ID", "RR", "Type", "Value", "TTL", "Status"},
+	}
+	for _, record := range records {
+		result.Rows = append(result.Rows, []string{record.RecordId, record.RR, record.Type, record.Value, strconv.FormatInt(record.TTL, 10), record.Status})
+	}
+	return result, nil
+}
+
+func runDnsExport(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	records, err := services.DNS.FetchDomainRecords(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	zone := service.ZoneFromRecords(args[0], records)
+	result := &Result{
+		Data:    zone,
+		Headers: []string{"RR", "Type", "Value", "TTL", "Line", "Priority"},
+		Text:    service.FormatBIND(zone),
+	}
+	for _, spec := range zone.Records {
+		result.Rows = append(result.Rows, []string{spec.RR, spec.Type, spec.Value, strconv.FormatInt(spec.TTL, 10), spec.Line, formatPriority(spec.Priority)})
+	}
+	return result, nil
+}
+
+func runDnsImport(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	domainName, path := args[0], args[1]
+	zone, err := service.ReadZoneFile(path, domainName)
+	if err != nil {
+		return nil, err
+	}
+	records, err := services.DNS.FetchDomainRecords(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := service.DiffZone(domainName, records, zone.Records)
+	result := &Result{
+		Data:      diff,
+		Headers:   []string{"Action", "Record ID", "RR", "Type", "Value", "TTL", "Line"},
+		ConfirmId: domainName,
+	}
+	for _, change := range diff.Changes {
+		result.Rows = append(result.Rows, zoneChangeRow(change))
+	}
+	if len(diff.Changes) > 0 {
+		result.Confirm = fmt.Sprintf("Apply %d changes to %s (%d records unchanged)?", len(diff.Changes), domainName, diff.Unchanged)
+	}
+	return result, nil
+}
+
+func applyDnsImport(ctx context.Context, services *app.Services, plan *Result) error {
+	diff := plan.Data.(*service.ZoneDiff)
+	applied, err := service.ApplyZoneDiff(ctx, services.DNS, diff)
+	if err != nil {
+		return fmt.Errorf("applied %d of %d changes to %s: %w", applied, len(diff.Changes), diff.Domain, err)
+	}
+	fmt.Fprintf(os.Stderr, "Applied %d changes to %s\n", applied, diff.Domain)
+	return nil
+}
+
+// zoneChangeRow flattens a zone change
+func zoneChangeRow(change service.ZoneChange) []string {
+	spec := change.Record()
+	value, ttl := change.Describe()
+	return []string{change.Action, change.RecordId, spec.RR, spec.Type, value, ttl, spec.Line}
+}
+
+// formatPriority formats an MX priority, empty for other records
+func formatPriority(priority int64) string {
+	if priority == 0 {
+		return ""
+	}
+	return strconv.FormatInt(priority, 10)
+}
+
+func runSlbList(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	loadBalancers, err := services.SLB.FetchInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    loadBalancers,
+		Headers: []string{"SLB ID", "Name", "IP Address", "Type", "Status"},
+	}
+	for _, lb := range loadBalancers {
+		result.Rows = append(result.Rows, []string{lb.LoadBalancerId, lb.LoadBalancerName, lb.Address, lb.LoadBalancerSpec, lb.LoadBalancerStatus})
+	}
+	return result, nil
+}
+
+func runSlbListeners(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	listeners, err := services.SLB.FetchDetailedListeners(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    listeners,
+		Headers: []string{"Protocol", "Port", "Backend Port", "Status", "Health Check", "Scheduler", "VServer Group"},
+	}
+	for _, listener := range listeners {
+		backendPort := ""
+		if listener.BackendPort > 0 {
+			backendPort = strconv.Itoa(listener.BackendPort)
+		}
+		vServerGroup := listener.VServerGroupName
+		if vServerGroup == "" {
+			vServerGroup = listener.VServerGroupId
+		}
+		result.Rows = append(result.Rows, []string{
+			listener.Protocol,
+			strconv.Itoa(listener.Port),
+			backendPort,
+			listener.Status,
+			listener.HealthCheck,
+			listener.Scheduler,
+			vServerGroup,
+		})
+	}
+	return result, nil
+}
+
+func runSlbVServerGroups(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	groups, err := services.SLB.FetchDetailedVServerGroups(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    groups,
+		Headers: []string{"VServer Group ID", "VServer Group Name", "Backend Server Count", "Listeners"},
+	}
+	for _, group := range groups {
+		result.Rows = append(result.Rows, []string{
+			group.VServerGroupId,
+			group.VServerGroupName,
+			strconv.Itoa(group.BackendServerCount),
+			strings.Join(group.AssociatedListeners, ", "),
+		})
+	}
+	return result, nil
+}
+
+func runOssBuckets(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	buckets, err := services.OSS.FetchBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    buckets,
+		Headers: []string{"Bucket Name", "Location", "Creation Date", "Storage Class"},
+	}
+	for _, bucket := range buckets {
+		result.Rows = append(result.Rows, []string{bucket.Name, bucket.Location, bucket.CreationDate.Format("2006-01-02 15:04:05"), bucket.StorageClass})
+	}
+	return result, nil
+}
+
+func runOssLs(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	bucketName, prefix, _ := strings.Cut(strings.TrimPrefix(args[0], "oss://"), "/")
+	if bucketName == "" {
+		return nil, fmt.Errorf("missing bucket name in '%s'", args[0])
+	}
+
+	result := &Result{
+		Headers: []string{"Object Key", "Size (Bytes)", "Last Modified", "Storage Class", "ETag"},
+	}
+
+	// 分页获取前缀下的全部对象
+	var objects []interface{}
+	marker := ""
+	for {
+		page, err := services.OSS.FetchObjectsWithPrefix(ctx, bucketName, prefix, marker, ossListPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Objects {
+			objects = append(objects, object)
+			result.Rows = append(result.Rows, []string{
+				object.Key,
+				strconv.FormatInt(object.Size, 10),
+				object.LastModified.Format("2006-01-02 15:04:05"),
+				object.StorageClass,
+				object.ETag,
+			})
+		}
+		if !page.IsTruncated || page.NextMarker == "" {
+			break
+		}
+		marker = page.NextMarker
+	}
+	result.Data = objects
+	return result, nil
+}
+
+// ossUpload is the plan of an upload: the object it creates or, when Existing is set, replaces
+type ossUpload struct {
+	File     string                `json:"file"`
+	Size     int64                 `json:"size"`
+	Bucket   string                `json:"bucket"`
+	Key      string                `json:"key"`
+	Existing *oss.ObjectProperties `json:"existing,omitempty"`
+}
+
+func runOssUpload(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	filePath := args[0]
+	bucketName, objectKey, _ := strings.Cut(strings.TrimPrefix(args[1], "oss://"), "/")
+	if bucketName == "" {
+		return nil, fmt.Errorf("missing bucket name in '%s'", args[1])
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("cannot upload %s: it is a directory", filePath)
+	}
+	if objectKey == "" || strings.HasSuffix(objectKey, "/") {
+		objectKey += filepath.Base(filePath)
+	}
+
+	// Whether the upload replaces an object decides how it is confirmed
+	existing, err := services.OSS.StatObject(ctx, bucketName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	upload := &ossUpload{File: filePath, Size: info.Size(), Bucket: bucketName, Key: objectKey, Existing: existing}
+	result := &Result{
+		Data:      upload,
+		Headers:   []string{"Action", "Object", "Size (Bytes)", "Replaced Size (Bytes)", "Replaced Last Modified"},
+		ConfirmId: objectKey,
+	}
+	object := fmt.Sprintf("oss://%s/%s", bucketName, objectKey)
+	if existing == nil {
+		result.Rows = [][]string{{"upload", object, strconv.FormatInt(info.Size(), 10), "", ""}}
+		result.Confirm = fmt.Sprintf("Upload %s to %s?", filePath, object)
+	} else {
+		result.Rows = [][]string{{"replace", object, strconv.FormatInt(info.Size(), 10),
+			strconv.FormatInt(existing.Size, 10), existing.LastModified.Format("2006-01-02 15:04:05")}}
+		result.Confirm = fmt.Sprintf("Replace the existing object %s (%d bytes, modified %s) with %s?",
+			object, existing.Size, existing.LastModified.Format("2006-01-02 15:04:05"), filePath)
+	}
+	return result, nil
+}
+
+func applyOssUpload(ctx context.Context, services *app.Services, plan *Result) error {
+	upload := plan.Data.(*ossUpload)
+	if err := services.OSS.UploadObject(ctx, upload.Bucket, upload.Key, upload.File, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Uploaded %s to oss://%s/%s\n", upload.File, upload.Bucket, upload.Key)
+	return nil
+}
+
+func runRdsList(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	instances, err := services.RDS.FetchInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    instances,
+		Headers: []string{"Instance ID", "Engine", "Version", "Class", "Status", "Description"},
+	}
+	for _, inst := range instances {
+		result.Rows = append(result.Rows, []string{inst.DBInstanceId, inst.Engine, inst.EngineVersion, inst.DBInstanceClass, inst.DBInstanceStatus, inst.DBInstanceDescription})
+	}
+	return result, nil
+}
+
+func runRdsDatabases(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	databases, err := services.RDS.FetchDatabases(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    databases,
+		Headers: []string{"Database Name", "Status", "Character Set", "Bound Accounts", "Description"},
+	}
+	for _, db := range databases {
+		var accounts []string
+		for _, account := range db.Accounts.AccountPrivilegeInfo {
+			accounts = append(accounts, account.Account)
+		}
+		result.Rows = append(result.Rows, []string{db.DBName, db.DBStatus, db.CharacterSetName, strings.Join(accounts, ", "), db.DBDescription})
+	}
+	return result, nil
+}
+
+func runRdsAccounts(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	accounts, err := services.RDS.FetchAccounts(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    accounts,
+		Headers: []string{"Account Name", "Type", "Status", "Bound Databases", "Description"},
+	}
+	for _, account := range accounts {
+		var databases []string
+		for _, dbPriv := range account.DatabasePrivileges.DatabasePrivilege {
+			databases = append(databases, fmt.Sprintf("%s(%s)", dbPriv.DBName, dbPriv.AccountPrivilege))
+		}
+		result.Rows = append(result.Rows, []string{account.AccountName, account.AccountType, account.AccountStatus, strings.Join(databases, ", "), account.AccountDescription})
+	}
+	return result, nil
+}
+
+func runRedisList(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	instances, err := services.Redis.FetchInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    instances,
+		Headers: []string{"Instance ID", "Instance Name", "Type", "Version", "Status", "Region", "Capacity", "Connection Domain"},
+	}
+	for _, inst := range instances {
+		result.Rows = append(result.Rows, []string{
+			inst.InstanceId,
+			inst.InstanceName,
+			inst.InstanceType,
+			inst.EngineVersion,
+			inst.InstanceStatus,
+			inst.RegionId,
+			fmt.Sprintf("%d MB", inst.Capacity),
+			inst.ConnectionDomain,
+		})
+	}
+	return result, nil
+}
+
+func runRedisAccounts(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	accounts, err := services.Redis.FetchAccounts(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    accounts,
+		Headers: []string{"Account Name", "Status", "Type"},
+	}
+	for _, acc := range accounts {
+		result.Rows = append(result.Rows, []string{acc.AccountName, acc.AccountStatus, acc.AccountType})
+	}
+	return result, nil
+}
+
+func runRocketMQList(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	instances, err := services.RocketMQ.FetchInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    instances,
+		Headers: []string{"Instance ID", "Instance Name", "Type", "Status", "Create Time"},
+	}
+	for _, instance := range instances {
+		instanceType := "Unknown"
+		switch instance.InstanceType {
+		case 1:
+			instanceType = "Standard"
+		case 2:
+			instanceType = "Platinum"
+		}
+
+		status := "Unknown"
+		switch instance.InstanceStatus {
+		case 0:
+			status = "Deploying"
+		case 2:
+			status = "Arrears"
+		case 5:
+			status = "Running"
+		case 7:
+			status = "Upgrading"
+		}
+
+		result.Rows = append(result.Rows, []string{instance.InstanceId, instance.InstanceName, instanceType, status, formatMillis(instance.CreateTime)})
+	}
+	return result, nil
+}
+
+func runRocketMQTopics(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	topics, err := services.RocketMQ.FetchTopics(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    topics,
+		Headers: []string{"Topic", "Message Type", "Create Time", "Remark"},
+	}
+	for _, topic := range topics {
+		messageType := "Unknown"
+		switch topic.MessageType {
+		case 0:
+			messageType = "Normal"
+		case 1:
+			messageType = "Partition Ordered"
+		case 2:
+			messageType = "Global Ordered"
+		case 4:
+			messageType = "Transaction"
+		case 5:
+			messageType = "Scheduled/Delayed"
+		}
+		result.Rows = append(result.Rows, []string{topic.Topic, messageType, formatMillis(topic.CreateTime), topic.Remark})
+	}
+	return result, nil
+}
+
+func runRocketMQGroups(ctx context.Context, services *app.Services, args []string) (*Result, error) {
+	groups, err := services.RocketMQ.FetchGroups(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Data:    groups,
+		Headers: []string{"Group ID", "Group Type", "Create Time", "Update Time", "Remark"},
+	}
+	for _, group := range groups {
+		result.Rows = append(result.Rows, []string{group.GroupId, group.GroupType, formatMillis(group.CreateTime), formatMillis(group.UpdateTime), group.Remark})
+	}
+	return result, nil
+}
+
+// formatMillis formats a millisecond Unix timestamp, or returns "" for zero
+func formatMillis(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.Unix(ms/1000, 0).Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"aliyun-tui-viewer/internal/app"
+	"aliyun-tui-viewer/internal/service"
+	"aliyun-tui-viewer/internal/service/fake"
+)
+
+// newTestServices returns fake services seeded from the sample fixtures
+func newTestServices(t *testing.T) *app.Services {
+	t.Helper()
+	cloud, err := fake.LoadCloud("../service/fake/fixtures/sample.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &app.Services{
+		ECS:      fake.NewECSService(cloud),
+		DNS:      fake.NewDNSService(cloud),
+		SLB:      fake.NewSLBService(cloud),
+		RDS:      fake.NewRDSService(cloud),
+		OSS:      fake.NewOSSService(cloud),
+		Redis:    fake.NewRedisService(cloud),
+		RocketMQ: fake.NewRocketMQService(cloud),
+	}
+}
+
+func TestOssUpload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "2024-01-02.log")
+	if err := os.WriteFile(file, []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		row     []string
+		confirm string
+	}{
+		{
+			name:    "new key",
+			target:  "demo-logs/app/new.log",
+			row:     []string{"upload", "oss://demo-logs/app/new.log", "6", "", ""},
+			confirm: "Upload " + file + " to oss://demo-logs/app/new.log?",
+		},
+		{
+			name:    "existing key under a prefix",
+			target:  "oss://demo-logs/app/",
+			row:     []string{"replace", "oss://demo-logs/app/2024-01-02.log", "6", "2048", "2024-01-03 00:00:00"},
+			confirm: "Replace the existing object oss://demo-logs/app/2024-01-02.log (2048 bytes, modified 2024-01-03 00:00:00) with " + file + "?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices(t)
+			result, err := runOssUpload(context.Background(), services, []string{file, tt.target})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Rows, [][]string{tt.row}) {
+				t.Errorf("rows %q, want %q", result.Rows, [][]string{tt.row})
+			}
+			if result.Confirm != tt.confirm {
+				t.Errorf("confirm %q, want %q", result.Confirm, tt.confirm)
+			}
+
+			if err := applyOssUpload(context.Background(), services, result); err != nil {
+				t.Fatal(err)
+			}
+			upload := result.Data.(*ossUpload)
+			object, err := services.OSS.StatObject(context.Background(), upload.Bucket, upload.Key)
+			if err != nil || object == nil || object.Size != 6 {
+				t.Errorf("uploaded object %+v, %v", object, err)
+			}
+		})
+	}
+}
+
+func TestEcsCloneAcrossProfiles(t *testing.T) {
+	prod, staging := newTestServices(t), newTestServices(t)
+	var resolved []string
+	groups := func(ref service.SecurityGroupRef) (*app.Services, bool, service.SecurityGroupRef, error) {
+		resolved = append(resolved, ref.String())
+		if ref.Profile == "staging" {
+			return staging, false, ref.Resolve("staging", "cn-beijing"), nil
+		}
+		return prod, true, ref.Resolve("prod", "cn-hangzhou"), nil
+	}
+
+	result, err := runEcsClone(context.Background(), groups, []string{"sg-bp1demoweb", "staging:sg-bp1demostaging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"sg-bp1demoweb", "staging:sg-bp1demostaging"}; !reflect.DeepEqual(resolved, want) {
+		t.Errorf("resolved %q, want %q", resolved, want)
+	}
+	if result.Services != staging || result.Production || result.ConfirmId != "sg-bp1demostaging" {
+		t.Errorf("applied with staging services %v, production %v and confirmed by %q; want the target's",
+			result.Services == staging, result.Production, result.ConfirmId)
+	}
+	plan := result.Data.(*service.SecurityGroupClonePlan)
+	if plan.Target.String() != "staging:cn-beijing/sg-bp1demostaging" {
+		t.Errorf("target %s, want staging:cn-beijing/sg-bp1demostaging", plan.Target)
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"aliyun-tui-viewer/internal/app"
+)
+
+// Output formats supported by --output
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+)
+
+// Result is the outcome of a command. Data is the raw service result used for the
+// json and yaml formats; Headers and Rows are the flattened view used for table and csv.
+// Text, when set, is printed as it is instead of the table, e.g. a zone file.
+type Result struct {
+	Data    interface{}
+	Headers []string
+	Rows    [][]string
+	Text    string
+
+	// Confirm is the question asked before the command's Apply runs, e.g. "Apply 3
+	// changes to example.com?". Empty when there is nothing to apply.
+	Confirm string
+
+	// ConfirmId is the resource ID that has to be typed, or passed with --confirm, before
+	// Apply changes a production profile, e.g. the domain of a zone import
+	ConfirmId string
+
+	// Services are the services Apply changes when they are not those of the command
+	// line, e.g. those of a security group in another profile; nil for the command line's.
+	// Production tells whether their profile is a production profile.
+	Services   *app.Services
+	Production bool
+}
+
+// validateOutput checks that the output format is supported
+func validateOutput(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML, OutputCSV:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format '%s' (expected table, json, yaml or csv)", format)
+}
+
+// writeResult writes the result to w in the given format
+func writeResult(w io.Writer, format string, result *Result) error {
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, result.Data)
+	case OutputYAML:
+		return writeYAML(w, result.Data)
+	case OutputCSV:
+		return writeCSV(w, result.Headers, result.Rows)
+	default:
+		if result.Text != "" {
+			_, err := io.WriteString(w, result.Text)
+			return err
+		}
+		return writeTable(w, result.Headers, result.Rows)
+	}
+}
+
+// writeJSON writes data as indented JSON
+func writeJSON(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// writeYAML writes data as YAML. The data goes through JSON first so the keys match
+// the SDK's field names, the same as in the json output and the TUI detail views.
+func writeYAML(w io.Writer, data interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshaling result: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(jsonData, &generic); err != nil {
+		return fmt.Errorf("unmarshaling result: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		return fmt.Errorf("encoding YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+// writeCSV writes the header row followed by the data rows
+func writeCSV(w io.Writer, headers []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// writeTable writes an aligned plain text table
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	upper := make([]string, len(headers))
+	for i, header := range headers {
+		upper[i] = strings.ToUpper(header)
+	}
+	fmt.Fprintln(tw, strings.Join(upper, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// Keep each row on one line
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
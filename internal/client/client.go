@@ -6,12 +6,17 @@ import (
 	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
 	ons20190214 "github.com/alibabacloud-go/ons-20190214/v3/client"
 	"github.com/alibabacloud-go/tea/tea"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	r_kvstore "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/aliyun/credentials-go/credentials"
+	"github.com/aliyun/credentials-go/credentials/providers"
+
+	"aliyun-tui-viewer/internal/config"
 )
 
 // AliyunClients holds all Aliyun service clients
@@ -24,57 +29,82 @@ type AliyunClients struct {
 	Redis    *r_kvstore.Client
 	RocketMQ *ons20190214.Client
 	config   *Config
+
+	// credentials is shared by all clients so temporary credentials are refreshed only once
+	credentials providers.CredentialsProvider
 }
 
 // Config represents the client configuration
 type Config struct {
+	// ProfileName, Mode and ConfigPath identify the aliyun CLI profile used for non-AK modes
+	ProfileName     string
+	Mode            string
+	ConfigPath      string
 	AccessKeyID     string
 	AccessKeySecret string
 	RegionID        string
 	OssEndpoint     string
 }
 
+// NewConfig creates the client configuration for the loaded application configuration
+func NewConfig(cfg *config.Config) *Config {
+	return &Config{
+		ProfileName:     cfg.Profile,
+		Mode:            cfg.Mode,
+		ConfigPath:      cfg.ConfigPath,
+		AccessKeyID:     cfg.AccessKeyID,
+		AccessKeySecret: cfg.AccessKeySecret,
+		RegionID:        cfg.RegionID,
+		OssEndpoint:     cfg.OssEndpoint,
+	}
+}
+
 // NewAliyunClients creates and initializes all Aliyun service clients
 func NewAliyunClients(cfg *Config) (*AliyunClients, error) {
-	clients := &AliyunClients{config: cfg}
+	credentialsProvider, err := newCredentialsProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clients := &AliyunClients{config: cfg, credentials: credentialsProvider}
+	sdkCredentials := &sdkCredentialsProvider{provider: credentialsProvider}
 
 	// Initialize ECS client
-	ecsClient, err := ecs.NewClientWithAccessKey(cfg.RegionID, cfg.AccessKeyID, cfg.AccessKeySecret)
+	ecsClient, err := ecs.NewClientWithOptions(cfg.RegionID, sdk.NewConfig(), sdkCredentials)
 	if err != nil {
 		return nil, fmt.Errorf("creating ECS client: %w", err)
 	}
 	clients.ECS = ecsClient
 
 	// Initialize DNS client
-	dnsClient, err := alidns.NewClientWithAccessKey(cfg.RegionID, cfg.AccessKeyID, cfg.AccessKeySecret)
+	dnsClient, err := alidns.NewClientWithOptions(cfg.RegionID, sdk.NewConfig(), sdkCredentials)
 	if err != nil {
 		return nil, fmt.Errorf("creating DNS client: %w", err)
 	}
 	clients.DNS = dnsClient
 
 	// Initialize SLB client
-	slbClient, err := slb.NewClientWithAccessKey(cfg.RegionID, cfg.AccessKeyID, cfg.AccessKeySecret)
+	slbClient, err := slb.NewClientWithOptions(cfg.RegionID, sdk.NewConfig(), sdkCredentials)
 	if err != nil {
 		return nil, fmt.Errorf("creating SLB client: %w", err)
 	}
 	clients.SLB = slbClient
 
 	// Initialize RDS client
-	rdsClient, err := rds.NewClientWithAccessKey(cfg.RegionID, cfg.AccessKeyID, cfg.AccessKeySecret)
+	rdsClient, err := rds.NewClientWithOptions(cfg.RegionID, sdk.NewConfig(), sdkCredentials)
 	if err != nil {
 		return nil, fmt.Errorf("creating RDS client: %w", err)
 	}
 	clients.RDS = rdsClient
 
 	// Initialize OSS client
-	ossClient, err := oss.New(cfg.OssEndpoint, cfg.AccessKeyID, cfg.AccessKeySecret)
+	ossClient, err := oss.New(cfg.OssEndpoint, "", "", oss.SetCredentialsProvider(clients.OSSCredentials()))
 	if err != nil {
 		return nil, fmt.Errorf("creating OSS client: %w", err)
 	}
 	clients.OSS = ossClient
 
 	// Initialize Redis client
-	redisClient, err := r_kvstore.NewClientWithAccessKey(cfg.RegionID, cfg.AccessKeyID, cfg.AccessKeySecret)
+	redisClient, err := r_kvstore.NewClientWithOptions(cfg.RegionID, sdk.NewConfig(), sdkCredentials)
 	if err != nil {
 		return nil, fmt.Errorf("creating Redis client: %w", err)
 	}
@@ -82,10 +112,9 @@ func NewAliyunClients(cfg *Config) (*AliyunClients, error) {
 
 	// Initialize RocketMQ client using V2.0 SDK
 	rocketmqConfig := &openapi.Config{
-		AccessKeyId:     tea.String(cfg.AccessKeyID),
-		AccessKeySecret: tea.String(cfg.AccessKeySecret),
-		RegionId:        tea.String(cfg.RegionID),
-		Endpoint:        tea.String(fmt.Sprintf("ons.%s.aliyuncs.com", cfg.RegionID)),
+		Credential: credentials.FromCredentialsProvider(credentialsProvider.GetProviderName(), credentialsProvider),
+		RegionId:   tea.String(cfg.RegionID),
+		Endpoint:   tea.String(fmt.Sprintf("ons.%s.aliyuncs.com", cfg.RegionID)),
 	}
 	rocketmqClient, err := ons20190214.NewClient(rocketmqConfig)
 	if err != nil {
@@ -100,3 +129,9 @@ func NewAliyunClients(cfg *Config) (*AliyunClients, error) {
 func (c *AliyunClients) GetConfig() *Config {
 	return c.config
 }
+
+// OSSCredentials returns the shared credentials in the form expected by the OSS SDK,
+// for creating additional OSS clients such as the ones for buckets in other regions
+func (c *AliyunClients) OSSCredentials() oss.CredentialsProvider {
+	return &ossCredentialsProvider{provider: c.credentials}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	sdkcredentials "github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/aliyun/credentials-go/credentials/providers"
+
+	"aliyun-tui-viewer/internal/config"
+)
+
+// newCredentialsProvider builds the credential provider for the configured profile mode.
+// Static AccessKey profiles are served directly; every other mode is resolved through the
+// aliyun CLI profile provider, which assumes roles, runs credential processes and exchanges
+// CloudSSO tokens, refreshing the temporary credentials before they expire.
+func newCredentialsProvider(cfg *Config) (providers.CredentialsProvider, error) {
+	var (
+		inner providers.CredentialsProvider
+		err   error
+	)
+
+	switch cfg.Mode {
+	case "", config.ModeAK:
+		inner, err = providers.NewStaticAKCredentialsProviderBuilder().
+			WithAccessKeyId(cfg.AccessKeyID).
+			WithAccessKeySecret(cfg.AccessKeySecret).
+			Build()
+	default:
+		inner, err = providers.NewCLIProfileCredentialsProviderBuilder().
+			WithProfileFile(cfg.ConfigPath).
+			WithProfileName(cfg.ProfileName).
+			Build()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s credentials provider for profile '%s': %w", cfg.Mode, cfg.ProfileName, err)
+	}
+
+	return &sharedCredentialsProvider{inner: inner}, nil
+}
+
+// sharedCredentialsProvider serializes access to a provider that is shared by all SDK clients.
+// The refreshing providers keep their cached credentials in unsynchronized fields.
+type sharedCredentialsProvider struct {
+	mu    sync.Mutex
+	inner providers.CredentialsProvider
+}
+
+// GetCredentials returns the current credentials, refreshing them if needed
+func (p *sharedCredentialsProvider) GetCredentials() (*providers.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inner.GetCredentials()
+}
+
+// GetProviderName returns the name of the wrapped provider
+func (p *sharedCredentialsProvider) GetProviderName() string {
+	return p.inner.GetProviderName()
+}
+
+// sdkCredentialsProvider adapts a provider to the alibaba-cloud-sdk-go credentials interface
+type sdkCredentialsProvider struct {
+	provider providers.CredentialsProvider
+}
+
+// GetCredentials returns the current credentials in the SDK representation
+func (p *sdkCredentialsProvider) GetCredentials() (*sdkcredentials.Credentials, error) {
+	cc, err := p.provider.GetCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return &sdkcredentials.Credentials{
+		AccessKeyId:     cc.AccessKeyId,
+		AccessKeySecret: cc.AccessKeySecret,
+		SecurityToken:   cc.SecurityToken,
+		ProviderName:    cc.ProviderName,
+	}, nil
+}
+
+// GetProviderName returns the name of the wrapped provider
+func (p *sdkCredentialsProvider) GetProviderName() string {
+	return p.provider.GetProviderName()
+}
+
+// ossCredentialsProvider adapts a provider to the OSS SDK credentials interface
+type ossCredentialsProvider struct {
+	provider providers.CredentialsProvider
+}
+
+// ossCredentials implements oss.Credentials
+type ossCredentials struct {
+	accessKeyID     string
+	accessKeySecret string
+	securityToken   string
+}
+
+func (c *ossCredentials) GetAccessKeyID() string     { return c.accessKeyID }
+func (c *ossCredentials) GetAccessKeySecret() string { return c.accessKeySecret }
+func (c *ossCredentials) GetSecurityToken() string   { return c.securityToken }
+
+// GetCredentials returns the current credentials, or empty credentials if they cannot be
+// obtained. The OSS SDK and OSSService call GetCredentialsE instead, which returns the error.
+func (p *ossCredentialsProvider) GetCredentials() oss.Credentials {
+	creds, err := p.GetCredentialsE()
+	if err != nil {
+		return &ossCredentials{}
+	}
+	return creds
+}
+
+// GetCredentialsE returns the current credentials or the error that prevented obtaining them
+func (p *ossCredentialsProvider) GetCredentialsE() (oss.Credentials, error) {
+	cc, err := p.provider.GetCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return &ossCredentials{
+		accessKeyID:     cc.AccessKeyId,
+		accessKeySecret: cc.AccessKeySecret,
+		securityToken:   cc.SecurityToken,
+	}, nil
+}
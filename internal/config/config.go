@@ -1,11 +1,26 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Credential modes supported in profiles, matching the aliyun CLI
+const (
+	ModeAK                  = "AK"
+	ModeStsToken            = "StsToken"
+	ModeRamRoleArn          = "RamRoleArn"
+	ModeEcsRamRole          = "EcsRamRole"
+	ModeChainableRamRoleArn = "ChainableRamRoleArn"
+	ModeExternal            = "External"
+	ModeCloudSSO            = "CloudSSO"
 )
 
 // ConfigProfile represents a single profile in the Aliyun CLI config
@@ -16,6 +31,26 @@ type ConfigProfile struct {
 	AccessKeySecret string `json:"access_key_secret"`
 	RegionID        string `json:"region_id"`
 	OssEndpoint     string `json:"oss_endpoint,omitempty"` // Custom field for OSS endpoint
+	// Which OSS endpoints buckets are accessed through, see OSSEndpoint
+	OssEndpointType string `json:"oss_endpoint_type,omitempty"`
+
+	// Fields used by the non-AK credential modes
+	StsToken             string `json:"sts_token,omitempty"`
+	RamRoleName          string `json:"ram_role_name,omitempty"`
+	RamRoleArn           string `json:"ram_role_arn,omitempty"`
+	RoleSessionName      string `json:"ram_session_name,omitempty"`
+	ExternalID           string `json:"external_id,omitempty"`
+	SourceProfile        string `json:"source_profile,omitempty"`
+	ProcessCommand       string `json:"process_command,omitempty"`
+	CloudSSOSignInURL    string `json:"cloud_sso_sign_in_url,omitempty"`
+	CloudSSOAccessConfig string `json:"cloud_sso_access_config,omitempty"`
+	CloudSSOAccountID    string `json:"cloud_sso_account_id,omitempty"`
+	AccessToken          string `json:"access_token,omitempty"`
+
+	// Write protection, see readOnlyReason
+	ReadOnly    bool `json:"read_only,omitempty"`    // Disable every write operation
+	Production  bool `json:"production,omitempty"`   // Read-only unless allow_writes is set
+	AllowWrites bool `json:"allow_writes,omitempty"` // Opt a production profile in to writes
 	// Other fields like output_format, language can be added if needed
 }
 
@@ -25,20 +60,50 @@ type AliyunConfig struct {
 	Profiles []ConfigProfile `json:"profiles"`
 	Editor   string          `json:"editor,omitempty"` // Global editor command
 	Pager    string          `json:"pager,omitempty"`  // Global pager command
+	// Auto-refresh interval of the TUI list pages, e.g. "30s"
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	// Age after which cached resource lists are refreshed in the background, e.g. "10m"
+	CacheTTL string `json:"cache_ttl,omitempty"`
 }
 
 // Config holds the application configuration
 type Config struct {
+	Profile         string
+	Mode            string
+	ConfigPath      string
 	AccessKeyID     string
 	AccessKeySecret string
 	RegionID        string
 	OssEndpoint     string
+	OssEndpointType string // OSSEndpointPublic, OSSEndpointInternal or OSSEndpointAccelerate
 	Editor          string
 	Pager           string
+	RefreshInterval time.Duration // Zero disables auto-refresh
+	CacheTTL        time.Duration
+	ReadOnly        bool   // Write operations are refused
+	ReadOnlyReason  string // Why writes are refused, shown with the refusal
+	Production      bool   // Destructive operations must be confirmed by typing the resource ID
+}
+
+// SetReadOnly refuses write operations regardless of the profile settings. It implements
+// the --read-only flag.
+func (c *Config) SetReadOnly() {
+	c.ReadOnly = true
+	c.ReadOnlyReason = "tali was started with --read-only"
 }
 
+// DefaultCacheTTL is the cache TTL used when cache_ttl is not set
+const DefaultCacheTTL = 5 * time.Minute
+
 // LoadAliyunConfig loads configuration from ~/.aliyun/config.json
 func LoadAliyunConfig() (*Config, error) {
+	return LoadProfileConfig("", "")
+}
+
+// LoadProfileConfig loads configuration from ~/.aliyun/config.json for the given profile
+// and region. An empty profileName selects the current profile and an empty regionID
+// keeps the profile's region_id.
+func LoadProfileConfig(profileName, regionID string) (*Config, error) {
 	usr, err := user.Current()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
@@ -60,7 +125,10 @@ func LoadAliyunConfig() (*Config, error) {
 		return nil, fmt.Errorf("no profiles found in aliyun config file: %s", configPath)
 	}
 
-	activeProfileName := config.Current
+	activeProfileName := profileName
+	if activeProfileName == "" {
+		activeProfileName = config.Current
+	}
 	if activeProfileName == "" {
 		if len(config.Profiles) == 1 {
 			activeProfileName = config.Profiles[0].Name
@@ -89,14 +157,39 @@ func LoadAliyunConfig() (*Config, error) {
 		return nil, fmt.Errorf("current profile '%s' not found in aliyun config file: %s", activeProfileName, configPath)
 	}
 
-	if activeProfile.AccessKeyID == "" || activeProfile.AccessKeySecret == "" || activeProfile.RegionID == "" {
-		return nil, fmt.Errorf("profile '%s' in %s is missing access_key_id, access_key_secret, or region_id", activeProfile.Name, configPath)
+	if regionID == "" {
+		regionID = activeProfile.RegionID
+	}
+	if regionID == "" {
+		return nil, fmt.Errorf("profile '%s' in %s is missing region_id", activeProfile.Name, configPath)
+	}
+
+	mode := activeProfile.Mode
+	if mode == "" {
+		mode = ModeAK
+	}
+	if err := validateCredentialFields(activeProfile, mode); err != nil {
+		return nil, fmt.Errorf("profile '%s' in %s: %w", activeProfile.Name, configPath, err)
 	}
 
 	// Resolve OSS Endpoint
+	ossEndpointType := activeProfile.OssEndpointType
+	switch ossEndpointType {
+	case "":
+		ossEndpointType = OSSEndpointPublic
+	case OSSEndpointPublic, OSSEndpointInternal, OSSEndpointAccelerate:
+	default:
+		return nil, fmt.Errorf("profile '%s' in %s: invalid oss_endpoint_type %q: expected %q, %q or %q",
+			activeProfile.Name, configPath, ossEndpointType, OSSEndpointPublic, OSSEndpointInternal, OSSEndpointAccelerate)
+	}
 	ossEndpoint := activeProfile.OssEndpoint
-	if ossEndpoint == "" && activeProfile.RegionID != "" {
-		ossEndpoint = fmt.Sprintf("oss-%s.aliyuncs.com", activeProfile.RegionID)
+	if ossEndpoint == "" && regionID != "" {
+		// Buckets are listed through a regional endpoint, even when they are accelerated
+		if ossEndpointType == OSSEndpointInternal {
+			ossEndpoint = OSSEndpoint(regionID, OSSEndpointInternal)
+		} else {
+			ossEndpoint = OSSEndpoint(regionID, OSSEndpointPublic)
+		}
 	}
 
 	if ossEndpoint == "" {
@@ -106,16 +199,128 @@ func LoadAliyunConfig() (*Config, error) {
 			activeProfile.Name, configPath)
 	}
 
+	var refreshInterval time.Duration
+	if config.RefreshInterval != "" {
+		refreshInterval, err = time.ParseDuration(config.RefreshInterval)
+		if err != nil || refreshInterval < 0 {
+			return nil, fmt.Errorf("invalid refresh_interval %q in %s: expected a duration such as \"30s\"", config.RefreshInterval, configPath)
+		}
+	}
+
+	cacheTTL := DefaultCacheTTL
+	if config.CacheTTL != "" {
+		cacheTTL, err = time.ParseDuration(config.CacheTTL)
+		if err != nil || cacheTTL < 0 {
+			return nil, fmt.Errorf("invalid cache_ttl %q in %s: expected a duration such as \"10m\"", config.CacheTTL, configPath)
+		}
+	}
+
+	writeRefusal := readOnlyReason(activeProfile)
+
 	return &Config{
+		Profile:         activeProfile.Name,
+		Mode:            mode,
+		ConfigPath:      configPath,
 		AccessKeyID:     activeProfile.AccessKeyID,
 		AccessKeySecret: activeProfile.AccessKeySecret,
-		RegionID:        activeProfile.RegionID,
+		RegionID:        regionID,
 		OssEndpoint:     ossEndpoint,
+		OssEndpointType: ossEndpointType,
 		Editor:          config.Editor,
 		Pager:           config.Pager,
+		RefreshInterval: refreshInterval,
+		CacheTTL:        cacheTTL,
+		ReadOnly:        writeRefusal != "",
+		ReadOnlyReason:  writeRefusal,
+		Production:      activeProfile.Production,
 	}, nil
 }
 
+// OSS endpoint types of the oss_endpoint_type profile option
+const (
+	OSSEndpointPublic     = "public"     // The public endpoint of the bucket's region
+	OSSEndpointInternal   = "internal"   // The internal endpoint, reachable from ECS and VPCs in the region
+	OSSEndpointAccelerate = "accelerate" // The global transfer acceleration endpoint
+)
+
+// OSSEndpoint returns the endpoint of the given type for the buckets of a region, e.g.
+// "oss-cn-hangzhou-internal.aliyuncs.com"
+func OSSEndpoint(regionID, endpointType string) string {
+	switch endpointType {
+	case OSSEndpointInternal:
+		return fmt.Sprintf("oss-%s-internal.aliyuncs.com", regionID)
+	case OSSEndpointAccelerate:
+		return "oss-accelerate.aliyuncs.com"
+	}
+	return fmt.Sprintf("oss-%s.aliyuncs.com", regionID)
+}
+
+// OSSBucketEndpoint returns the endpoint for the buckets of a region. Buckets in the
+// profile's own region use oss_endpoint, unless they are accelerated.
+func (c *Config) OSSBucketEndpoint(regionID string) string {
+	if regionID == c.RegionID && c.OssEndpointType != OSSEndpointAccelerate {
+		return c.OssEndpoint
+	}
+	return OSSEndpoint(regionID, c.OssEndpointType)
+}
+
+// readOnlyReason returns why write operations are refused for a profile, or an empty
+// string if they are allowed. Production profiles are read-only until allow_writes opts
+// them in.
+func readOnlyReason(profile *ConfigProfile) string {
+	switch {
+	case profile.ReadOnly:
+		return fmt.Sprintf("profile '%s' is read-only", profile.Name)
+	case profile.Production && !profile.AllowWrites:
+		return fmt.Sprintf("profile '%s' is a production profile; set allow_writes to enable writes", profile.Name)
+	}
+	return ""
+}
+
+// validateCredentialFields checks that the profile sets every field its credential mode requires
+func validateCredentialFields(profile *ConfigProfile, mode string) error {
+	type field struct {
+		name  string
+		value string
+	}
+
+	var required []field
+	switch mode {
+	case ModeAK:
+		required = []field{{"access_key_id", profile.AccessKeyID}, {"access_key_secret", profile.AccessKeySecret}}
+	case ModeStsToken:
+		required = []field{{"access_key_id", profile.AccessKeyID}, {"access_key_secret", profile.AccessKeySecret}, {"sts_token", profile.StsToken}}
+	case ModeRamRoleArn:
+		required = []field{{"access_key_id", profile.AccessKeyID}, {"access_key_secret", profile.AccessKeySecret}, {"ram_role_arn", profile.RamRoleArn}}
+	case ModeEcsRamRole:
+		// The role name is optional, it is discovered from the metadata service when empty
+	case ModeChainableRamRoleArn:
+		required = []field{{"source_profile", profile.SourceProfile}, {"ram_role_arn", profile.RamRoleArn}}
+	case ModeExternal:
+		required = []field{{"process_command", profile.ProcessCommand}}
+	case ModeCloudSSO:
+		required = []field{
+			{"cloud_sso_sign_in_url", profile.CloudSSOSignInURL},
+			{"access_token", profile.AccessToken},
+			{"cloud_sso_access_config", profile.CloudSSOAccessConfig},
+			{"cloud_sso_account_id", profile.CloudSSOAccountID},
+		}
+	default:
+		return fmt.Errorf("unsupported credential mode '%s'", mode)
+	}
+
+	var missing []string
+	for _, f := range required {
+		if f.value == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("mode %s requires %s", mode, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // GetCurrentProfileName returns the name of the current active profile
 func GetCurrentProfileName() (string, error) {
 	usr, err := user.Current()
@@ -216,13 +421,10 @@ func SwitchProfile(profileName string) error {
 		return fmt.Errorf("profile '%s' not found", profileName)
 	}
 
-	// Update current profile
-	config.Current = profileName
-
-	// Write back to file
-	updatedData, err := json.MarshalIndent(config, "", "  ")
+	// Update only the current profile, keeping the rest of the file byte for byte
+	updatedData, err := setCurrentProfile(data, profileName)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return fmt.Errorf("failed to update aliyun config file %s: %w", configPath, err)
 	}
 
 	err = os.WriteFile(configPath, updatedData, 0644)
@@ -233,6 +435,45 @@ func SwitchProfile(profileName string) error {
 	return nil
 }
 
+// setCurrentProfile returns the config file data with its top-level "current" field set to
+// profileName. Only the value is replaced, so the order of the fields, the indentation and
+// the fields this tool does not know about are kept; a missing field is added first.
+func setCurrentProfile(data []byte, profileName string) ([]byte, error) {
+	value, err := json.Marshal(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("the config is not a JSON object")
+	}
+	objectStart := dec.InputOffset()
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if key == "current" {
+			end := dec.InputOffset()
+			start := end - int64(len(raw))
+			return slices.Concat(data[:start], value, data[end:]), nil
+		}
+	}
+
+	field := fmt.Sprintf("\n  \"current\": %s", value)
+	if len(bytes.TrimSpace(data[objectStart:])) > 1 { // More than the closing brace
+		field += ","
+	} else {
+		field += "\n"
+	}
+	return slices.Concat(data[:objectStart], []byte(field), data[objectStart:]), nil
+}
+
 // GetEditor returns the editor command to use, following the priority:
 // 1. Config file "editor" field
 // 2. VISUAL environment variable
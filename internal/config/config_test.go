@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetCurrentProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "fields kept in order",
+			data: "{\n  \"profiles\": [{\"name\": \"dev\"}, {\"name\": \"prod\"}],\n  \"current\": \"dev\",\n  \"meta_path\": \"\",\n  \"editor\": \"nano\"\n}\n",
+			want: "{\n  \"profiles\": [{\"name\": \"dev\"}, {\"name\": \"prod\"}],\n  \"current\": \"prod\",\n  \"meta_path\": \"\",\n  \"editor\": \"nano\"\n}\n",
+		},
+		{
+			name: "nested current untouched",
+			data: `{"profiles":[{"name":"prod","current":"dev"}],"current":"dev"}`,
+			want: `{"profiles":[{"name":"prod","current":"dev"}],"current":"prod"}`,
+		},
+		{
+			name: "missing current",
+			data: "{\n  \"profiles\": []\n}",
+			want: "{\n  \"current\": \"prod\",\n  \"profiles\": []\n}",
+		},
+		{
+			name: "empty object",
+			data: "{}",
+			want: "{\n  \"current\": \"prod\"\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setCurrentProfile([]byte(tt.data), "prod")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := setCurrentProfile([]byte(`["dev"]`), "prod"); err == nil {
+		t.Error("no error for a config that is not an object")
+	}
+}
+
+func TestValidateCredentialFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		profile ConfigProfile
+		err     string // Empty when the profile is valid
+	}{
+		{name: "AK", mode: ModeAK, profile: ConfigProfile{AccessKeyID: "id", AccessKeySecret: "secret"}},
+		{name: "AK without secret", mode: ModeAK, profile: ConfigProfile{AccessKeyID: "id"}, err: "mode AK requires access_key_secret"},
+		{name: "StsToken without token", mode: ModeStsToken, profile: ConfigProfile{AccessKeyID: "id", AccessKeySecret: "secret"}, err: "mode StsToken requires sts_token"},
+		{name: "RamRoleArn", mode: ModeRamRoleArn, profile: ConfigProfile{AccessKeyID: "id", AccessKeySecret: "secret", RamRoleArn: "acs:ram::1:role/admin"}},
+		{name: "EcsRamRole without role name", mode: ModeEcsRamRole},
+		{name: "ChainableRamRoleArn empty", mode: ModeChainableRamRoleArn, err: "mode ChainableRamRoleArn requires source_profile, ram_role_arn"},
+		{name: "External", mode: ModeExternal, profile: ConfigProfile{ProcessCommand: "vault-aliyun"}},
+		{name: "CloudSSO without token", mode: ModeCloudSSO, profile: ConfigProfile{CloudSSOSignInURL: "https://signin", CloudSSOAccessConfig: "ac-1", CloudSSOAccountID: "1"}, err: "mode CloudSSO requires access_token"},
+		{name: "unknown mode", mode: "OIDC", err: "unsupported credential mode 'OIDC'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCredentialFields(&tt.profile, tt.mode)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("got %v, want an error with %q", err, tt.err)
+			}
+		})
+	}
+}
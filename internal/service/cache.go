@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores snapshots of resource lists on disk so they can be shown immediately on
+// the next launch. Snapshots live in ~/.cache/tali/<profile>/<region>/<name>.json
+// ($XDG_CACHE_HOME replaces ~/.cache when set), so one account never sees another's data.
+type Cache struct {
+	dir string
+}
+
+// snapshot is the file format of a cached resource list
+type snapshot struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewCache returns the cache of a profile and region
+func NewCache(profile, region string) (*Cache, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("finding home directory: %w", err)
+		}
+		base = filepath.Join(home, ".cache")
+	}
+	// Escape the names so a profile called "../x" cannot leave the cache directory
+	return &Cache{dir: filepath.Join(base, "tali", url.PathEscape(profile), url.PathEscape(region))}, nil
+}
+
+// Load decodes the snapshot called name into v and returns the time it was saved. The
+// error wraps fs.ErrNotExist when there is no snapshot yet.
+func (c *Cache) Load(name string, v any) (time.Time, error) {
+	data, err := os.ReadFile(c.path(name))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading cache %s: %w", name, err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return time.Time{}, fmt.Errorf("parsing cache %s: %w", name, err)
+	}
+	if err := json.Unmarshal(snap.Data, v); err != nil {
+		return time.Time{}, fmt.Errorf("parsing cache %s: %w", name, err)
+	}
+	return snap.SavedAt, nil
+}
+
+// Save stores v as the snapshot called name. The file is replaced atomically, so a
+// concurrent Load never sees a partial snapshot.
+func (c *Cache) Save(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache %s: %w", name, err)
+	}
+	data, err = json.Marshal(snapshot{SavedAt: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding cache %s: %w", name, err)
+	}
+
+	// Resource lists include IPs and other account details, so keep them private
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("creating cache directory %s: %w", c.dir, err)
+	}
+	tmp, err := os.CreateTemp(c.dir, url.PathEscape(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing cache %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(name)); err != nil {
+		return fmt.Errorf("writing cache %s: %w", name, err)
+	}
+	return nil
+}
+
+// path returns the file of the snapshot called name
+func (c *Cache) path(name string) string {
+	return filepath.Join(c.dir, url.PathEscape(name)+".json")
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", base)
+
+	// Profile, region and snapshot names stay single path elements under the cache
+	cache, err := NewCache("../prod", "cn/hangzhou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded []string
+	if _, err := cache.Load("ecs/instances", &loaded); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("loading before saving: got %v, want fs.ErrNotExist", err)
+	}
+
+	before := time.Now()
+	if err := cache.Save("ecs/instances", []string{"i-web", "i-db"}); err != nil {
+		t.Fatal(err)
+	}
+	savedAt, err := cache.Load("ecs/instances", &loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(loaded, ",") != "i-web,i-db" || savedAt.Before(before) || savedAt.After(time.Now()) {
+		t.Errorf("loaded %q saved at %s", loaded, savedAt)
+	}
+
+	var files []string
+	err = filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			rel, _ := filepath.Rel(base, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"tali/..%2Fprod/cn%2Fhangzhou/ecs%2Finstances.json"}; strings.Join(files, " ") != strings.Join(want, " ") {
+		t.Errorf("files %q, want %q and no temporary file left", files, want)
+	}
+	info, err := os.Stat(filepath.Join(base, files[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("snapshot mode %s, want -rw-------", info.Mode().Perm())
+	}
+}
+
+func TestCacheConcurrentSaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cache, err := NewCache("default", "cn-hangzhou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := strings.Repeat("x", 64<<10)
+	if err := cache.Save("objects", large); err != nil {
+		t.Fatal(err)
+	}
+
+	// A snapshot is replaced as a whole: a load sees the old one or the new one
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if err := cache.Save("objects", large[i:]); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for range 20 {
+		var loaded string
+		if _, err := cache.Load("objects", &loaded); err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) < len(large)-3 {
+			t.Fatalf("loaded a snapshot of %d bytes", len(loaded))
+		}
+	}
+	wg.Wait()
+}
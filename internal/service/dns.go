@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -18,12 +19,15 @@ func NewDNSService(client *alidns.Client) *DNSService {
 }
 
 // FetchDomains retrieves all DNS domains using pagination
-func (s *DNSService) FetchDomains() ([]alidns.DomainInDescribeDomains, error) {
+func (s *DNSService) FetchDomains(ctx context.Context) ([]alidns.DomainInDescribeDomains, error) {
 	var allDomains []alidns.DomainInDescribeDomains
 	pageNumber := int64(1) // SDK uses int64 for PageNumber in response, so keep consistent
 	pageSize := int64(100)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := alidns.CreateDescribeDomainsRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(int(pageNumber)) // CreateDescribeDomainsRequest uses requests.Integer
@@ -52,12 +56,15 @@ func (s *DNSService) FetchDomains() ([]alidns.DomainInDescribeDomains, error) {
 }
 
 // FetchDomainRecords retrieves DNS records for a specific domain using pagination
-func (s *DNSService) FetchDomainRecords(domainName string) ([]alidns.Record, error) {
+func (s *DNSService) FetchDomainRecords(ctx context.Context, domainName string) ([]alidns.Record, error) {
 	var allRecords []alidns.Record
 	pageNumber := int64(1)
 	pageSize := int64(100)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := alidns.CreateDescribeDomainRecordsRequest()
 		request.Scheme = "https"
 		request.DomainName = domainName
@@ -83,3 +90,79 @@ func (s *DNSService) FetchDomainRecords(domainName string) ([]alidns.Record, err
 	}
 	return allRecords, nil
 }
+
+// AddDomainRecord adds a record to a domain and returns the ID of the new record
+func (s *DNSService) AddDomainRecord(ctx context.Context, domainName string, spec DomainRecordSpec) (string, error) {
+	if err := ValidateDomainRecord(spec); err != nil {
+		return "", fmt.Errorf("adding DNS record to %s: %w", domainName, err)
+	}
+	request := alidns.CreateAddDomainRecordRequest()
+	request.Scheme = "https"
+	request.DomainName = domainName
+	request.RR = spec.RR
+	request.Type = spec.Type
+	request.Value = spec.Value
+	request.TTL = requests.NewInteger(int(spec.TTL))
+	request.Line = spec.Line
+	if spec.Type == "MX" {
+		request.Priority = requests.NewInteger(int(spec.Priority))
+	}
+
+	response, err := s.client.AddDomainRecord(request)
+	if err != nil {
+		return "", fmt.Errorf("adding DNS record to %s: %w", domainName, err)
+	}
+	return response.RecordId, nil
+}
+
+// UpdateDomainRecord replaces the fields of a record
+func (s *DNSService) UpdateDomainRecord(ctx context.Context, recordId string, spec DomainRecordSpec) error {
+	if err := ValidateDomainRecord(spec); err != nil {
+		return fmt.Errorf("updating DNS record %s: %w", recordId, err)
+	}
+	request := alidns.CreateUpdateDomainRecordRequest()
+	request.Scheme = "https"
+	request.RecordId = recordId
+	request.RR = spec.RR
+	request.Type = spec.Type
+	request.Value = spec.Value
+	request.TTL = requests.NewInteger(int(spec.TTL))
+	request.Line = spec.Line
+	if spec.Type == "MX" {
+		request.Priority = requests.NewInteger(int(spec.Priority))
+	}
+
+	if _, err := s.client.UpdateDomainRecord(request); err != nil {
+		return fmt.Errorf("updating DNS record %s: %w", recordId, err)
+	}
+	return nil
+}
+
+// DeleteDomainRecord deletes a record
+func (s *DNSService) DeleteDomainRecord(ctx context.Context, recordId string) error {
+	request := alidns.CreateDeleteDomainRecordRequest()
+	request.Scheme = "https"
+	request.RecordId = recordId
+
+	if _, err := s.client.DeleteDomainRecord(request); err != nil {
+		return fmt.Errorf("deleting DNS record %s: %w", recordId, err)
+	}
+	return nil
+}
+
+// SetDomainRecordStatus enables or disables a record. A disabled record is kept but not
+// resolved.
+func (s *DNSService) SetDomainRecordStatus(ctx context.Context, recordId string, enabled bool) error {
+	request := alidns.CreateSetDomainRecordStatusRequest()
+	request.Scheme = "https"
+	request.RecordId = recordId
+	request.Status = "Disable"
+	if enabled {
+		request.Status = "Enable"
+	}
+
+	if _, err := s.client.SetDomainRecordStatus(request); err != nil {
+		return fmt.Errorf("setting status of DNS record %s: %w", recordId, err)
+	}
+	return nil
+}
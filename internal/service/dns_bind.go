@@ -0,0 +1,346 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultZoneTTL is the TTL of zone file records that don't give one, the smallest one
+// every AliDNS edition accepts
+const defaultZoneTTL = 600
+
+// maxCharacterString is the longest character string of a record, in bytes (RFC 1035).
+// Longer TXT values, such as DKIM keys, are split into several strings.
+const maxCharacterString = 255
+
+// bindLineComment marks the resolution line of a record in a BIND zone file, e.g.
+// "www 600 IN A 192.0.2.1 ; line=telecom". BIND has no notion of lines.
+const bindLineComment = "line="
+
+// FormatBIND encodes a zone as a BIND zone file. Hostname targets are written fully
+// qualified, TXT values quoted and split into strings BIND accepts, and lines other than
+// the default one as a trailing "; line=" comment. REDIRECT_URL and FORWARD_URL records
+// keep their AliDNS type.
+func FormatBIND(zone *Zone) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "; Zone %s exported by tali\n", zone.Domain)
+	fmt.Fprintf(&b, "; Lines other than %q are kept in \"; %s\" comments\n", DefaultDomainRecordLine, bindLineComment)
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", zone.Domain)
+	fmt.Fprintf(&b, "$TTL %d\n\n", defaultZoneTTL)
+
+	width := 1
+	for _, spec := range zone.Records {
+		width = max(width, len(spec.RR))
+	}
+	for _, spec := range zone.Records {
+		fmt.Fprintf(&b, "%-*s %d IN %s %s", width, spec.RR, spec.TTL, spec.Type, bindValue(spec))
+		if spec.Line != "" && spec.Line != DefaultDomainRecordLine {
+			fmt.Fprintf(&b, " ; %s%s", bindLineComment, spec.Line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// bindValue returns the RDATA of a record as written in a zone file
+func bindValue(spec DomainRecordSpec) string {
+	switch spec.Type {
+	case "CNAME", "NS":
+		return fqdn(spec.Value)
+	case "MX":
+		return fmt.Sprintf("%d %s", spec.Priority, fqdn(spec.Value))
+	case "SRV":
+		if fields := strings.Fields(spec.Value); len(fields) == 4 {
+			fields[3] = fqdn(fields[3])
+			return strings.Join(fields, " ")
+		}
+	case "TXT":
+		return quoteTXT(spec.Value)
+	}
+	return spec.Value
+}
+
+// quoteTXT writes a TXT value as quoted character strings of at most maxCharacterString
+// bytes each, escaped as RFC 1035 requires: '"' and '\' with a backslash, bytes that are
+// not printable ASCII as \DDD with the decimal value
+func quoteTXT(value string) string {
+	var b strings.Builder
+	for {
+		chunk := value[:min(len(value), maxCharacterString)]
+		value = value[len(chunk):]
+
+		b.WriteByte('"')
+		for i := 0; i < len(chunk); i++ {
+			switch c := chunk[i]; {
+			case c == '"' || c == '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c < ' ' || c > '~':
+				fmt.Fprintf(&b, "\\%03d", c)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		b.WriteByte('"')
+
+		if value == "" {
+			return b.String()
+		}
+		b.WriteByte(' ')
+	}
+}
+
+// fqdn adds the trailing dot of a fully qualified name
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ParseBIND decodes a BIND zone file for the domain. It understands $ORIGIN (which must be
+// the domain), $TTL, omitted owners and TTLs, the IN class, relative and fully qualified
+// names, and the "; line=" comments written by FormatBIND. SOA records are skipped since
+// AliDNS manages them; multi-line records in parentheses are not supported.
+func ParseBIND(text, domainName string) (*Zone, error) {
+	zone := &Zone{Domain: domainName, Records: []DomainRecordSpec{}}
+	origin := strings.ToLower(domainName)
+	ttl := int64(0)
+	owner := ""
+
+	for n, raw := range strings.Split(text, "\n") {
+		lineNo := n + 1
+		content, comment := splitBINDComment(strings.TrimRight(raw, "\r"))
+		tokens, err := bindTokens(content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(tokens[0], "$") {
+			switch strings.ToUpper(tokens[0]) {
+			case "$ORIGIN":
+				if len(tokens) != 2 || !strings.EqualFold(strings.TrimSuffix(tokens[1], "."), origin) {
+					return nil, fmt.Errorf("line %d: $ORIGIN must be %s.", lineNo, domainName)
+				}
+			case "$TTL":
+				if len(tokens) != 2 {
+					return nil, fmt.Errorf("line %d: $TTL needs one value", lineNo)
+				}
+				if ttl, err = strconv.ParseInt(tokens[1], 10, 64); err != nil {
+					return nil, fmt.Errorf("line %d: invalid $TTL %q", lineNo, tokens[1])
+				}
+			default:
+				return nil, fmt.Errorf("line %d: unsupported directive %s", lineNo, tokens[0])
+			}
+			continue
+		}
+		if strings.ContainsAny(content, "()") && !strings.Contains(content, `"`) {
+			return nil, fmt.Errorf("line %d: multi-line records in parentheses are not supported", lineNo)
+		}
+
+		// A line starting with whitespace continues the previous owner
+		if raw[0] != ' ' && raw[0] != '\t' {
+			if owner, err = relativeName(tokens[0], origin); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			tokens = tokens[1:]
+		} else if owner == "" {
+			return nil, fmt.Errorf("line %d: record without an owner name", lineNo)
+		}
+
+		spec := DomainRecordSpec{RR: owner, TTL: ttl, Line: DefaultDomainRecordLine}
+		for len(tokens) > 0 {
+			if n, err := strconv.ParseInt(tokens[0], 10, 64); err == nil {
+				spec.TTL = n
+			} else if strings.EqualFold(tokens[0], "IN") {
+				// The only class there is
+			} else {
+				break
+			}
+			tokens = tokens[1:]
+		}
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("line %d: expected a record type and value", lineNo)
+		}
+		spec.Type = strings.ToUpper(tokens[0])
+		if spec.Type == "SOA" {
+			continue
+		}
+		if err := setBINDValue(&spec, tokens[1:], origin); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if line, ok := strings.CutPrefix(strings.TrimSpace(comment), bindLineComment); ok && line != "" {
+			spec.Line = strings.TrimSpace(line)
+		}
+		zone.Records = append(zone.Records, spec)
+	}
+	return zone, nil
+}
+
+// setBINDValue fills the value (and MX priority) of a record from its RDATA tokens
+func setBINDValue(spec *DomainRecordSpec, rdata []string, origin string) error {
+	switch spec.Type {
+	case "CNAME", "NS":
+		if len(rdata) != 1 {
+			return fmt.Errorf("%s record needs one target", spec.Type)
+		}
+		spec.Value = absoluteName(rdata[0], origin)
+	case "MX":
+		if len(rdata) != 2 {
+			return fmt.Errorf("MX record needs a priority and a target")
+		}
+		priority, err := strconv.ParseInt(rdata[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MX priority %q", rdata[0])
+		}
+		spec.Priority = priority
+		spec.Value = absoluteName(rdata[1], origin)
+	case "SRV":
+		if len(rdata) != 4 {
+			return fmt.Errorf("SRV record needs priority, weight, port and target")
+		}
+		spec.Value = strings.Join(append(rdata[:3:3], absoluteName(rdata[3], origin)), " ")
+	case "TXT":
+		// Character strings are concatenated, as resolvers do
+		var value strings.Builder
+		for _, token := range rdata {
+			if len(token) >= 2 && strings.HasPrefix(token, `"`) {
+				token = token[1 : len(token)-1]
+			}
+			unescaped, err := unescapeCharacterString(token)
+			if err != nil {
+				return fmt.Errorf("invalid TXT string %s: %w", token, err)
+			}
+			value.WriteString(unescaped)
+		}
+		spec.Value = value.String()
+	default:
+		spec.Value = strings.Join(rdata, " ")
+	}
+	return nil
+}
+
+// unescapeCharacterString decodes the RFC 1035 escapes of a character string: \DDD is the
+// byte with the decimal value DDD, and a backslash before any other character stands for
+// that character
+func unescapeCharacterString(text string) (string, error) {
+	if !strings.Contains(text, `\`) {
+		return text, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch {
+		case i >= len(text):
+			return "", fmt.Errorf("trailing backslash")
+		case i+3 <= len(text) && isDigits(text[i:i+3]):
+			n, _ := strconv.Atoi(text[i : i+3])
+			if n > 255 {
+				return "", fmt.Errorf("escape \\%s is not a byte", text[i:i+3])
+			}
+			b.WriteByte(byte(n))
+			i += 2
+		case text[i] >= '0' && text[i] <= '9':
+			return "", fmt.Errorf("escape \\%s needs three digits", text[i:min(i+3, len(text))])
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// isDigits reports whether text is made of decimal digits only
+func isDigits(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// relativeName turns an owner name into a host record of the origin: "@" for the origin
+// itself, relative names as they are
+func relativeName(name, origin string) (string, error) {
+	if name == "@" || !strings.HasSuffix(name, ".") {
+		return name, nil
+	}
+	lower := strings.ToLower(strings.TrimSuffix(name, "."))
+	if lower == origin {
+		return "@", nil
+	}
+	if strings.HasSuffix(lower, "."+origin) {
+		return name[:len(lower)-len(origin)-1], nil
+	}
+	return "", fmt.Errorf("owner %s is outside the zone %s", name, origin)
+}
+
+// absoluteName turns a target name into a hostname without the trailing dot, completing
+// relative names with the origin
+func absoluteName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	}
+	return name + "." + origin
+}
+
+// splitBINDComment splits a zone file line at the first ";" outside a quoted string
+func splitBINDComment(line string) (content, comment string) {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				return line[:i], line[i+1:]
+			}
+		}
+	}
+	return line, ""
+}
+
+// bindTokens splits the content of a zone file line at whitespace, keeping quoted strings
+// (with their quotes) as one token
+func bindTokens(content string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(content); {
+		switch c := content[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(content) && content[end] != '"'; end++ {
+				if content[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(content) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, content[i:end+1])
+			i = end + 1
+		default:
+			end := i
+			for end < len(content) && content[end] != ' ' && content[end] != '\t' {
+				end++
+			}
+			tokens = append(tokens, content[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
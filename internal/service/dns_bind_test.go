@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatBINDTXT(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "hello world", `"hello world"`},
+		{"escaped", `say "hi" \o/`, `"say \"hi\" \\o/"`},
+		{"not printable", "caf\xc3\xa9\ttab", `"caf\195\169\009tab"`},
+		{"split", strings.Repeat("a", 300), `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bindValue(DomainRecordSpec{Type: "TXT", Value: tt.value})
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBIND(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []DomainRecordSpec
+		wantErr string
+	}{
+		{
+			name: "relative names, omitted owner and TTL",
+			text: "$ORIGIN example.com.\n$TTL 300\n" +
+				"@ IN SOA ns1.example.com. admin.example.com. 1 7200 3600 1209600 300\n" +
+				"www 60 IN A 192.0.2.1\n" +
+				"    IN A 192.0.2.2 ; line=unicom\n" +
+				"mail.example.com. MX 5 mx\n" +
+				"alias CNAME @\n",
+			want: []DomainRecordSpec{
+				{RR: "www", Type: "A", Value: "192.0.2.1", TTL: 60, Line: DefaultDomainRecordLine},
+				{RR: "www", Type: "A", Value: "192.0.2.2", TTL: 300, Line: "unicom"},
+				{RR: "mail", Type: "MX", Value: "mx.example.com", TTL: 300, Line: DefaultDomainRecordLine, Priority: 5},
+				{RR: "alias", Type: "CNAME", Value: "example.com", TTL: 300, Line: DefaultDomainRecordLine},
+			},
+		},
+		{
+			name: "TXT strings concatenated and unescaped",
+			text: `@ TXT "v=spf1; " "-all \"x\" \059\\"` + "\n",
+			want: []DomainRecordSpec{
+				{RR: "@", Type: "TXT", Value: `v=spf1; -all "x" ;\`, TTL: defaultZoneTTL, Line: DefaultDomainRecordLine},
+			},
+		},
+		{name: "another origin", text: "$ORIGIN example.org.\n", wantErr: "line 1: $ORIGIN must be example.com."},
+		{name: "owner outside the zone", text: "www.example.org. A 192.0.2.1\n", wantErr: "line 1: owner www.example.org. is outside the zone example.com"},
+		{name: "no owner", text: " A 192.0.2.1\n", wantErr: "line 1: record without an owner name"},
+		{name: "parentheses", text: "@ SOA ns1 admin (\n", wantErr: "line 1: multi-line records in parentheses are not supported"},
+		{name: "bad escape", text: `@ TXT "\256"` + "\n", wantErr: `line 1: invalid TXT string \256: escape \256 is not a byte`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := ParseZone([]byte(tt.text), ZoneFormatBIND, "example.com")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(zone.Records, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", zone.Records, tt.want)
+			}
+		})
+	}
+}
+
+func TestBINDRoundTripTXT(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"quotes and backslashes", `say "hi" \o/ \"`},
+		{"semicolon", "v=DMARC1; p=reject; rua=mailto:dmarc@example.com"},
+		{"escape-like text", `\059 is not a semicolon`},
+		{"not printable", "caf\xc3\xa9\ttab\x00end"},
+		{"255 bytes", strings.Repeat("x", 255)},
+		{"256 bytes", strings.Repeat("x", 256)},
+		{"long DKIM key with quotes", "v=DKIM1; k=rsa; p=" + strings.Repeat(`MIIB"IjAN\Bg`, 60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := &Zone{Domain: "example.com", Records: []DomainRecordSpec{
+				{RR: "@", Type: "TXT", Value: tt.value, TTL: 600, Line: DefaultDomainRecordLine},
+			}}
+			text := FormatBIND(zone)
+			parsed, err := ParseBIND(text, zone.Domain)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, text)
+			}
+			if !reflect.DeepEqual(parsed, zone) {
+				t.Errorf("got\n%+v\nwant\n%+v\nfrom\n%s", parsed, zone, text)
+			}
+		})
+	}
+}
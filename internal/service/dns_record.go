@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+)
+
+// Status of a DNS record as reported by DescribeDomainRecords
+const (
+	DomainRecordEnabled  = "ENABLE"
+	DomainRecordDisabled = "DISABLE"
+)
+
+// DefaultDomainRecordLine is the resolution line that answers every client
+const DefaultDomainRecordLine = "default"
+
+// DomainRecordTypes lists the record types that can be added or edited
+var DomainRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS", "SRV", "CAA", "REDIRECT_URL", "FORWARD_URL"}
+
+// DomainRecordLines lists the common resolution lines. The account may offer more, which
+// are accepted as well.
+var DomainRecordLines = []string{DefaultDomainRecordLine, "telecom", "unicom", "mobile", "edu", "oversea"}
+
+// DomainRecordSpec holds the editable fields of a DNS record
+type DomainRecordSpec struct {
+	// Host record, e.g. "www", "@" for the domain itself or "*" for a wildcard
+	RR    string `json:"rr" yaml:"rr"`
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+	TTL   int64  `json:"ttl" yaml:"ttl"`   // Seconds
+	Line  string `json:"line" yaml:"line"` // Resolution line, see DomainRecordLines
+	// MX records only, lower is preferred
+	Priority int64 `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// DomainRecordSpecOf returns the editable fields of an existing record
+func DomainRecordSpecOf(record alidns.Record) DomainRecordSpec {
+	return DomainRecordSpec{
+		RR:       record.RR,
+		Type:     record.Type,
+		Value:    record.Value,
+		TTL:      record.TTL,
+		Line:     record.Line,
+		Priority: record.Priority,
+	}
+}
+
+// ValidateDomainRecord checks a record before it is sent: the host record, the TTL and
+// the value for its type, e.g. an IPv4 address for A or a hostname for CNAME
+func ValidateDomainRecord(spec DomainRecordSpec) error {
+	if err := validateHostRecord(spec.RR); err != nil {
+		return err
+	}
+	if spec.TTL < 1 || spec.TTL > 86400 {
+		return fmt.Errorf("TTL must be between 1 and 86400 seconds, got %d", spec.TTL)
+	}
+	if spec.Line == "" {
+		return errors.New("line is required, e.g. \"default\"")
+	}
+	if spec.Value == "" {
+		return errors.New("value is required")
+	}
+
+	value := spec.Value
+	switch spec.Type {
+	case "A":
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return fmt.Errorf("A record value %q is not an IPv4 address", value)
+		}
+	case "AAAA":
+		if ip := net.ParseIP(value); ip == nil || !strings.Contains(value, ":") {
+			return fmt.Errorf("AAAA record value %q is not an IPv6 address", value)
+		}
+	case "CNAME", "NS":
+		if !isHostname(value) {
+			return fmt.Errorf("%s record value %q is not a hostname", spec.Type, value)
+		}
+	case "MX":
+		if !isHostname(value) {
+			return fmt.Errorf("MX record value %q is not a hostname", value)
+		}
+		if spec.Priority < 1 || spec.Priority > 50 {
+			return fmt.Errorf("MX priority must be between 1 and 50, got %d", spec.Priority)
+		}
+	case "TXT":
+		if len(value) > 512 {
+			return fmt.Errorf("TXT record value is %d characters long, at most 512 are allowed", len(value))
+		}
+	case "SRV":
+		return validateSRVValue(value)
+	case "CAA":
+		return validateCAAValue(value)
+	case "REDIRECT_URL", "FORWARD_URL":
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s record value %q is not an http or https URL", spec.Type, value)
+		}
+	default:
+		return fmt.Errorf("unsupported record type %q", spec.Type)
+	}
+	return nil
+}
+
+// validateHostRecord checks the RR of a record: "@", or dot-separated labels of which the
+// first may be the wildcard "*"
+func validateHostRecord(rr string) error {
+	if rr == "" {
+		return errors.New("host record (RR) is required, use @ for the domain itself")
+	}
+	if rr == "@" {
+		return nil
+	}
+	for i, label := range strings.Split(rr, ".") {
+		if label == "*" && i == 0 {
+			continue
+		}
+		if !isHostLabel(label, true) {
+			return fmt.Errorf("host record %q is invalid: label %q", rr, label)
+		}
+	}
+	return nil
+}
+
+// isHostname reports whether s is a hostname, optionally fully qualified with a trailing dot
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !isHostLabel(label, false) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostLabel reports whether s is a valid DNS label. Underscores are allowed in host
+// records for service names such as _dmarc.
+func isHostLabel(s string, allowUnderscore bool) bool {
+	if s == "" || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+		case c == '_' && allowUnderscore:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateSRVValue checks an SRV value of the form "priority weight port target"
+func validateSRVValue(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return fmt.Errorf("SRV record value %q must be \"priority weight port target\"", value)
+	}
+	for i, name := range []string{"priority", "weight", "port"} {
+		if n, err := strconv.Atoi(fields[i]); err != nil || n < 0 || n > 65535 {
+			return fmt.Errorf("SRV record %s %q must be a number between 0 and 65535", name, fields[i])
+		}
+	}
+	if !isHostname(fields[3]) {
+		return fmt.Errorf("SRV record target %q is not a hostname", fields[3])
+	}
+	return nil
+}
+
+// validateCAAValue checks a CAA value of the form `flags tag "value"`
+func validateCAAValue(value string) error {
+	fields := strings.SplitN(value, " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("CAA record value %q must be `flags tag \"value\"`", value)
+	}
+	if n, err := strconv.Atoi(fields[0]); err != nil || n < 0 || n > 255 {
+		return fmt.Errorf("CAA record flags %q must be a number between 0 and 255", fields[0])
+	}
+	switch fields[1] {
+	case "issue", "issuewild", "iodef":
+	default:
+		return fmt.Errorf("CAA record tag %q must be issue, issuewild or iodef", fields[1])
+	}
+	if len(fields[2]) < 2 || !strings.HasPrefix(fields[2], `"`) || !strings.HasSuffix(fields[2], `"`) {
+		return fmt.Errorf("CAA record value %s must be quoted", fields[2])
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// record returns a record with a valid TTL and line
+func record(rr, recordType, value string) DomainRecordSpec {
+	return DomainRecordSpec{RR: rr, Type: recordType, Value: value, TTL: 600, Line: "default"}
+}
+
+func TestValidateDomainRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		spec DomainRecordSpec
+		err  string // Part of the error, empty when the record is valid
+	}{
+		{name: "A", spec: record("www", "A", "203.0.113.7")},
+		{name: "apex", spec: record("@", "A", "203.0.113.7")},
+		{name: "wildcard", spec: record("*.app", "A", "203.0.113.7")},
+		{name: "service name", spec: record("_dmarc", "TXT", "v=DMARC1; p=none")},
+		{name: "AAAA", spec: record("www", "AAAA", "2001:db8::7")},
+		{name: "CNAME fully qualified", spec: record("cdn", "CNAME", "example.cdn.net.")},
+		{name: "MX", spec: DomainRecordSpec{RR: "@", Type: "MX", Value: "mx.example.com", TTL: 600, Line: "default", Priority: 10}},
+		{name: "SRV", spec: record("_sip._tcp", "SRV", "10 60 5060 sip.example.com")},
+		{name: "CAA", spec: record("@", "CAA", `0 issue "letsencrypt.org"`)},
+		{name: "forward URL", spec: record("go", "FORWARD_URL", "https://example.org/path")},
+
+		{name: "no RR", spec: record("", "A", "203.0.113.7"), err: "host record (RR) is required"},
+		{name: "wildcard not first", spec: record("app.*", "A", "203.0.113.7"), err: `label "*"`},
+		{name: "label starting with a hyphen", spec: record("-www", "A", "203.0.113.7"), err: `label "-www"`},
+		{name: "TTL of zero", spec: DomainRecordSpec{RR: "www", Type: "A", Value: "203.0.113.7", Line: "default"}, err: "TTL must be between 1 and 86400"},
+		{name: "no line", spec: DomainRecordSpec{RR: "www", Type: "A", Value: "203.0.113.7", TTL: 600}, err: "line is required"},
+		{name: "no value", spec: record("www", "A", ""), err: "value is required"},
+		{name: "A with IPv6", spec: record("www", "A", "2001:db8::7"), err: "is not an IPv4 address"},
+		{name: "A with mapped IPv4", spec: record("www", "A", "::ffff:203.0.113.7"), err: "is not an IPv4 address"},
+		{name: "AAAA with IPv4", spec: record("www", "AAAA", "203.0.113.7"), err: "is not an IPv6 address"},
+		{name: "CNAME with underscore", spec: record("cdn", "CNAME", "my_host.example.com"), err: "is not a hostname"},
+		{name: "MX without priority", spec: record("@", "MX", "mx.example.com"), err: "MX priority must be between 1 and 50"},
+		{name: "TXT too long", spec: record("@", "TXT", strings.Repeat("x", 513)), err: "at most 512"},
+		{name: "SRV missing target", spec: record("_sip._tcp", "SRV", "10 60 5060"), err: `must be "priority weight port target"`},
+		{name: "SRV port out of range", spec: record("_sip._tcp", "SRV", "10 60 70000 sip.example.com"), err: "SRV record port"},
+		{name: "CAA unknown tag", spec: record("@", "CAA", `0 issuer "letsencrypt.org"`), err: "must be issue, issuewild or iodef"},
+		{name: "CAA unquoted", spec: record("@", "CAA", "0 issue letsencrypt.org"), err: "must be quoted"},
+		{name: "redirect without scheme", spec: record("go", "REDIRECT_URL", "example.org/path"), err: "is not an http or https URL"},
+		{name: "unknown type", spec: record("www", "SPF", "v=spf1 -all"), err: `unsupported record type "SPF"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDomainRecord(tt.spec)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("got %v, want an error with %q", err, tt.err)
+			}
+		})
+	}
+}
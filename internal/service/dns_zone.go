@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"gopkg.in/yaml.v3"
+)
+
+// Zone file formats supported by export and import
+const (
+	ZoneFormatBIND = "bind"
+	ZoneFormatYAML = "yaml"
+)
+
+// Zone is the declarative form of a domain's records, as exported to and imported from a
+// zone file. Only the fields of DomainRecordSpec are managed; record status is not.
+type Zone struct {
+	Domain  string             `json:"domain" yaml:"domain"`
+	Records []DomainRecordSpec `json:"records" yaml:"records"`
+}
+
+// ZoneFromRecords builds the zone of a domain from its live records, sorted by host
+// record and type so that exports of the same records are identical
+func ZoneFromRecords(domainName string, records []alidns.Record) *Zone {
+	zone := &Zone{Domain: domainName, Records: make([]DomainRecordSpec, 0, len(records))}
+	for _, record := range records {
+		zone.Records = append(zone.Records, DomainRecordSpecOf(record))
+	}
+	sort.SliceStable(zone.Records, func(i, j int) bool {
+		return recordSortKey(zone.Records[i]) < recordSortKey(zone.Records[j])
+	})
+	return zone
+}
+
+// recordSortKey orders records by host record, type, line and value, with @ first
+func recordSortKey(spec DomainRecordSpec) string {
+	rr := strings.ToLower(spec.RR)
+	if rr == "@" {
+		rr = ""
+	}
+	return strings.Join([]string{rr, spec.Type, spec.Line, spec.Value}, "\x00")
+}
+
+// ZoneFormatForPath picks the zone format from a file name: YAML for .yaml and .yml,
+// BIND otherwise
+func ZoneFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ZoneFormatYAML
+	}
+	return ZoneFormatBIND
+}
+
+// FormatZone encodes a zone in the given format
+func FormatZone(zone *Zone, format string) ([]byte, error) {
+	switch format {
+	case ZoneFormatBIND:
+		return []byte(FormatBIND(zone)), nil
+	case ZoneFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(zone); err != nil {
+			return nil, fmt.Errorf("encoding zone %s as YAML: %w", zone.Domain, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("encoding zone %s as YAML: %w", zone.Domain, err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported zone format '%s' (expected bind or yaml)", format)
+}
+
+// ParseZone decodes a zone file in the given format for the domain. Defaults are filled
+// in (TTL 600, the default line) and every record is checked with ValidateDomainRecord.
+func ParseZone(data []byte, format, domainName string) (*Zone, error) {
+	var zone *Zone
+	switch format {
+	case ZoneFormatBIND:
+		var err error
+		if zone, err = ParseBIND(string(data), domainName); err != nil {
+			return nil, err
+		}
+	case ZoneFormatYAML:
+		zone = &Zone{}
+		if err := yaml.Unmarshal(data, zone); err != nil {
+			return nil, fmt.Errorf("parsing YAML zone: %w", err)
+		}
+		if zone.Domain != "" && !strings.EqualFold(strings.TrimSuffix(zone.Domain, "."), domainName) {
+			return nil, fmt.Errorf("zone file is for %s, not %s", zone.Domain, domainName)
+		}
+		zone.Domain = domainName
+	default:
+		return nil, fmt.Errorf("unsupported zone format '%s' (expected bind or yaml)", format)
+	}
+
+	for i := range zone.Records {
+		spec := &zone.Records[i]
+		if spec.TTL == 0 {
+			spec.TTL = defaultZoneTTL
+		}
+		if spec.Line == "" {
+			spec.Line = DefaultDomainRecordLine
+		}
+		if spec.Type != "MX" {
+			spec.Priority = 0
+		}
+		if err := ValidateDomainRecord(*spec); err != nil {
+			return nil, fmt.Errorf("record %d (%s %s): %w", i+1, spec.RR, spec.Type, err)
+		}
+	}
+	return zone, nil
+}
+
+// ReadZoneFile reads and parses a zone file, picking the format from its name
+func ReadZoneFile(path, domainName string) (*Zone, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading zone file: %w", err)
+	}
+	zone, err := ParseZone(data, ZoneFormatForPath(path), domainName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return zone, nil
+}
+
+// Actions of a ZoneChange
+const (
+	ZoneChangeAdd    = "add"
+	ZoneChangeUpdate = "update"
+	ZoneChangeDelete = "delete"
+)
+
+// ZoneChange is one API call needed to turn the live records into the desired zone
+type ZoneChange struct {
+	Action   string            `json:"action"`
+	RecordId string            `json:"record_id,omitempty"` // Live record, for update and delete
+	Old      *DomainRecordSpec `json:"old,omitempty"`       // Live record, for update and delete
+	New      *DomainRecordSpec `json:"new,omitempty"`       // Desired record, for add and update
+}
+
+// Record returns the record a change is about: the desired one for adds and updates, the
+// live one for deletes
+func (c ZoneChange) Record() DomainRecordSpec {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+// Describe returns the value and TTL of a change for display, as "old -> new" when an
+// update changes them. MX values are prefixed with their priority.
+func (c ZoneChange) Describe() (value, ttl string) {
+	spec := c.Record()
+	value, ttl = displayValue(spec), strconv.FormatInt(spec.TTL, 10)
+	if c.Old == nil || c.New == nil {
+		return value, ttl
+	}
+	if old := displayValue(*c.Old); old != value {
+		value = old + " -> " + value
+	}
+	if c.Old.TTL != spec.TTL {
+		ttl = strconv.FormatInt(c.Old.TTL, 10) + " -> " + ttl
+	}
+	return value, ttl
+}
+
+// displayValue returns the value of a record, prefixed with the priority for MX records
+func displayValue(spec DomainRecordSpec) string {
+	if spec.Type == "MX" {
+		return strconv.FormatInt(spec.Priority, 10) + " " + spec.Value
+	}
+	return spec.Value
+}
+
+// ZoneDiff is the difference between the live records of a domain and a desired zone
+type ZoneDiff struct {
+	Domain    string       `json:"domain"`
+	Changes   []ZoneChange `json:"changes"`
+	Unchanged int          `json:"unchanged"`
+}
+
+// DiffZone compares the live records of a domain with the desired records. Records with
+// the same host record, type, line and value are the same record; they are updated when
+// their TTL or priority differs. The remaining records of each host record, type and line
+// are paired in order as value updates, and what is left over is added or deleted.
+func DiffZone(domainName string, live []alidns.Record, desired []DomainRecordSpec) *ZoneDiff {
+	diff := &ZoneDiff{Domain: domainName, Changes: []ZoneChange{}}
+
+	type liveRecord struct {
+		id   string
+		spec DomainRecordSpec
+	}
+	remaining := make([]*liveRecord, 0, len(live))
+	for _, record := range live {
+		remaining = append(remaining, &liveRecord{id: record.RecordId, spec: DomainRecordSpecOf(record)})
+	}
+	take := func(match func(DomainRecordSpec) bool) *liveRecord {
+		for i, record := range remaining {
+			if record != nil && match(record.spec) {
+				remaining[i] = nil
+				return record
+			}
+		}
+		return nil
+	}
+
+	var unmatched []DomainRecordSpec
+	for _, spec := range desired {
+		record := take(func(candidate DomainRecordSpec) bool {
+			return sameRecordSet(candidate, spec) && candidate.Value == spec.Value
+		})
+		switch {
+		case record == nil:
+			unmatched = append(unmatched, spec)
+		case record.spec == spec:
+			diff.Unchanged++
+		default:
+			diff.Changes = append(diff.Changes, updateChange(record.id, record.spec, spec))
+		}
+	}
+
+	var adds []ZoneChange
+	for _, spec := range unmatched {
+		if record := take(func(candidate DomainRecordSpec) bool { return sameRecordSet(candidate, spec) }); record != nil {
+			diff.Changes = append(diff.Changes, updateChange(record.id, record.spec, spec))
+			continue
+		}
+		spec := spec
+		adds = append(adds, ZoneChange{Action: ZoneChangeAdd, New: &spec})
+	}
+
+	// Deletes go first, so that e.g. an A record can be replaced by a CNAME of the same name
+	var deletes []ZoneChange
+	for _, record := range remaining {
+		if record != nil {
+			spec := record.spec
+			deletes = append(deletes, ZoneChange{Action: ZoneChangeDelete, RecordId: record.id, Old: &spec})
+		}
+	}
+	diff.Changes = append(append(deletes, diff.Changes...), adds...)
+	return diff
+}
+
+// sameRecordSet reports whether two records share host record, type and line
+func sameRecordSet(a, b DomainRecordSpec) bool {
+	return strings.EqualFold(a.RR, b.RR) && a.Type == b.Type && a.Line == b.Line
+}
+
+// updateChange builds the change that turns a live record into the desired one
+func updateChange(recordId string, old, desired DomainRecordSpec) ZoneChange {
+	return ZoneChange{Action: ZoneChangeUpdate, RecordId: recordId, Old: &old, New: &desired}
+}
+
+// ApplyZoneDiff carries out the changes of a diff one by one, in order. It stops at the
+// first failure and returns how many changes were applied before it.
+func ApplyZoneDiff(ctx context.Context, dns DNS, diff *ZoneDiff) (int, error) {
+	for i, change := range diff.Changes {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		var err error
+		switch change.Action {
+		case ZoneChangeAdd:
+			_, err = dns.AddDomainRecord(ctx, diff.Domain, *change.New)
+		case ZoneChangeUpdate:
+			err = dns.UpdateDomainRecord(ctx, change.RecordId, *change.New)
+		case ZoneChangeDelete:
+			err = dns.DeleteDomainRecord(ctx, change.RecordId)
+		default:
+			err = fmt.Errorf("unknown zone change '%s'", change.Action)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(diff.Changes), nil
+}
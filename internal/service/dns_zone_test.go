@@ -0,0 +1,35 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestZoneRoundTrip(t *testing.T) {
+	zone := &Zone{Domain: "example.com", Records: []DomainRecordSpec{
+		{RR: "@", Type: "A", Value: "192.0.2.1", TTL: 600, Line: DefaultDomainRecordLine},
+		{RR: "@", Type: "MX", Value: "mx1.example.com", TTL: 3600, Line: DefaultDomainRecordLine, Priority: 10},
+		{RR: "@", Type: "TXT", Value: `v=spf1 include:spf.example.net ~all; "quoted" \ backslash`, TTL: 600, Line: DefaultDomainRecordLine},
+		{RR: "_sip._tcp", Type: "SRV", Value: "10 5 5060 sip.example.com", TTL: 600, Line: DefaultDomainRecordLine},
+		{RR: "selector._domainkey", Type: "TXT", Value: "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0B", 20), TTL: 600, Line: DefaultDomainRecordLine},
+		{RR: "www", Type: "CNAME", Value: "web.example.net", TTL: 600, Line: DefaultDomainRecordLine},
+		{RR: "www", Type: "A", Value: "192.0.2.2", TTL: 600, Line: "telecom"},
+		{RR: "v6", Type: "AAAA", Value: "2001:db8::1", TTL: 600, Line: DefaultDomainRecordLine},
+	}}
+	for _, format := range []string{ZoneFormatBIND, ZoneFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := FormatZone(zone, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ParseZone(data, format, zone.Domain)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(parsed, zone) {
+				t.Errorf("got\n%+v\nwant\n%+v\nfrom\n%s", parsed, zone, data)
+			}
+		})
+	}
+}
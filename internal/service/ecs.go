@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -17,13 +18,28 @@ func NewECSService(client *ecs.Client) *ECSService {
 	return &ECSService{client: client}
 }
 
+// FetchRegions retrieves the regions available to the account
+func (s *ECSService) FetchRegions(ctx context.Context) ([]ecs.Region, error) {
+	request := ecs.CreateDescribeRegionsRequest()
+	request.Scheme = "https"
+
+	response, err := s.client.DescribeRegions(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing regions: %w", err)
+	}
+	return response.Regions.Region, nil
+}
+
 // FetchInstances retrieves all ECS instances using pagination
-func (s *ECSService) FetchInstances() ([]ecs.Instance, error) {
+func (s *ECSService) FetchInstances(ctx context.Context) ([]ecs.Instance, error) {
 	var allInstances []ecs.Instance
 	pageNumber := 1
 	pageSize := 100 // 使用最大页面大小以减少请求次数
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := ecs.CreateDescribeInstancesRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(pageNumber)
@@ -55,12 +71,15 @@ func (s *ECSService) FetchInstances() ([]ecs.Instance, error) {
 }
 
 // FetchSecurityGroups retrieves all security groups using pagination
-func (s *ECSService) FetchSecurityGroups() ([]ecs.SecurityGroup, error) {
+func (s *ECSService) FetchSecurityGroups(ctx context.Context) ([]ecs.SecurityGroup, error) {
 	var allSecurityGroups []ecs.SecurityGroup
 	pageNumber := 1
 	pageSize := 100 // 使用最大页面大小以减少请求次数
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := ecs.CreateDescribeSecurityGroupsRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(pageNumber)
@@ -92,7 +111,7 @@ func (s *ECSService) FetchSecurityGroups() ([]ecs.SecurityGroup, error) {
 }
 
 // FetchSecurityGroupRules retrieves security group rules for a specific security group
-func (s *ECSService) FetchSecurityGroupRules(securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+func (s *ECSService) FetchSecurityGroupRules(ctx context.Context, securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
 	request := ecs.CreateDescribeSecurityGroupAttributeRequest()
 	request.Scheme = "https"
 	request.SecurityGroupId = securityGroupId
@@ -106,12 +125,15 @@ func (s *ECSService) FetchSecurityGroupRules(securityGroupId string) (*ecs.Descr
 }
 
 // FetchInstancesBySecurityGroup retrieves ECS instances that use a specific security group
-func (s *ECSService) FetchInstancesBySecurityGroup(securityGroupId string) ([]ecs.Instance, error) {
+func (s *ECSService) FetchInstancesBySecurityGroup(ctx context.Context, securityGroupId string) ([]ecs.Instance, error) {
 	var allInstances []ecs.Instance
 	pageNumber := 1
 	pageSize := 100
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := ecs.CreateDescribeInstancesRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(pageNumber)
@@ -142,7 +164,7 @@ func (s *ECSService) FetchInstancesBySecurityGroup(securityGroupId string) ([]ec
 }
 
 // FetchSecurityGroupsByInstance retrieves security groups for a specific ECS instance
-func (s *ECSService) FetchSecurityGroupsByInstance(instanceId string) ([]ecs.SecurityGroup, error) {
+func (s *ECSService) FetchSecurityGroupsByInstance(ctx context.Context, instanceId string) ([]ecs.SecurityGroup, error) {
 	// 首先获取实例详情以获取安全组ID列表
 	request := ecs.CreateDescribeInstancesRequest()
 	request.Scheme = "https"
@@ -167,19 +189,100 @@ func (s *ECSService) FetchSecurityGroupsByInstance(instanceId string) ([]ecs.Sec
 	// 获取安全组详情
 	var securityGroups []ecs.SecurityGroup
 	for _, sgId := range securityGroupIds {
-		sgRequest := ecs.CreateDescribeSecurityGroupsRequest()
-		sgRequest.Scheme = "https"
-		sgRequest.SecurityGroupIds = fmt.Sprintf("[\"%s\"]", sgId)
-
-		sgResponse, err := s.client.DescribeSecurityGroups(sgRequest)
+		group, err := s.FetchSecurityGroup(ctx, sgId)
 		if err != nil {
-			// 记录错误但继续处理其他安全组
-			fmt.Printf("Warning: failed to describe security group %s: %v\n", sgId, err)
-			continue
+			return nil, err
 		}
-
-		securityGroups = append(securityGroups, sgResponse.SecurityGroups.SecurityGroup...)
+		securityGroups = append(securityGroups, *group)
 	}
 
 	return securityGroups, nil
 }
+
+// FetchSecurityGroup retrieves a single security group by ID
+func (s *ECSService) FetchSecurityGroup(ctx context.Context, securityGroupId string) (*ecs.SecurityGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	request := ecs.CreateDescribeSecurityGroupsRequest()
+	request.Scheme = "https"
+	request.SecurityGroupIds = fmt.Sprintf("[\"%s\"]", securityGroupId)
+
+	response, err := s.client.DescribeSecurityGroups(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing security group %s: %w", securityGroupId, err)
+	}
+	if len(response.SecurityGroups.SecurityGroup) == 0 {
+		return nil, fmt.Errorf("describing security group %s: security group not found", securityGroupId)
+	}
+	return &response.SecurityGroups.SecurityGroup[0], nil
+}
+
+// FetchInstance retrieves a single ECS instance, e.g. to follow its status after an action
+func (s *ECSService) FetchInstance(ctx context.Context, instanceId string) (*ecs.Instance, error) {
+	request := ecs.CreateDescribeInstancesRequest()
+	request.Scheme = "https"
+	request.InstanceIds = fmt.Sprintf(`["%s"]`, instanceId)
+
+	response, err := s.client.DescribeInstances(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing ECS instance %s: %w", instanceId, err)
+	}
+	if len(response.Instances.Instance) == 0 {
+		return nil, fmt.Errorf("describing ECS instance %s: instance not found", instanceId)
+	}
+	return &response.Instances.Instance[0], nil
+}
+
+// StartInstance starts a stopped ECS instance
+func (s *ECSService) StartInstance(ctx context.Context, instanceId string) error {
+	request := ecs.CreateStartInstanceRequest()
+	request.Scheme = "https"
+	request.InstanceId = instanceId
+
+	if _, err := s.client.StartInstance(request); err != nil {
+		return fmt.Errorf("starting ECS instance %s: %w", instanceId, err)
+	}
+	return nil
+}
+
+// StopInstance stops a running ECS instance. A forced stop is like cutting the power and
+// may lose data that has not been written to disk.
+func (s *ECSService) StopInstance(ctx context.Context, instanceId string, force bool) error {
+	request := ecs.CreateStopInstanceRequest()
+	request.Scheme = "https"
+	request.InstanceId = instanceId
+	request.ForceStop = requests.NewBoolean(force)
+
+	if _, err := s.client.StopInstance(request); err != nil {
+		return fmt.Errorf("stopping ECS instance %s: %w", instanceId, err)
+	}
+	return nil
+}
+
+// RebootInstance reboots a running ECS instance
+func (s *ECSService) RebootInstance(ctx context.Context, instanceId string) error {
+	request := ecs.CreateRebootInstanceRequest()
+	request.Scheme = "https"
+	request.InstanceId = instanceId
+
+	if _, err := s.client.RebootInstance(request); err != nil {
+		return fmt.Errorf("rebooting ECS instance %s: %w", instanceId, err)
+	}
+	return nil
+}
+
+// ModifyInstanceAttribute changes the name and description of an ECS instance. The API
+// leaves empty values unchanged, so neither can be cleared this way.
+func (s *ECSService) ModifyInstanceAttribute(ctx context.Context, instanceId, name, description string) error {
+	request := ecs.CreateModifyInstanceAttributeRequest()
+	request.Scheme = "https"
+	request.InstanceId = instanceId
+	request.InstanceName = name
+	request.Description = description
+
+	if _, err := s.client.ModifyInstanceAttribute(request); err != nil {
+		return fmt.Errorf("modifying ECS instance %s: %w", instanceId, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+func TestSecurityGroupRuleSpecOf(t *testing.T) {
+	tests := []struct {
+		name string
+		rule ecs.Permission
+		peer string
+	}{
+		{"IPv4 source", ecs.Permission{Direction: "ingress", SourceCidrIp: "10.0.0.0/8"}, "10.0.0.0/8"},
+		{"IPv6 source", ecs.Permission{Direction: "ingress", Ipv6SourceCidrIp: "2001:db8::/32"}, "2001:db8::/32"},
+		{"group source", ecs.Permission{Direction: "ingress", SourceGroupId: "sg-app"}, "sg-app"},
+		{"prefix list source", ecs.Permission{Direction: "ingress", SourcePrefixListId: "pl-office"}, "pl-office"},
+		{"IPv6 destination", ecs.Permission{Direction: "egress", SourceCidrIp: "10.0.0.0/8", Ipv6DestCidrIp: "::/0"}, "::/0"},
+		{"prefix list destination", ecs.Permission{Direction: "egress", DestPrefixListId: "pl-office"}, "pl-office"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if peer := SecurityGroupRuleSpecOf(tt.rule).Peer; peer != tt.peer {
+				t.Errorf("peer %q, want %q", peer, tt.peer)
+			}
+		})
+	}
+}
+
+func TestValidateSecurityGroupRule(t *testing.T) {
+	valid := SecurityGroupRuleSpec{Direction: SecurityGroupRuleIngress, IpProtocol: "TCP", PortRange: "22/22", Peer: "10.0.0.0/8", Policy: "accept", Priority: 1}
+	tests := []struct {
+		name   string
+		modify func(spec *SecurityGroupRuleSpec)
+		err    string // Part of the error, empty when the rule is valid
+	}{
+		{"valid", func(spec *SecurityGroupRuleSpec) {}, ""},
+		{"IPv6 peer", func(spec *SecurityGroupRuleSpec) { spec.Peer = "2001:db8::/32" }, ""},
+		{"group peer", func(spec *SecurityGroupRuleSpec) { spec.Peer = "sg-app" }, ""},
+		{"prefix list peer", func(spec *SecurityGroupRuleSpec) { spec.Peer = "pl-office" }, ""},
+		{"ICMP without ports", func(spec *SecurityGroupRuleSpec) { spec.IpProtocol, spec.PortRange = "ICMP", AllPorts }, ""},
+		{"unknown direction", func(spec *SecurityGroupRuleSpec) { spec.Direction = "inbound" }, "direction must be"},
+		{"unknown protocol", func(spec *SecurityGroupRuleSpec) { spec.IpProtocol = "SCTP" }, "unsupported protocol"},
+		{"port range without a slash", func(spec *SecurityGroupRuleSpec) { spec.PortRange = "22" }, "must be \"from/to\""},
+		{"port range reversed", func(spec *SecurityGroupRuleSpec) { spec.PortRange = "443/80" }, "the lower one first"},
+		{"port out of range", func(spec *SecurityGroupRuleSpec) { spec.PortRange = "1/65536" }, "between 1 and 65535"},
+		{"ICMP with ports", func(spec *SecurityGroupRuleSpec) { spec.IpProtocol = "ICMP" }, "ICMP rules have no ports"},
+		{"no peer", func(spec *SecurityGroupRuleSpec) { spec.Peer = "" }, "is required"},
+		{"peer not a CIDR block", func(spec *SecurityGroupRuleSpec) { spec.Peer = "10.0.0.1" }, "is neither a CIDR block"},
+		{"ICMPv6 from IPv4", func(spec *SecurityGroupRuleSpec) { spec.IpProtocol, spec.PortRange = "ICMPv6", AllPorts }, "need an IPv6 CIDR block"},
+		{"unknown policy", func(spec *SecurityGroupRuleSpec) { spec.Policy = "allow" }, "policy must be"},
+		{"priority too high", func(spec *SecurityGroupRuleSpec) { spec.Priority = 101 }, "priority must be"},
+		{"description too long", func(spec *SecurityGroupRuleSpec) { spec.Description = strings.Repeat("x", 513) }, "at most 512"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid
+			tt.modify(&spec)
+			err := ValidateSecurityGroupRule(spec)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("got %v, want an error with %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSplitPeer(t *testing.T) {
+	tests := []struct {
+		peer                                  string
+		cidr, ipv6Cidr, groupId, prefixListId string
+	}{
+		{peer: "10.0.0.0/8", cidr: "10.0.0.0/8"},
+		{peer: "2001:db8::/32", ipv6Cidr: "2001:db8::/32"},
+		{peer: "sg-app", groupId: "sg-app"},
+		{peer: "pl-office", prefixListId: "pl-office"},
+	}
+	for _, tt := range tests {
+		cidr, ipv6Cidr, groupId, prefixListId := splitPeer(SecurityGroupRuleSpec{Peer: tt.peer})
+		if cidr != tt.cidr || ipv6Cidr != tt.ipv6Cidr || groupId != tt.groupId || prefixListId != tt.prefixListId {
+			t.Errorf("%s: got %q, %q, %q, %q", tt.peer, cidr, ipv6Cidr, groupId, prefixListId)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// DNSService is an in-memory implementation of service.DNS
+type DNSService struct {
+	cloud *Cloud
+}
+
+var _ service.DNS = (*DNSService)(nil)
+
+// NewDNSService creates a fake DNS service backed by the given account
+func NewDNSService(cloud *Cloud) *DNSService {
+	return &DNSService{cloud: cloud}
+}
+
+// FetchDomains returns all domains
+func (s *DNSService) FetchDomains(ctx context.Context) ([]alidns.DomainInDescribeDomains, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]alidns.DomainInDescribeDomains(nil), s.cloud.data.Domains...), nil
+}
+
+// FetchDomainRecords returns the records of a domain
+func (s *DNSService) FetchDomainRecords(ctx context.Context, domainName string) ([]alidns.Record, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasDomain(domainName) {
+		return nil, fmt.Errorf("describing DNS domain records for %s: domain not found", domainName)
+	}
+	return append([]alidns.Record(nil), s.cloud.data.DomainRecords[domainName]...), nil
+}
+
+// hasDomain reports whether the domain exists; the caller must hold the lock
+func (s *DNSService) hasDomain(domainName string) bool {
+	for _, domain := range s.cloud.data.Domains {
+		if domain.DomainName == domainName {
+			return true
+		}
+	}
+	return false
+}
+
+// AddDomainRecord adds a record to a domain
+func (s *DNSService) AddDomainRecord(ctx context.Context, domainName string, spec service.DomainRecordSpec) (string, error) {
+	if err := service.ValidateDomainRecord(spec); err != nil {
+		return "", fmt.Errorf("adding DNS record to %s: %w", domainName, err)
+	}
+
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	if !s.hasDomain(domainName) {
+		return "", fmt.Errorf("adding DNS record to %s: domain not found", domainName)
+	}
+	record := alidns.Record{
+		RecordId:   s.cloud.newID("fake-record-"),
+		DomainName: domainName,
+		Status:     service.DomainRecordEnabled,
+	}
+	applyRecordSpec(&record, spec)
+	if s.cloud.data.DomainRecords == nil {
+		s.cloud.data.DomainRecords = make(map[string][]alidns.Record)
+	}
+	s.cloud.data.DomainRecords[domainName] = append(s.cloud.data.DomainRecords[domainName], record)
+	s.countRecords(domainName)
+	return record.RecordId, nil
+}
+
+// UpdateDomainRecord replaces the fields of a record
+func (s *DNSService) UpdateDomainRecord(ctx context.Context, recordId string, spec service.DomainRecordSpec) error {
+	if err := service.ValidateDomainRecord(spec); err != nil {
+		return fmt.Errorf("updating DNS record %s: %w", recordId, err)
+	}
+
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	record, ok := s.findRecord(recordId)
+	if !ok {
+		return fmt.Errorf("updating DNS record %s: record not found", recordId)
+	}
+	applyRecordSpec(record, spec)
+	return nil
+}
+
+// DeleteDomainRecord deletes a record
+func (s *DNSService) DeleteDomainRecord(ctx context.Context, recordId string) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	for domainName, records := range s.cloud.data.DomainRecords {
+		for i, record := range records {
+			if record.RecordId == recordId {
+				s.cloud.data.DomainRecords[domainName] = append(records[:i:i], records[i+1:]...)
+				s.countRecords(domainName)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("deleting DNS record %s: record not found", recordId)
+}
+
+// SetDomainRecordStatus enables or disables a record
+func (s *DNSService) SetDomainRecordStatus(ctx context.Context, recordId string, enabled bool) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	record, ok := s.findRecord(recordId)
+	if !ok {
+		return fmt.Errorf("setting status of DNS record %s: record not found", recordId)
+	}
+	record.Status = service.DomainRecordDisabled
+	if enabled {
+		record.Status = service.DomainRecordEnabled
+	}
+	return nil
+}
+
+// findRecord returns a record by ID for modification; the caller must hold the lock
+func (s *DNSService) findRecord(recordId string) (*alidns.Record, bool) {
+	for _, records := range s.cloud.data.DomainRecords {
+		for i := range records {
+			if records[i].RecordId == recordId {
+				return &records[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// countRecords updates the record count of a domain; the caller must hold the lock
+func (s *DNSService) countRecords(domainName string) {
+	for i := range s.cloud.data.Domains {
+		if s.cloud.data.Domains[i].DomainName == domainName {
+			s.cloud.data.Domains[i].RecordCount = int64(len(s.cloud.data.DomainRecords[domainName]))
+		}
+	}
+}
+
+// applyRecordSpec copies the editable fields into a record
+func applyRecordSpec(record *alidns.Record, spec service.DomainRecordSpec) {
+	record.RR = spec.RR
+	record.Type = spec.Type
+	record.Value = spec.Value
+	record.TTL = spec.TTL
+	record.Line = spec.Line
+	record.Priority = spec.Priority
+}
@@ -0,0 +1,310 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// ECSService is an in-memory implementation of service.ECS
+type ECSService struct {
+	cloud *Cloud
+}
+
+var _ service.ECS = (*ECSService)(nil)
+
+// NewECSService creates a fake ECS service backed by the given account
+func NewECSService(cloud *Cloud) *ECSService {
+	return &ECSService{cloud: cloud}
+}
+
+// FetchRegions returns the regions of the account
+func (s *ECSService) FetchRegions(ctx context.Context) ([]ecs.Region, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]ecs.Region(nil), s.cloud.data.Regions...), nil
+}
+
+// FetchInstances returns all ECS instances
+func (s *ECSService) FetchInstances(ctx context.Context) ([]ecs.Instance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]ecs.Instance(nil), s.cloud.data.ECSInstances...), nil
+}
+
+// FetchSecurityGroups returns all security groups
+func (s *ECSService) FetchSecurityGroups(ctx context.Context) ([]ecs.SecurityGroup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]ecs.SecurityGroup(nil), s.cloud.data.SecurityGroups...), nil
+}
+
+// FetchSecurityGroupRules returns the rules of a security group
+func (s *ECSService) FetchSecurityGroupRules(ctx context.Context, securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	sg, ok := s.findSecurityGroup(securityGroupId)
+	if !ok {
+		return nil, fmt.Errorf("describing security group rules for %s: security group not found", securityGroupId)
+	}
+
+	response := &ecs.DescribeSecurityGroupAttributeResponse{
+		SecurityGroupId:   sg.SecurityGroupId,
+		SecurityGroupName: sg.SecurityGroupName,
+		Description:       sg.Description,
+		VpcId:             sg.VpcId,
+	}
+	response.Permissions.Permission = append([]ecs.Permission(nil), s.cloud.data.SecurityGroupRules[securityGroupId]...)
+	return response, nil
+}
+
+// FetchInstancesBySecurityGroup returns the instances that belong to a security group
+func (s *ECSService) FetchInstancesBySecurityGroup(ctx context.Context, securityGroupId string) ([]ecs.Instance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	var instances []ecs.Instance
+	for _, inst := range s.cloud.data.ECSInstances {
+		for _, sgId := range inst.SecurityGroupIds.SecurityGroupId {
+			if sgId == securityGroupId {
+				instances = append(instances, inst)
+				break
+			}
+		}
+	}
+	return instances, nil
+}
+
+// FetchSecurityGroupsByInstance returns the security groups of an instance
+func (s *ECSService) FetchSecurityGroupsByInstance(ctx context.Context, instanceId string) ([]ecs.SecurityGroup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	securityGroups := []ecs.SecurityGroup{}
+	for _, inst := range s.cloud.data.ECSInstances {
+		if inst.InstanceId != instanceId {
+			continue
+		}
+		for _, sgId := range inst.SecurityGroupIds.SecurityGroupId {
+			if sg, ok := s.findSecurityGroup(sgId); ok {
+				securityGroups = append(securityGroups, sg)
+			}
+		}
+	}
+	return securityGroups, nil
+}
+
+// FetchSecurityGroup returns a single security group
+func (s *ECSService) FetchSecurityGroup(ctx context.Context, securityGroupId string) (*ecs.SecurityGroup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	sg, ok := s.findSecurityGroup(securityGroupId)
+	if !ok {
+		return nil, fmt.Errorf("describing security group %s: security group not found", securityGroupId)
+	}
+	return &sg, nil
+}
+
+// findSecurityGroup looks up a security group by ID; the caller must hold the lock
+func (s *ECSService) findSecurityGroup(securityGroupId string) (ecs.SecurityGroup, bool) {
+	for _, sg := range s.cloud.data.SecurityGroups {
+		if sg.SecurityGroupId == securityGroupId {
+			return sg, true
+		}
+	}
+	return ecs.SecurityGroup{}, false
+}
+
+// FetchInstance returns a single ECS instance
+func (s *ECSService) FetchInstance(ctx context.Context, instanceId string) (*ecs.Instance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	i, ok := s.findInstance(instanceId)
+	if !ok {
+		return nil, fmt.Errorf("describing ECS instance %s: instance not found", instanceId)
+	}
+	inst := s.cloud.data.ECSInstances[i]
+	return &inst, nil
+}
+
+// StartInstance starts a stopped instance. The fake account has no boot time, so the
+// instance is running right away.
+func (s *ECSService) StartInstance(ctx context.Context, instanceId string) error {
+	return s.transition(instanceId, "starting", "Stopped", "Running")
+}
+
+// StopInstance stops a running instance
+func (s *ECSService) StopInstance(ctx context.Context, instanceId string, force bool) error {
+	return s.transition(instanceId, "stopping", "Running", "Stopped")
+}
+
+// RebootInstance reboots a running instance
+func (s *ECSService) RebootInstance(ctx context.Context, instanceId string) error {
+	return s.transition(instanceId, "rebooting", "Running", "Running")
+}
+
+// ModifyInstanceAttribute changes the name and description of an instance; empty values
+// are left unchanged like in the real API
+func (s *ECSService) ModifyInstanceAttribute(ctx context.Context, instanceId, name, description string) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	i, ok := s.findInstance(instanceId)
+	if !ok {
+		return fmt.Errorf("modifying ECS instance %s: instance not found", instanceId)
+	}
+	if name != "" {
+		s.cloud.data.ECSInstances[i].InstanceName = name
+	}
+	if description != "" {
+		s.cloud.data.ECSInstances[i].Description = description
+	}
+	return nil
+}
+
+// AuthorizeSecurityGroupRule adds a rule to a security group, refusing a rule that
+// already exists like the real API
+func (s *ECSService) AuthorizeSecurityGroupRule(ctx context.Context, securityGroupId string, spec service.SecurityGroupRuleSpec) error {
+	if err := service.ValidateSecurityGroupRule(spec); err != nil {
+		return fmt.Errorf("adding rule to security group %s: %w", securityGroupId, err)
+	}
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	if _, ok := s.findSecurityGroup(securityGroupId); !ok {
+		return fmt.Errorf("adding rule to security group %s: InvalidSecurityGroupId.NotFound", securityGroupId)
+	}
+	rules := s.cloud.data.SecurityGroupRules[securityGroupId]
+	for _, rule := range rules {
+		existing := service.SecurityGroupRuleSpecOf(rule)
+		existing.Description = spec.Description
+		if existing == spec {
+			return fmt.Errorf("adding rule to security group %s: InvalidPermission.Duplicate: the rule already exists as %s", securityGroupId, rule.SecurityGroupRuleId)
+		}
+	}
+
+	rule := permissionOf(spec)
+	rule.SecurityGroupRuleId = s.cloud.newID("sgr-fake")
+	rule.CreateTime = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	if s.cloud.data.SecurityGroupRules == nil {
+		s.cloud.data.SecurityGroupRules = map[string][]ecs.Permission{}
+	}
+	s.cloud.data.SecurityGroupRules[securityGroupId] = append(rules, rule)
+	return nil
+}
+
+// ModifySecurityGroupRule changes a rule of a security group in place
+func (s *ECSService) ModifySecurityGroupRule(ctx context.Context, securityGroupId, ruleId string, spec service.SecurityGroupRuleSpec) error {
+	if err := service.ValidateSecurityGroupRule(spec); err != nil {
+		return fmt.Errorf("modifying rule %s of security group %s: %w", ruleId, securityGroupId, err)
+	}
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	rules := s.cloud.data.SecurityGroupRules[securityGroupId]
+	i, ok := findRule(rules, ruleId, spec.Direction)
+	if !ok {
+		return fmt.Errorf("modifying rule %s of security group %s: InvalidSecurityGroupRuleId.NotFound", ruleId, securityGroupId)
+	}
+	rule := permissionOf(spec)
+	rule.SecurityGroupRuleId, rule.CreateTime = ruleId, rules[i].CreateTime
+	rules[i] = rule
+	return nil
+}
+
+// RevokeSecurityGroupRule removes a rule from a security group
+func (s *ECSService) RevokeSecurityGroupRule(ctx context.Context, securityGroupId, ruleId, direction string) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	rules := s.cloud.data.SecurityGroupRules[securityGroupId]
+	i, ok := findRule(rules, ruleId, direction)
+	if !ok {
+		return fmt.Errorf("revoking rule %s of security group %s: InvalidSecurityGroupRuleId.NotFound", ruleId, securityGroupId)
+	}
+	s.cloud.data.SecurityGroupRules[securityGroupId] = slices.Delete(rules, i, i+1)
+	return nil
+}
+
+// findRule returns the index of a rule with the given ID and direction
+func findRule(rules []ecs.Permission, ruleId, direction string) (int, bool) {
+	for i, rule := range rules {
+		if rule.SecurityGroupRuleId == ruleId && service.SecurityGroupRuleSpecOf(rule).Direction == direction {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// permissionOf returns a rule as DescribeSecurityGroupAttribute reports it
+func permissionOf(spec service.SecurityGroupRuleSpec) ecs.Permission {
+	rule := ecs.Permission{
+		Direction:   spec.Direction,
+		IpProtocol:  spec.IpProtocol,
+		PortRange:   spec.PortRange,
+		Policy:      strings.ToUpper(spec.Policy[:1]) + spec.Policy[1:], // "Accept" or "Drop"
+		Priority:    strconv.Itoa(spec.Priority),
+		Description: spec.Description,
+	}
+	ipv6 := strings.Contains(spec.Peer, ":")
+	switch {
+	case spec.Direction == service.SecurityGroupRuleEgress && spec.PeerIsGroup():
+		rule.DestGroupId = spec.Peer
+	case spec.Direction == service.SecurityGroupRuleEgress && spec.PeerIsPrefixList():
+		rule.DestPrefixListId = spec.Peer
+	case spec.Direction == service.SecurityGroupRuleEgress && ipv6:
+		rule.Ipv6DestCidrIp = spec.Peer
+	case spec.Direction == service.SecurityGroupRuleEgress:
+		rule.DestCidrIp = spec.Peer
+	case spec.PeerIsGroup():
+		rule.SourceGroupId = spec.Peer
+	case spec.PeerIsPrefixList():
+		rule.SourcePrefixListId = spec.Peer
+	case ipv6:
+		rule.Ipv6SourceCidrIp = spec.Peer
+	default:
+		rule.SourceCidrIp = spec.Peer
+	}
+	return rule
+}
+
+// transition moves an instance from one status to another, failing like the real API
+// when the instance is not in the expected status
+func (s *ECSService) transition(instanceId, verb, from, to string) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	i, ok := s.findInstance(instanceId)
+	if !ok {
+		return fmt.Errorf("%s ECS instance %s: instance not found", verb, instanceId)
+	}
+	inst := &s.cloud.data.ECSInstances[i]
+	if inst.Status != from {
+		return fmt.Errorf("%s ECS instance %s: IncorrectInstanceStatus: the instance is %s", verb, instanceId, inst.Status)
+	}
+	inst.Status = to
+	return nil
+}
+
+// findInstance returns the index of an instance in the fixtures; the caller must hold the lock
+func (s *ECSService) findInstance(instanceId string) (int, bool) {
+	for i, inst := range s.cloud.data.ECSInstances {
+		if inst.InstanceId == instanceId {
+			return i, true
+		}
+	}
+	return -1, false
+}
@@ -0,0 +1,118 @@
+// Package fake provides in-memory implementations of the service interfaces.
+//
+// The fakes are seeded from a JSON fixture file (see fixtures/sample.json) so
+// that the application and its navigation flows can be exercised without
+// Alibaba Cloud credentials.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	r_kvstore "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// Fixtures describes the contents of a fake Alibaba Cloud account
+type Fixtures struct {
+	Regions []ecs.Region `json:"regions"`
+
+	ECSInstances       []ecs.Instance              `json:"ecs_instances"`
+	SecurityGroups     []ecs.SecurityGroup         `json:"security_groups"`
+	SecurityGroupRules map[string][]ecs.Permission `json:"security_group_rules"` // keyed by security group ID
+
+	Domains       []alidns.DomainInDescribeDomains `json:"domains"`
+	DomainRecords map[string][]alidns.Record       `json:"domain_records"` // keyed by domain name
+
+	LoadBalancers  []slb.LoadBalancer                                            `json:"load_balancers"`
+	Listeners      map[string][]service.ListenerDetail                           `json:"listeners"`       // keyed by load balancer ID
+	VServerGroups  map[string][]slb.VServerGroup                                 `json:"vserver_groups"`  // keyed by load balancer ID
+	BackendServers map[string][]slb.BackendServerInDescribeVServerGroupAttribute `json:"backend_servers"` // keyed by VServer group ID
+
+	// Full configuration of listeners, keyed by load balancer ID. Listeners without one
+	// have the settings of their listener entry only.
+	ListenerAttributes map[string][]service.ListenerAttributes `json:"listener_attributes"`
+
+	Buckets       []oss.BucketProperties            `json:"buckets"`
+	Objects       map[string][]oss.ObjectProperties `json:"objects"`        // keyed by bucket name
+	BucketConfigs map[string]service.BucketConfig   `json:"bucket_configs"` // keyed by bucket name
+
+	// Versions and delete markers of objects, keyed by bucket name. Objects without
+	// versions have a single version, the current one.
+	ObjectVersions map[string][]service.ObjectVersion `json:"object_versions"`
+
+	// Content of objects, keyed by bucket name and object key. Objects without content
+	// read as generated text of their size.
+	ObjectContents map[string]map[string]string `json:"object_contents"`
+
+	RDSInstances []rds.DBInstance                   `json:"rds_instances"`
+	RDSDatabases map[string][]rds.Database          `json:"rds_databases"` // keyed by DB instance ID
+	RDSAccounts  map[string][]rds.DBInstanceAccount `json:"rds_accounts"`  // keyed by DB instance ID
+
+	// Details of RDS instances, keyed by DB instance ID. Read-only instances are the RDS
+	// instances whose MasterInstanceId is the primary.
+	RDSNetInfo        map[string][]rds.DBInstanceNetInfo          `json:"rds_net_info"`
+	RDSIPWhitelists   map[string][]rds.DBInstanceIPArray          `json:"rds_ip_whitelists"`
+	RDSParameters     map[string][]service.RDSParameter           `json:"rds_parameters"`
+	RDSBackups        map[string][]rds.Backup                     `json:"rds_backups"`
+	RDSBackupPolicies map[string]rds.DescribeBackupPolicyResponse `json:"rds_backup_policies"`
+	RDSSlowLogs       map[string][]rds.SQLSlowLog                 `json:"rds_slow_logs"`
+
+	RedisInstances []r_kvstore.KVStoreInstance    `json:"redis_instances"`
+	RedisAccounts  map[string][]r_kvstore.Account `json:"redis_accounts"` // keyed by instance ID
+
+	RocketMQInstances []service.RocketMQInstance         `json:"rocketmq_instances"`
+	RocketMQTopics    map[string][]service.RocketMQTopic `json:"rocketmq_topics"` // keyed by instance ID
+	RocketMQGroups    map[string][]service.RocketMQGroup `json:"rocketmq_groups"` // keyed by instance ID
+}
+
+// Cloud is an in-memory account shared by all fake services
+type Cloud struct {
+	mu     sync.RWMutex
+	data   *Fixtures
+	lastID int // Sequence of the IDs given to created resources
+}
+
+// NewCloud creates a fake account seeded with the given fixtures
+func NewCloud(fixtures *Fixtures) *Cloud {
+	if fixtures == nil {
+		fixtures = &Fixtures{}
+	}
+	return &Cloud{data: fixtures}
+}
+
+// ParseFixtures decodes fixtures from JSON
+func ParseFixtures(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("parsing fixtures: %w", err)
+	}
+	return &fixtures, nil
+}
+
+// LoadCloud reads a JSON fixture file and creates a fake account from it
+func LoadCloud(path string) (*Cloud, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixtures %s: %w", path, err)
+	}
+	fixtures, err := ParseFixtures(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewCloud(fixtures), nil
+}
+
+// newID returns a fresh resource ID with the given prefix; the caller must hold the lock
+func (c *Cloud) newID(prefix string) string {
+	c.lastID++
+	return fmt.Sprintf("%s%d", prefix, c.lastID)
+}
@@ -0,0 +1,461 @@
+package fake
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// OSSService is an in-memory implementation of service.OSS
+type OSSService struct {
+	cloud *Cloud
+}
+
+var _ service.OSS = (*OSSService)(nil)
+
+// NewOSSService creates a fake OSS service backed by the given account
+func NewOSSService(cloud *Cloud) *OSSService {
+	return &OSSService{cloud: cloud}
+}
+
+// FetchBuckets returns all buckets
+func (s *OSSService) FetchBuckets(ctx context.Context) ([]oss.BucketProperties, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]oss.BucketProperties(nil), s.cloud.data.Buckets...), nil
+}
+
+// FetchObjects returns one page of objects in key order, starting after marker
+func (s *OSSService) FetchObjects(ctx context.Context, bucketName string, marker string, pageSize int) (*service.ObjectListResult, error) {
+	return s.FetchObjectsWithPrefix(ctx, bucketName, "", marker, pageSize)
+}
+
+// FetchObjectsWithPrefix returns one page of the objects whose keys start with prefix
+func (s *OSSService) FetchObjectsWithPrefix(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*service.ObjectListResult, error) {
+	return s.listObjects(bucketName, prefix, "", marker, pageSize)
+}
+
+// FetchFolder returns one page of the objects and subfolders directly under prefix
+func (s *OSSService) FetchFolder(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*service.ObjectListResult, error) {
+	return s.listObjects(bucketName, prefix, service.OSSDelimiter, marker, pageSize)
+}
+
+// WalkObjects calls visit with the objects under prefix, in pages of up to 1000 like the
+// SDK-backed service
+func (s *OSSService) WalkObjects(ctx context.Context, bucketName, prefix string, visit func(objects []oss.ObjectProperties) error) error {
+	marker := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := s.listObjects(bucketName, prefix, "", marker, 1000)
+		if err != nil {
+			return err
+		}
+		if err := visit(result.Objects); err != nil {
+			return err
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// listObjects pages through the keys under prefix like ListObjects: with a delimiter, the
+// keys below the next delimiter are rolled up into one folder entry, and folders and
+// objects share the page size and the marker
+func (s *OSSService) listObjects(bucketName, prefix, delimiter, marker string, pageSize int) (*service.ObjectListResult, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasBucket(bucketName) {
+		return nil, fmt.Errorf("listing objects in bucket %s (marker: %s): bucket not found", bucketName, marker)
+	}
+
+	type entry struct {
+		key    string
+		object *oss.ObjectProperties // nil for a folder
+	}
+	var entries []entry
+	folders := map[string]bool{}
+	for i, obj := range s.cloud.data.Objects[bucketName] {
+		if !strings.HasPrefix(obj.Key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if n := strings.Index(obj.Key[len(prefix):], delimiter); n >= 0 {
+				folder := obj.Key[:len(prefix)+n+len(delimiter)]
+				if !folders[folder] {
+					folders[folder] = true
+					entries = append(entries, entry{key: folder})
+				}
+				continue
+			}
+		}
+		entries = append(entries, entry{key: obj.Key, object: &s.cloud.data.Objects[bucketName][i]})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	start := sort.Search(len(entries), func(i int) bool { return entries[i].key > marker })
+	end := len(entries)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+	page := entries[start:end]
+
+	result := &service.ObjectListResult{
+		PrevMarker:  marker,
+		IsTruncated: end < len(entries),
+		HasPrevious: marker != "",
+	}
+	for _, e := range page {
+		if e.object != nil {
+			result.Objects = append(result.Objects, *e.object)
+		} else {
+			result.Folders = append(result.Folders, e.key)
+		}
+	}
+	if result.IsTruncated && len(page) > 0 {
+		result.NextMarker = page[len(page)-1].key
+	}
+	return result, nil
+}
+
+// FetchBucketConfig returns the fixture configuration of a bucket, or a private bucket
+// without any optional configuration if there is none
+func (s *OSSService) FetchBucketConfig(ctx context.Context, bucketName string) (*service.BucketConfig, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	for _, bucket := range s.cloud.data.Buckets {
+		if bucket.Name != bucketName {
+			continue
+		}
+		config, ok := s.cloud.data.BucketConfigs[bucketName]
+		if !ok {
+			config.ACL = "private"
+		}
+		config.Info.Name = bucket.Name
+		config.Info.Location = bucket.Location
+		config.Info.CreationDate = bucket.CreationDate
+		config.Info.StorageClass = bucket.StorageClass
+		if config.Info.ACL == "" {
+			config.Info.ACL = config.ACL
+		}
+		if config.Info.Versioning == "" {
+			config.Info.Versioning = config.Versioning
+		}
+		return &config, nil
+	}
+	return nil, fmt.Errorf("accessing bucket %s: bucket not found", bucketName)
+}
+
+// DownloadObject writes the content of an object to a local file
+func (s *OSSService) DownloadObject(ctx context.Context, bucketName, objectKey, filePath string, progress service.TransferProgress) error {
+	s.cloud.mu.RLock()
+	content, ok := s.objectContent(bucketName, objectKey)
+	s.cloud.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("downloading oss://%s/%s to %s: object not found", bucketName, objectKey, filePath)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("downloading oss://%s/%s to %s: %w", bucketName, objectKey, filePath, err)
+	}
+	if progress != nil {
+		progress(int64(len(content)), int64(len(content)))
+	}
+	return nil
+}
+
+// UploadObject stores the content of a local file as an object, replacing any object with the same key
+func (s *OSSService) UploadObject(ctx context.Context, bucketName, objectKey, filePath string, progress service.TransferProgress) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("uploading %s to oss://%s/%s: %w", filePath, bucketName, objectKey, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	if !s.hasBucket(bucketName) {
+		return fmt.Errorf("uploading %s to oss://%s/%s: bucket not found", filePath, bucketName, objectKey)
+	}
+	object := oss.ObjectProperties{
+		Key:          objectKey,
+		Type:         "Normal",
+		Size:         int64(len(data)),
+		ETag:         fmt.Sprintf("\"%X\"", md5.Sum(data)),
+		LastModified: time.Now(),
+		StorageClass: "Standard",
+	}
+	objects := s.cloud.data.Objects[bucketName]
+	replaced := false
+	for i := range objects {
+		if objects[i].Key == objectKey {
+			objects[i], replaced = object, true
+		}
+	}
+	if !replaced {
+		objects = append(objects, object)
+	}
+	if s.cloud.data.Objects == nil {
+		s.cloud.data.Objects = map[string][]oss.ObjectProperties{}
+	}
+	s.cloud.data.Objects[bucketName] = objects
+
+	if s.cloud.data.ObjectContents == nil {
+		s.cloud.data.ObjectContents = map[string]map[string]string{}
+	}
+	if s.cloud.data.ObjectContents[bucketName] == nil {
+		s.cloud.data.ObjectContents[bucketName] = map[string]string{}
+	}
+	s.cloud.data.ObjectContents[bucketName][objectKey] = string(data)
+
+	if progress != nil {
+		progress(object.Size, object.Size)
+	}
+	return nil
+}
+
+// StatObject returns the listing entry of an object, or nil if no object has the key
+func (s *OSSService) StatObject(ctx context.Context, bucketName, objectKey string) (*oss.ObjectProperties, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasBucket(bucketName) {
+		return nil, fmt.Errorf("reading metadata of oss://%s/%s: bucket not found", bucketName, objectKey)
+	}
+	for _, object := range s.cloud.data.Objects[bucketName] {
+		if object.Key == objectKey {
+			return &object, nil
+		}
+	}
+	return nil, nil
+}
+
+// OpenObject returns a reader over the content of an object, or over its first limit bytes
+func (s *OSSService) OpenObject(ctx context.Context, bucketName, objectKey string, limit int64) (io.ReadCloser, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	content, ok := s.objectContent(bucketName, objectKey)
+	if !ok {
+		return nil, fmt.Errorf("reading oss://%s/%s: object not found", bucketName, objectKey)
+	}
+	if limit > 0 && int64(len(content)) > limit {
+		content = content[:limit]
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// SignObjectURL returns a URL in the format of a signed OSS URL, with a fake signature
+func (s *OSSService) SignObjectURL(ctx context.Context, bucketName, objectKey string, expiry time.Duration) (*service.SignedURL, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	for _, bucket := range s.cloud.data.Buckets {
+		if bucket.Name != bucketName {
+			continue
+		}
+		expires := time.Now().Add(expiry)
+		query := url.Values{
+			"Expires":        {fmt.Sprint(expires.Unix())},
+			"OSSAccessKeyId": {"fake-access-key"},
+			"Signature":      {"fake-signature"},
+		}
+		objectURL := url.URL{
+			Scheme:   "https",
+			Host:     fmt.Sprintf("%s.%s.aliyuncs.com", bucketName, bucket.Location),
+			Path:     "/" + objectKey,
+			RawQuery: query.Encode(),
+		}
+		return &service.SignedURL{URL: objectURL.String(), Expires: expires}, nil
+	}
+	return nil, fmt.Errorf("signing URL for oss://%s/%s: bucket not found", bucketName, objectKey)
+}
+
+// FetchObjectVersions returns the fixture versions of an object, newest first, or the
+// current object as its only version
+func (s *OSSService) FetchObjectVersions(ctx context.Context, bucketName, objectKey string) ([]service.ObjectVersion, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasBucket(bucketName) {
+		return nil, fmt.Errorf("listing versions of oss://%s/%s: bucket not found", bucketName, objectKey)
+	}
+	versions := s.objectVersions(bucketName, objectKey)
+	service.SortObjectVersions(versions)
+	return versions, nil
+}
+
+// DownloadObjectVersion writes the content of one version of an object to a local file
+func (s *OSSService) DownloadObjectVersion(ctx context.Context, bucketName, objectKey, versionId, filePath string, progress service.TransferProgress) error {
+	s.cloud.mu.RLock()
+	version, ok := s.objectVersion(bucketName, objectKey, versionId)
+	content := generatedContent(objectKey, version.Size)
+	if version.IsLatest {
+		content, _ = s.objectContent(bucketName, objectKey)
+	}
+	s.cloud.mu.RUnlock()
+	if !ok || version.IsDeleteMarker {
+		return fmt.Errorf("downloading version %s of oss://%s/%s to %s: version not found", versionId, bucketName, objectKey, filePath)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("downloading version %s of oss://%s/%s to %s: %w", versionId, bucketName, objectKey, filePath, err)
+	}
+	if progress != nil {
+		progress(int64(len(content)), int64(len(content)))
+	}
+	return nil
+}
+
+// RestoreObjectVersion copies a version over the object as a new latest version
+func (s *OSSService) RestoreObjectVersion(ctx context.Context, bucketName, objectKey, versionId string) error {
+	s.cloud.mu.Lock()
+	defer s.cloud.mu.Unlock()
+
+	version, ok := s.objectVersion(bucketName, objectKey, versionId)
+	if !ok || version.IsDeleteMarker {
+		return fmt.Errorf("restoring version %s of oss://%s/%s: version not found", versionId, bucketName, objectKey)
+	}
+
+	var versions []service.ObjectVersion
+	for _, v := range s.objectVersions(bucketName, objectKey) {
+		v.IsLatest = false
+		versions = append(versions, v)
+	}
+	restored := version
+	restored.VersionId = s.cloud.newID("CAEQNhiBgMDfake")
+	restored.IsLatest = true
+	restored.LastModified = time.Now()
+	versions = append(versions, restored)
+	if s.cloud.data.ObjectVersions == nil {
+		s.cloud.data.ObjectVersions = map[string][]service.ObjectVersion{}
+	}
+	var others []service.ObjectVersion
+	for _, v := range s.cloud.data.ObjectVersions[bucketName] {
+		if v.Key != objectKey {
+			others = append(others, v)
+		}
+	}
+	s.cloud.data.ObjectVersions[bucketName] = append(others, versions...)
+
+	object := oss.ObjectProperties{
+		Key:          objectKey,
+		Type:         "Normal",
+		Size:         restored.Size,
+		ETag:         restored.ETag,
+		LastModified: restored.LastModified,
+		StorageClass: restored.StorageClass,
+	}
+	objects := s.cloud.data.Objects[bucketName]
+	replaced := false
+	for i := range objects {
+		if objects[i].Key == objectKey {
+			objects[i], replaced = object, true
+		}
+	}
+	if !replaced {
+		objects = append(objects, object)
+	}
+	if s.cloud.data.Objects == nil {
+		s.cloud.data.Objects = map[string][]oss.ObjectProperties{}
+	}
+	s.cloud.data.Objects[bucketName] = objects
+	delete(s.cloud.data.ObjectContents[bucketName], objectKey)
+	return nil
+}
+
+// objectVersions returns the fixture versions of an object, or the current object as its
+// only version. The caller must hold the lock.
+func (s *OSSService) objectVersions(bucketName, objectKey string) []service.ObjectVersion {
+	var versions []service.ObjectVersion
+	for _, v := range s.cloud.data.ObjectVersions[bucketName] {
+		if v.Key == objectKey {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) > 0 {
+		return versions
+	}
+	for _, obj := range s.cloud.data.Objects[bucketName] {
+		if obj.Key == objectKey {
+			return []service.ObjectVersion{{
+				Key:          obj.Key,
+				VersionId:    "null",
+				IsLatest:     true,
+				LastModified: obj.LastModified,
+				Size:         obj.Size,
+				ETag:         obj.ETag,
+				StorageClass: obj.StorageClass,
+			}}
+		}
+	}
+	return nil
+}
+
+// objectVersion returns one version of an object; the caller must hold the lock
+func (s *OSSService) objectVersion(bucketName, objectKey, versionId string) (service.ObjectVersion, bool) {
+	for _, v := range s.objectVersions(bucketName, objectKey) {
+		if v.VersionId == versionId {
+			return v, true
+		}
+	}
+	return service.ObjectVersion{}, false
+}
+
+// objectContent returns the content of an object: the fixture content if there is one,
+// generated lines of text of the object's size otherwise. The caller must hold the lock.
+func (s *OSSService) objectContent(bucketName, objectKey string) (string, bool) {
+	if content, ok := s.cloud.data.ObjectContents[bucketName][objectKey]; ok {
+		return content, true
+	}
+	for _, obj := range s.cloud.data.Objects[bucketName] {
+		if obj.Key == objectKey {
+			return generatedContent(objectKey, obj.Size), true
+		}
+	}
+	return "", false
+}
+
+// generatedContent returns size bytes of numbered lines of text naming the object
+func generatedContent(objectKey string, size int64) string {
+	var b strings.Builder
+	for line := 1; int64(b.Len()) < size; line++ {
+		fmt.Fprintf(&b, "%s line %d\n", objectKey, line)
+	}
+	return b.String()[:size]
+}
+
+// hasBucket reports whether the bucket exists; the caller must hold the lock
+func (s *OSSService) hasBucket(bucketName string) bool {
+	for _, bucket := range s.cloud.data.Buckets {
+		if bucket.Name == bucketName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// RDSService is an in-memory implementation of service.RDS
+type RDSService struct {
+	cloud *Cloud
+}
+
+var _ service.RDS = (*RDSService)(nil)
+
+// NewRDSService creates a fake RDS service backed by the given account
+func NewRDSService(cloud *Cloud) *RDSService {
+	return &RDSService{cloud: cloud}
+}
+
+// FetchInstances returns all RDS instances
+func (s *RDSService) FetchInstances(ctx context.Context) ([]rds.DBInstance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]rds.DBInstance(nil), s.cloud.data.RDSInstances...), nil
+}
+
+// FetchDatabases returns the databases of an RDS instance
+func (s *RDSService) FetchDatabases(ctx context.Context, dbInstanceId string) ([]rds.Database, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing databases for instance %s: instance not found", dbInstanceId)
+	}
+	return append([]rds.Database(nil), s.cloud.data.RDSDatabases[dbInstanceId]...), nil
+}
+
+// FetchAccounts returns the accounts of an RDS instance
+func (s *RDSService) FetchAccounts(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceAccount, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing accounts for instance %s: instance not found", dbInstanceId)
+	}
+	return append([]rds.DBInstanceAccount(nil), s.cloud.data.RDSAccounts[dbInstanceId]...), nil
+}
+
+// FetchNetInfo returns the connection strings of an RDS instance
+func (s *RDSService) FetchNetInfo(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceNetInfo, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing network info for instance %s: instance not found", dbInstanceId)
+	}
+	return append([]rds.DBInstanceNetInfo(nil), s.cloud.data.RDSNetInfo[dbInstanceId]...), nil
+}
+
+// FetchIPWhitelists returns the IP whitelist groups of an RDS instance
+func (s *RDSService) FetchIPWhitelists(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceIPArray, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing IP whitelists for instance %s: instance not found", dbInstanceId)
+	}
+	return append([]rds.DBInstanceIPArray(nil), s.cloud.data.RDSIPWhitelists[dbInstanceId]...), nil
+}
+
+// FetchParameters returns the parameters of an RDS instance, sorted by name
+func (s *RDSService) FetchParameters(ctx context.Context, dbInstanceId string) ([]service.RDSParameter, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing parameters for instance %s: instance not found", dbInstanceId)
+	}
+	parameters := append([]service.RDSParameter(nil), s.cloud.data.RDSParameters[dbInstanceId]...)
+	sort.Slice(parameters, func(i, j int) bool { return parameters[i].Name < parameters[j].Name })
+	return parameters, nil
+}
+
+// FetchBackups returns the backup sets of an RDS instance, newest first
+func (s *RDSService) FetchBackups(ctx context.Context, dbInstanceId string) ([]rds.Backup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing backups for instance %s: instance not found", dbInstanceId)
+	}
+	backups := append([]rds.Backup(nil), s.cloud.data.RDSBackups[dbInstanceId]...)
+	sort.SliceStable(backups, func(i, j int) bool { return backups[i].BackupStartTime > backups[j].BackupStartTime })
+	return backups, nil
+}
+
+// FetchBackupPolicy returns the backup policy of an RDS instance, the default policy if
+// the fixtures have none
+func (s *RDSService) FetchBackupPolicy(ctx context.Context, dbInstanceId string) (*rds.DescribeBackupPolicyResponse, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing backup policy for instance %s: instance not found", dbInstanceId)
+	}
+	policy, ok := s.cloud.data.RDSBackupPolicies[dbInstanceId]
+	if !ok {
+		policy = rds.DescribeBackupPolicyResponse{BackupRetentionPeriod: 7, PreferredBackupPeriod: "Monday,Wednesday,Friday,Sunday", PreferredBackupTime: "18:00Z-19:00Z", BackupLog: "Enable"}
+	}
+	return &policy, nil
+}
+
+// FetchReadOnlyInstances returns the RDS instances replicating from a primary instance
+func (s *RDSService) FetchReadOnlyInstances(ctx context.Context, dbInstanceId string) ([]rds.DBInstance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing instance %s: instance not found", dbInstanceId)
+	}
+	var instances []rds.DBInstance
+	for _, inst := range s.cloud.data.RDSInstances {
+		if inst.MasterInstanceId == dbInstanceId {
+			instances = append(instances, inst)
+		}
+	}
+	return instances, nil
+}
+
+// FetchSlowLogs returns the slow query statistics of an RDS instance, longest total
+// execution time first. The fixture statistics are returned whatever the range.
+func (s *RDSService) FetchSlowLogs(ctx context.Context, dbInstanceId string, start, end time.Time) ([]rds.SQLSlowLog, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(dbInstanceId) {
+		return nil, fmt.Errorf("describing slow logs for instance %s: instance not found", dbInstanceId)
+	}
+	logs := append([]rds.SQLSlowLog(nil), s.cloud.data.RDSSlowLogs[dbInstanceId]...)
+	service.SortSlowLogs(logs)
+	return logs, nil
+}
+
+// hasInstance reports whether the instance exists; the caller must hold the lock
+func (s *RDSService) hasInstance(dbInstanceId string) bool {
+	for _, inst := range s.cloud.data.RDSInstances {
+		if inst.DBInstanceId == dbInstanceId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	r_kvstore "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// RedisService is an in-memory implementation of service.Redis
+type RedisService struct {
+	cloud *Cloud
+}
+
+var _ service.Redis = (*RedisService)(nil)
+
+// NewRedisService creates a fake Redis service backed by the given account
+func NewRedisService(cloud *Cloud) *RedisService {
+	return &RedisService{cloud: cloud}
+}
+
+// FetchInstances returns all Redis instances
+func (s *RedisService) FetchInstances(ctx context.Context) ([]r_kvstore.KVStoreInstance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]r_kvstore.KVStoreInstance(nil), s.cloud.data.RedisInstances...), nil
+}
+
+// FetchAccounts returns the accounts of a Redis instance
+func (s *RedisService) FetchAccounts(ctx context.Context, instanceID string) ([]r_kvstore.Account, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	for _, inst := range s.cloud.data.RedisInstances {
+		if inst.InstanceId == instanceID {
+			return append([]r_kvstore.Account(nil), s.cloud.data.RedisAccounts[instanceID]...), nil
+		}
+	}
+	return nil, fmt.Errorf("fetching redis accounts for instance %s: instance not found", instanceID)
+}
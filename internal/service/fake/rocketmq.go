@@ -0,0 +1,60 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// RocketMQService is an in-memory implementation of service.RocketMQ
+type RocketMQService struct {
+	cloud *Cloud
+}
+
+var _ service.RocketMQ = (*RocketMQService)(nil)
+
+// NewRocketMQService creates a fake RocketMQ service backed by the given account
+func NewRocketMQService(cloud *Cloud) *RocketMQService {
+	return &RocketMQService{cloud: cloud}
+}
+
+// FetchInstances returns all RocketMQ instances
+func (s *RocketMQService) FetchInstances(ctx context.Context) ([]service.RocketMQInstance, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]service.RocketMQInstance(nil), s.cloud.data.RocketMQInstances...), nil
+}
+
+// FetchTopics returns the topics of a RocketMQ instance
+func (s *RocketMQService) FetchTopics(ctx context.Context, instanceId string) ([]service.RocketMQTopic, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(instanceId) {
+		return nil, fmt.Errorf("fetching topics for instance %s: instance not found", instanceId)
+	}
+	return append([]service.RocketMQTopic(nil), s.cloud.data.RocketMQTopics[instanceId]...), nil
+}
+
+// FetchGroups returns the consumer groups of a RocketMQ instance
+func (s *RocketMQService) FetchGroups(ctx context.Context, instanceId string) ([]service.RocketMQGroup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if !s.hasInstance(instanceId) {
+		return nil, fmt.Errorf("fetching groups for instance %s: instance not found", instanceId)
+	}
+	return append([]service.RocketMQGroup(nil), s.cloud.data.RocketMQGroups[instanceId]...), nil
+}
+
+// hasInstance reports whether the instance exists; the caller must hold the lock
+func (s *RocketMQService) hasInstance(instanceId string) bool {
+	for _, inst := range s.cloud.data.RocketMQInstances {
+		if inst.InstanceId == instanceId {
+			return true
+		}
+	}
+	return false
+}
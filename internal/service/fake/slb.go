@@ -0,0 +1,213 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// SLBService is an in-memory implementation of service.SLB
+type SLBService struct {
+	cloud *Cloud
+}
+
+var _ service.SLB = (*SLBService)(nil)
+
+// NewSLBService creates a fake SLB service backed by the given account
+func NewSLBService(cloud *Cloud) *SLBService {
+	return &SLBService{cloud: cloud}
+}
+
+// FetchInstances returns all load balancers
+func (s *SLBService) FetchInstances(ctx context.Context) ([]slb.LoadBalancer, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	return append([]slb.LoadBalancer(nil), s.cloud.data.LoadBalancers...), nil
+}
+
+// FetchListeners returns the load balancer attributes including its listener ports
+func (s *SLBService) FetchListeners(ctx context.Context, loadBalancerId string) (*slb.DescribeLoadBalancerAttributeResponse, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	lb, ok := s.findLoadBalancer(loadBalancerId)
+	if !ok {
+		return nil, fmt.Errorf("describing listeners for SLB %s: load balancer not found", loadBalancerId)
+	}
+
+	response := &slb.DescribeLoadBalancerAttributeResponse{
+		LoadBalancerId:     lb.LoadBalancerId,
+		LoadBalancerName:   lb.LoadBalancerName,
+		LoadBalancerStatus: lb.LoadBalancerStatus,
+		Address:            lb.Address,
+		RegionId:           lb.RegionId,
+	}
+	for _, listener := range s.cloud.data.Listeners[loadBalancerId] {
+		response.ListenerPorts.ListenerPort = append(response.ListenerPorts.ListenerPort, listener.Port)
+	}
+	return response, nil
+}
+
+// FetchDetailedListeners returns the listeners of a load balancer
+func (s *SLBService) FetchDetailedListeners(ctx context.Context, loadBalancerId string) ([]service.ListenerDetail, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if _, ok := s.findLoadBalancer(loadBalancerId); !ok {
+		return nil, fmt.Errorf("describing listeners for SLB %s: load balancer not found", loadBalancerId)
+	}
+	return append([]service.ListenerDetail(nil), s.cloud.data.Listeners[loadBalancerId]...), nil
+}
+
+// FetchListenerAttributes returns the full configuration of a listener
+func (s *SLBService) FetchListenerAttributes(ctx context.Context, loadBalancerId, protocol string, port int) (*service.ListenerAttributes, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	var listener *service.ListenerDetail
+	for i, candidate := range s.cloud.data.Listeners[loadBalancerId] {
+		if strings.EqualFold(candidate.Protocol, protocol) && candidate.Port == port {
+			listener = &s.cloud.data.Listeners[loadBalancerId][i]
+			break
+		}
+	}
+	if listener == nil {
+		return nil, fmt.Errorf("describing listener %s:%d of SLB %s: listener not found", protocol, port, loadBalancerId)
+	}
+
+	var attributes service.ListenerAttributes
+	for _, candidate := range s.cloud.data.ListenerAttributes[loadBalancerId] {
+		if strings.EqualFold(candidate.Protocol, protocol) && candidate.Port == port {
+			attributes = candidate
+			break
+		}
+	}
+	attributes.LoadBalancerId = loadBalancerId
+	attributes.Protocol = listener.Protocol
+	attributes.Port = listener.Port
+	attributes.BackendPort = listener.BackendPort
+	attributes.Status = listener.Status
+	attributes.Scheduler = listener.Scheduler
+	attributes.HealthCheck.Enabled = listener.HealthCheck
+	attributes.VServerGroupId = listener.VServerGroupId
+	attributes.VServerGroupName = listener.VServerGroupName
+	attributes.Rules = slices.Clone(attributes.Rules)
+	attributes.Domains = slices.Clone(attributes.Domains)
+	attributes.Acl.Ids = slices.Clone(attributes.Acl.Ids)
+	return &attributes, nil
+}
+
+// FetchVServerGroups returns the virtual server groups of a load balancer
+func (s *SLBService) FetchVServerGroups(ctx context.Context, loadBalancerId string) ([]slb.VServerGroup, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	if _, ok := s.findLoadBalancer(loadBalancerId); !ok {
+		return nil, fmt.Errorf("describing virtual server groups for SLB %s: load balancer not found", loadBalancerId)
+	}
+	return append([]slb.VServerGroup(nil), s.cloud.data.VServerGroups[loadBalancerId]...), nil
+}
+
+// FetchDetailedVServerGroups returns the virtual server groups with backend counts and listener associations
+func (s *SLBService) FetchDetailedVServerGroups(ctx context.Context, loadBalancerId string) ([]service.VServerGroupDetail, error) {
+	vServerGroups, err := s.FetchVServerGroups(ctx, loadBalancerId)
+	if err != nil {
+		return nil, err
+	}
+	listeners, err := s.FetchDetailedListeners(ctx, loadBalancerId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	var detailedVServerGroups []service.VServerGroupDetail
+	for _, vsg := range vServerGroups {
+		var associatedListeners []string
+		for _, listener := range listeners {
+			if listener.VServerGroupId == vsg.VServerGroupId {
+				associatedListeners = append(associatedListeners, fmt.Sprintf("%s:%d", listener.Protocol, listener.Port))
+			}
+		}
+		detailedVServerGroups = append(detailedVServerGroups, service.VServerGroupDetail{
+			VServerGroupId:      vsg.VServerGroupId,
+			VServerGroupName:    vsg.VServerGroupName,
+			BackendServerCount:  len(s.cloud.data.BackendServers[vsg.VServerGroupId]),
+			AssociatedListeners: associatedListeners,
+		})
+	}
+	return detailedVServerGroups, nil
+}
+
+// FetchVServerGroupBackendServers returns the backend servers of a virtual server group
+func (s *SLBService) FetchVServerGroupBackendServers(ctx context.Context, vServerGroupId string) ([]slb.BackendServerInDescribeVServerGroupAttribute, error) {
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	servers, ok := s.cloud.data.BackendServers[vServerGroupId]
+	if !ok {
+		return nil, fmt.Errorf("describing backend servers for virtual server group %s: group not found", vServerGroupId)
+	}
+	return append([]slb.BackendServerInDescribeVServerGroupAttribute(nil), servers...), nil
+}
+
+// FetchDetailedBackendServers returns the backend servers enriched with ECS details from the
+// same fake account; the ECS client argument is ignored
+func (s *SLBService) FetchDetailedBackendServers(ctx context.Context, vServerGroupId string, _ *ecs.Client) ([]service.BackendServerDetail, error) {
+	backendServers, err := s.FetchVServerGroupBackendServers(ctx, vServerGroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cloud.mu.RLock()
+	defer s.cloud.mu.RUnlock()
+
+	var detailedServers []service.BackendServerDetail
+	for _, server := range backendServers {
+		detail := service.BackendServerDetail{
+			ServerId:         server.ServerId,
+			Port:             server.Port,
+			Weight:           server.Weight,
+			Type:             server.Type,
+			Description:      server.Description,
+			InstanceName:     "N/A",
+			PrivateIpAddress: "N/A",
+			PublicIpAddress:  "N/A",
+		}
+		for _, inst := range s.cloud.data.ECSInstances {
+			if inst.InstanceId != server.ServerId {
+				continue
+			}
+			detail.InstanceName = inst.InstanceName
+			if len(inst.VpcAttributes.PrivateIpAddress.IpAddress) > 0 {
+				detail.PrivateIpAddress = inst.VpcAttributes.PrivateIpAddress.IpAddress[0]
+			}
+			if len(inst.PublicIpAddress.IpAddress) > 0 {
+				detail.PublicIpAddress = inst.PublicIpAddress.IpAddress[0]
+			} else if inst.EipAddress.IpAddress != "" {
+				detail.PublicIpAddress = fmt.Sprintf("EIP: %s", inst.EipAddress.IpAddress)
+			}
+			break
+		}
+		detailedServers = append(detailedServers, detail)
+	}
+	return detailedServers, nil
+}
+
+// findLoadBalancer looks up a load balancer by ID; the caller must hold the lock
+func (s *SLBService) findLoadBalancer(loadBalancerId string) (slb.LoadBalancer, bool) {
+	for _, lb := range s.cloud.data.LoadBalancers {
+		if lb.LoadBalancerId == loadBalancerId {
+			return lb, true
+		}
+	}
+	return slb.LoadBalancer{}, false
+}
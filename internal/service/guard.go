@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is returned by every write operation while read-only mode is on
+var ErrReadOnly = errors.New("read-only mode")
+
+// WriteGuard is the single point every write operation passes before it reaches the API.
+// The Guard* functions wrap a service so that its write methods consult the guard; reads
+// pass through untouched. A nil guard allows every write.
+type WriteGuard struct {
+	reason string // Why writes are refused, empty when they are allowed
+}
+
+// NewWriteGuard creates a guard that refuses every write if readOnly is set. The reason is
+// included in the errors, e.g. "profile 'prod' is read-only".
+func NewWriteGuard(readOnly bool, reason string) *WriteGuard {
+	if readOnly && reason == "" {
+		reason = "writes are disabled"
+	}
+	if !readOnly {
+		reason = ""
+	}
+	return &WriteGuard{reason: reason}
+}
+
+// ReadOnly reports whether write operations are refused
+func (g *WriteGuard) ReadOnly() bool {
+	return g != nil && g.reason != ""
+}
+
+// Reason returns why write operations are refused, or an empty string if they are allowed
+func (g *WriteGuard) Reason() string {
+	if g == nil {
+		return ""
+	}
+	return g.reason
+}
+
+// Check returns an error wrapping ErrReadOnly if the operation, e.g. "stopping ECS
+// instance i-xxx", must not be sent
+func (g *WriteGuard) Check(operation string) error {
+	if !g.ReadOnly() {
+		return nil
+	}
+	return fmt.Errorf("%s refused: %w: %s", operation, ErrReadOnly, g.reason)
+}
+
+// guardedECS passes the write operations of an ECS service through a WriteGuard
+type guardedECS struct {
+	ECS
+	guard *WriteGuard
+}
+
+// GuardECS returns an ECS service whose write operations are checked by guard
+func GuardECS(s ECS, guard *WriteGuard) ECS {
+	return &guardedECS{ECS: s, guard: guard}
+}
+
+func (s *guardedECS) StartInstance(ctx context.Context, instanceId string) error {
+	if err := s.guard.Check("starting ECS instance " + instanceId); err != nil {
+		return err
+	}
+	return s.ECS.StartInstance(ctx, instanceId)
+}
+
+func (s *guardedECS) StopInstance(ctx context.Context, instanceId string, force bool) error {
+	if err := s.guard.Check("stopping ECS instance " + instanceId); err != nil {
+		return err
+	}
+	return s.ECS.StopInstance(ctx, instanceId, force)
+}
+
+func (s *guardedECS) RebootInstance(ctx context.Context, instanceId string) error {
+	if err := s.guard.Check("rebooting ECS instance " + instanceId); err != nil {
+		return err
+	}
+	return s.ECS.RebootInstance(ctx, instanceId)
+}
+
+func (s *guardedECS) ModifyInstanceAttribute(ctx context.Context, instanceId, name, description string) error {
+	if err := s.guard.Check("modifying ECS instance " + instanceId); err != nil {
+		return err
+	}
+	return s.ECS.ModifyInstanceAttribute(ctx, instanceId, name, description)
+}
+
+func (s *guardedECS) AuthorizeSecurityGroupRule(ctx context.Context, securityGroupId string, spec SecurityGroupRuleSpec) error {
+	if err := s.guard.Check("adding rule to security group " + securityGroupId); err != nil {
+		return err
+	}
+	return s.ECS.AuthorizeSecurityGroupRule(ctx, securityGroupId, spec)
+}
+
+func (s *guardedECS) ModifySecurityGroupRule(ctx context.Context, securityGroupId, ruleId string, spec SecurityGroupRuleSpec) error {
+	if err := s.guard.Check(fmt.Sprintf("modifying rule %s of security group %s", ruleId, securityGroupId)); err != nil {
+		return err
+	}
+	return s.ECS.ModifySecurityGroupRule(ctx, securityGroupId, ruleId, spec)
+}
+
+func (s *guardedECS) RevokeSecurityGroupRule(ctx context.Context, securityGroupId, ruleId, direction string) error {
+	if err := s.guard.Check(fmt.Sprintf("revoking rule %s of security group %s", ruleId, securityGroupId)); err != nil {
+		return err
+	}
+	return s.ECS.RevokeSecurityGroupRule(ctx, securityGroupId, ruleId, direction)
+}
+
+// guardedDNS passes the write operations of a DNS service through a WriteGuard
+type guardedDNS struct {
+	DNS
+	guard *WriteGuard
+}
+
+// GuardDNS returns a DNS service whose write operations are checked by guard
+func GuardDNS(s DNS, guard *WriteGuard) DNS {
+	return &guardedDNS{DNS: s, guard: guard}
+}
+
+func (s *guardedDNS) AddDomainRecord(ctx context.Context, domainName string, spec DomainRecordSpec) (string, error) {
+	if err := s.guard.Check("adding DNS record to " + domainName); err != nil {
+		return "", err
+	}
+	return s.DNS.AddDomainRecord(ctx, domainName, spec)
+}
+
+func (s *guardedDNS) UpdateDomainRecord(ctx context.Context, recordId string, spec DomainRecordSpec) error {
+	if err := s.guard.Check("updating DNS record " + recordId); err != nil {
+		return err
+	}
+	return s.DNS.UpdateDomainRecord(ctx, recordId, spec)
+}
+
+func (s *guardedDNS) DeleteDomainRecord(ctx context.Context, recordId string) error {
+	if err := s.guard.Check("deleting DNS record " + recordId); err != nil {
+		return err
+	}
+	return s.DNS.DeleteDomainRecord(ctx, recordId)
+}
+
+func (s *guardedDNS) SetDomainRecordStatus(ctx context.Context, recordId string, enabled bool) error {
+	if err := s.guard.Check("setting status of DNS record " + recordId); err != nil {
+		return err
+	}
+	return s.DNS.SetDomainRecordStatus(ctx, recordId, enabled)
+}
+
+// guardedOSS passes the write operations of an OSS service through a WriteGuard
+type guardedOSS struct {
+	OSS
+	guard *WriteGuard
+}
+
+// GuardOSS returns an OSS service whose write operations are checked by guard
+func GuardOSS(s OSS, guard *WriteGuard) OSS {
+	return &guardedOSS{OSS: s, guard: guard}
+}
+
+func (s *guardedOSS) UploadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error {
+	if err := s.guard.Check(fmt.Sprintf("uploading to oss://%s/%s", bucketName, objectKey)); err != nil {
+		return err
+	}
+	return s.OSS.UploadObject(ctx, bucketName, objectKey, filePath, progress)
+}
+
+func (s *guardedOSS) RestoreObjectVersion(ctx context.Context, bucketName, objectKey, versionId string) error {
+	if err := s.guard.Check(fmt.Sprintf("restoring a version of oss://%s/%s", bucketName, objectKey)); err != nil {
+		return err
+	}
+	return s.OSS.RestoreObjectVersion(ctx, bucketName, objectKey, versionId)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingECS, recordingDNS and recordingOSS count the write operations that reach them;
+// anything else is not implemented
+type recordingECS struct {
+	ECS
+	writes int
+}
+
+func (s *recordingECS) StartInstance(ctx context.Context, instanceId string) error {
+	s.writes++
+	return nil
+}
+
+func (s *recordingECS) RevokeSecurityGroupRule(ctx context.Context, securityGroupId, ruleId, direction string) error {
+	s.writes++
+	return nil
+}
+
+type recordingDNS struct {
+	DNS
+	writes int
+}
+
+func (s *recordingDNS) DeleteDomainRecord(ctx context.Context, recordId string) error {
+	s.writes++
+	return nil
+}
+
+type recordingOSS struct {
+	OSS
+	writes int
+}
+
+func (s *recordingOSS) UploadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error {
+	s.writes++
+	return nil
+}
+
+func (s *recordingOSS) SignObjectURL(ctx context.Context, bucketName, objectKey string, expiry time.Duration) (*SignedURL, error) {
+	return &SignedURL{URL: "https://" + bucketName + ".oss-cn-hangzhou.aliyuncs.com/" + objectKey}, nil
+}
+
+func TestWriteGuard(t *testing.T) {
+	tests := []struct {
+		name   string
+		guard  *WriteGuard
+		reason string // Empty when writes are allowed
+	}{
+		{name: "nil guard"},
+		{name: "writable", guard: NewWriteGuard(false, "ignored")},
+		{name: "read-only", guard: NewWriteGuard(true, "profile 'prod' is read-only"), reason: "profile 'prod' is read-only"},
+		{name: "read-only without a reason", guard: NewWriteGuard(true, ""), reason: "writes are disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.guard.ReadOnly() != (tt.reason != "") || tt.guard.Reason() != tt.reason {
+				t.Fatalf("read-only %v because %q, want %q", tt.guard.ReadOnly(), tt.guard.Reason(), tt.reason)
+			}
+
+			ecsService, dnsService, ossService := &recordingECS{}, &recordingDNS{}, &recordingOSS{}
+			writes := []struct {
+				operation string
+				err       error
+			}{
+				{"starting ECS instance i-web", GuardECS(ecsService, tt.guard).StartInstance(ctx, "i-web")},
+				{"revoking rule sgr-ssh of security group sg-web", GuardECS(ecsService, tt.guard).RevokeSecurityGroupRule(ctx, "sg-web", "sgr-ssh", "ingress")},
+				{"deleting DNS record 1001", GuardDNS(dnsService, tt.guard).DeleteDomainRecord(ctx, "1001")},
+				{"uploading to oss://demo-logs/app.log", GuardOSS(ossService, tt.guard).UploadObject(ctx, "demo-logs", "app.log", "app.log", nil)},
+			}
+			for _, write := range writes {
+				switch {
+				case tt.reason == "" && write.err != nil:
+					t.Errorf("%s: %v", write.operation, write.err)
+				case tt.reason != "" && !errors.Is(write.err, ErrReadOnly):
+					t.Errorf("%s: got %v, want ErrReadOnly", write.operation, write.err)
+				case tt.reason != "" && write.err.Error() != write.operation+" refused: read-only mode: "+tt.reason:
+					t.Errorf("%s: got %q", write.operation, write.err)
+				}
+			}
+
+			want := 0
+			if tt.reason == "" {
+				want = 1
+			}
+			if ecsService.writes != 2*want || dnsService.writes != want || ossService.writes != want {
+				t.Errorf("%d ECS, %d DNS and %d OSS writes sent, want %d, %d and %d",
+					ecsService.writes, dnsService.writes, ossService.writes, 2*want, want, want)
+			}
+
+			// Reads pass through whatever the guard says
+			if _, err := GuardOSS(ossService, tt.guard).SignObjectURL(ctx, "demo-logs", "app.log", time.Hour); err != nil {
+				t.Errorf("signing a URL: %v", err)
+			}
+		})
+	}
+}
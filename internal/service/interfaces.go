@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	r_kvstore "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Every Fetch method takes a context. The Alibaba Cloud SDK cannot abort a request in
+// flight, so implementations check the context between API calls and return its error
+// once it is cancelled.
+
+// ECS is the set of ECS operations used by the application
+type ECS interface {
+	FetchRegions(ctx context.Context) ([]ecs.Region, error)
+	FetchInstances(ctx context.Context) ([]ecs.Instance, error)
+	FetchSecurityGroups(ctx context.Context) ([]ecs.SecurityGroup, error)
+	FetchSecurityGroupRules(ctx context.Context, securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error)
+	FetchInstancesBySecurityGroup(ctx context.Context, securityGroupId string) ([]ecs.Instance, error)
+	FetchSecurityGroupsByInstance(ctx context.Context, instanceId string) ([]ecs.SecurityGroup, error)
+	FetchSecurityGroup(ctx context.Context, securityGroupId string) (*ecs.SecurityGroup, error)
+	FetchInstance(ctx context.Context, instanceId string) (*ecs.Instance, error)
+	StartInstance(ctx context.Context, instanceId string) error
+	StopInstance(ctx context.Context, instanceId string, force bool) error
+	RebootInstance(ctx context.Context, instanceId string) error
+	ModifyInstanceAttribute(ctx context.Context, instanceId, name, description string) error
+	AuthorizeSecurityGroupRule(ctx context.Context, securityGroupId string, spec SecurityGroupRuleSpec) error
+	ModifySecurityGroupRule(ctx context.Context, securityGroupId, ruleId string, spec SecurityGroupRuleSpec) error
+	RevokeSecurityGroupRule(ctx context.Context, securityGroupId, ruleId, direction string) error
+}
+
+// DNS is the set of AliDNS operations used by the application
+type DNS interface {
+	FetchDomains(ctx context.Context) ([]alidns.DomainInDescribeDomains, error)
+	FetchDomainRecords(ctx context.Context, domainName string) ([]alidns.Record, error)
+	AddDomainRecord(ctx context.Context, domainName string, spec DomainRecordSpec) (string, error)
+	UpdateDomainRecord(ctx context.Context, recordId string, spec DomainRecordSpec) error
+	DeleteDomainRecord(ctx context.Context, recordId string) error
+	SetDomainRecordStatus(ctx context.Context, recordId string, enabled bool) error
+}
+
+// SLB is the set of SLB operations used by the application
+type SLB interface {
+	FetchInstances(ctx context.Context) ([]slb.LoadBalancer, error)
+	FetchListeners(ctx context.Context, loadBalancerId string) (*slb.DescribeLoadBalancerAttributeResponse, error)
+	FetchDetailedListeners(ctx context.Context, loadBalancerId string) ([]ListenerDetail, error)
+	FetchListenerAttributes(ctx context.Context, loadBalancerId, protocol string, port int) (*ListenerAttributes, error)
+	FetchVServerGroups(ctx context.Context, loadBalancerId string) ([]slb.VServerGroup, error)
+	FetchDetailedVServerGroups(ctx context.Context, loadBalancerId string) ([]VServerGroupDetail, error)
+	FetchVServerGroupBackendServers(ctx context.Context, vServerGroupId string) ([]slb.BackendServerInDescribeVServerGroupAttribute, error)
+	FetchDetailedBackendServers(ctx context.Context, vServerGroupId string, ecsClient *ecs.Client) ([]BackendServerDetail, error)
+}
+
+// OSS is the set of OSS operations used by the application
+type OSS interface {
+	FetchBuckets(ctx context.Context) ([]oss.BucketProperties, error)
+	FetchObjects(ctx context.Context, bucketName string, marker string, pageSize int) (*ObjectListResult, error)
+	FetchObjectsWithPrefix(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*ObjectListResult, error)
+	FetchFolder(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*ObjectListResult, error)
+	WalkObjects(ctx context.Context, bucketName, prefix string, visit func(objects []oss.ObjectProperties) error) error
+	FetchBucketConfig(ctx context.Context, bucketName string) (*BucketConfig, error)
+	DownloadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error
+	UploadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error
+	StatObject(ctx context.Context, bucketName, objectKey string) (*oss.ObjectProperties, error)
+	OpenObject(ctx context.Context, bucketName, objectKey string, limit int64) (io.ReadCloser, error)
+	SignObjectURL(ctx context.Context, bucketName, objectKey string, expiry time.Duration) (*SignedURL, error)
+	FetchObjectVersions(ctx context.Context, bucketName, objectKey string) ([]ObjectVersion, error)
+	DownloadObjectVersion(ctx context.Context, bucketName, objectKey, versionId, filePath string, progress TransferProgress) error
+	RestoreObjectVersion(ctx context.Context, bucketName, objectKey, versionId string) error
+}
+
+// RDS is the set of RDS operations used by the application
+type RDS interface {
+	FetchInstances(ctx context.Context) ([]rds.DBInstance, error)
+	FetchDatabases(ctx context.Context, dbInstanceId string) ([]rds.Database, error)
+	FetchAccounts(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceAccount, error)
+	FetchNetInfo(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceNetInfo, error)
+	FetchIPWhitelists(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceIPArray, error)
+	FetchParameters(ctx context.Context, dbInstanceId string) ([]RDSParameter, error)
+	FetchBackups(ctx context.Context, dbInstanceId string) ([]rds.Backup, error)
+	FetchBackupPolicy(ctx context.Context, dbInstanceId string) (*rds.DescribeBackupPolicyResponse, error)
+	FetchReadOnlyInstances(ctx context.Context, dbInstanceId string) ([]rds.DBInstance, error)
+	FetchSlowLogs(ctx context.Context, dbInstanceId string, start, end time.Time) ([]rds.SQLSlowLog, error)
+}
+
+// Redis is the set of r-kvstore operations used by the application
+type Redis interface {
+	FetchInstances(ctx context.Context) ([]r_kvstore.KVStoreInstance, error)
+	FetchAccounts(ctx context.Context, instanceID string) ([]r_kvstore.Account, error)
+}
+
+// RocketMQ is the set of RocketMQ operations used by the application
+type RocketMQ interface {
+	FetchInstances(ctx context.Context) ([]RocketMQInstance, error)
+	FetchTopics(ctx context.Context, instanceId string) ([]RocketMQTopic, error)
+	FetchGroups(ctx context.Context, instanceId string) ([]RocketMQGroup, error)
+}
+
+// Compile-time checks that the SDK-backed services satisfy the interfaces
+var (
+	_ ECS      = (*ECSService)(nil)
+	_ DNS      = (*DNSService)(nil)
+	_ SLB      = (*SLBService)(nil)
+	_ OSS      = (*OSSService)(nil)
+	_ RDS      = (*RDSService)(nil)
+	_ Redis    = (*RedisService)(nil)
+	_ RocketMQ = (*RocketMQService)(nil)
+)
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
@@ -10,36 +12,48 @@ import (
 // OSSService handles OSS operations
 type OSSService struct {
 	client          *oss.Client
-	accessKeyID     string
-	accessKeySecret string
+	credentials     oss.CredentialsProvider
 	defaultEndpoint string
+	regionEndpoint  func(regionID string) string // Endpoint of the buckets in a region
+
+	mu        sync.Mutex
+	clients   map[string]*oss.Client // Clients by endpoint, one per region in use
+	locations map[string]string      // Bucket locations by bucket name, e.g. "oss-cn-hangzhou"
 }
 
-// NewOSSService creates a new OSS service
+// NewOSSService creates a new OSS service that accesses every bucket with client
 func NewOSSService(client *oss.Client) *OSSService {
 	return &OSSService{
-		client: client,
+		client:      client,
+		credentials: client.Config.CredentialsProvider,
+		clients:     map[string]*oss.Client{},
+		locations:   map[string]string{},
 	}
 }
 
-// NewOSSServiceWithCredentials creates a new OSS service with credentials for cross-region access
-func NewOSSServiceWithCredentials(client *oss.Client, accessKeyID, accessKeySecret, defaultEndpoint string) *OSSService {
+// NewOSSServiceWithCredentials creates a new OSS service with credentials for cross-region
+// access. client, for defaultEndpoint, lists the buckets; each bucket is accessed through
+// a client for the endpoint regionEndpoint gives for the bucket's region.
+func NewOSSServiceWithCredentials(client *oss.Client, credentials oss.CredentialsProvider, defaultEndpoint string, regionEndpoint func(regionID string) string) *OSSService {
 	return &OSSService{
 		client:          client,
-		accessKeyID:     accessKeyID,
-		accessKeySecret: accessKeySecret,
+		credentials:     credentials,
 		defaultEndpoint: defaultEndpoint,
+		regionEndpoint:  regionEndpoint,
+		clients:         map[string]*oss.Client{defaultEndpoint: client},
+		locations:       map[string]string{},
 	}
 }
 
 // FetchBuckets retrieves all OSS buckets using pagination
-func (s *OSSService) FetchBuckets() ([]oss.BucketProperties, error) {
+func (s *OSSService) FetchBuckets(ctx context.Context) ([]oss.BucketProperties, error) {
 	var allBuckets []oss.BucketProperties
 	marker := ""
 	for {
 		options := []oss.Option{
 			oss.MaxKeys(100),
 			oss.Marker(marker),
+			oss.WithContext(ctx),
 		}
 		result, err := s.client.ListBuckets(options...)
 		if err != nil {
@@ -52,88 +66,76 @@ func (s *OSSService) FetchBuckets() ([]oss.BucketProperties, error) {
 		}
 		marker = result.NextMarker
 	}
+
+	// The locations spare a GetBucketLocation call when the buckets are accessed
+	if s.locations != nil {
+		s.mu.Lock()
+		for _, bucket := range allBuckets {
+			if bucket.Location != "" {
+				s.locations[bucket.Name] = bucket.Location
+			}
+		}
+		s.mu.Unlock()
+	}
 	return allBuckets, nil
 }
 
-// getClientForBucket creates an OSS client for the specific bucket's region
-func (s *OSSService) getClientForBucket(bucketName string) (*oss.Client, error) {
-	// First try with the default client
-	bucket, err := s.client.Bucket(bucketName)
-	if err != nil {
-		return nil, fmt.Errorf("getting bucket %s with default client: %w", bucketName, err)
+// getClientForBucket returns the OSS client for the bucket's region. The region is the
+// Location from ListBuckets, or from GetBucketLocation for a bucket that was not listed.
+func (s *OSSService) getClientForBucket(ctx context.Context, bucketName string) (*oss.Client, error) {
+	if s.credentials == nil || s.regionEndpoint == nil {
+		return s.client, nil
 	}
 
-	// Try to list objects to check if we can access the bucket
-	_, err = bucket.ListObjects(oss.MaxKeys(1))
+	location, err := s.bucketLocation(ctx, bucketName)
 	if err != nil {
-		// If we get an access denied error, try to determine the correct endpoint
-		if strings.Contains(err.Error(), "AccessDenied") && strings.Contains(err.Error(), "endpoint") {
-			// Extract region from bucket location if available
-			// For buckets in different regions, we need to create a new client
-			if s.accessKeyID != "" && s.accessKeySecret != "" {
-				// Try common endpoints based on bucket name patterns or error messages
-				endpoints := s.guessEndpointsFromError(err.Error(), bucketName)
-				for _, endpoint := range endpoints {
-					newClient, clientErr := oss.New(endpoint, s.accessKeyID, s.accessKeySecret)
-					if clientErr != nil {
-						continue
-					}
-
-					// Test if this endpoint works
-					testBucket, bucketErr := newClient.Bucket(bucketName)
-					if bucketErr != nil {
-						continue
-					}
-
-					_, listErr := testBucket.ListObjects(oss.MaxKeys(1))
-					if listErr == nil {
-						return newClient, nil
-					}
-				}
-			}
-		}
-		return nil, fmt.Errorf("accessing bucket %s: %w", bucketName, err)
+		return nil, err
 	}
-
-	return s.client, nil
+	return s.clientForEndpoint(s.regionEndpoint(strings.TrimPrefix(location, "oss-")))
 }
 
-// guessEndpointsFromError tries to extract the correct endpoint from error messages
-func (s *OSSService) guessEndpointsFromError(errorMsg, bucketName string) []string {
-	var endpoints []string
-
-	// Extract endpoint from error message if present
-	if strings.Contains(errorMsg, "oss-cn-beijing") {
-		endpoints = append(endpoints, "oss-cn-beijing.aliyuncs.com")
-	}
-	if strings.Contains(errorMsg, "oss-cn-shanghai") {
-		endpoints = append(endpoints, "oss-cn-shanghai.aliyuncs.com")
-	}
-	if strings.Contains(errorMsg, "oss-cn-hangzhou") {
-		endpoints = append(endpoints, "oss-cn-hangzhou.aliyuncs.com")
-	}
-	if strings.Contains(errorMsg, "oss-cn-shenzhen") {
-		endpoints = append(endpoints, "oss-cn-shenzhen.aliyuncs.com")
+// bucketLocation returns the location of a bucket, e.g. "oss-cn-hangzhou"
+func (s *OSSService) bucketLocation(ctx context.Context, bucketName string) (string, error) {
+	s.mu.Lock()
+	location, ok := s.locations[bucketName]
+	s.mu.Unlock()
+	if ok {
+		return location, nil
 	}
 
-	// If no specific endpoint found in error, try common ones
-	if len(endpoints) == 0 {
-		endpoints = []string{
-			"oss-cn-beijing.aliyuncs.com",
-			"oss-cn-shanghai.aliyuncs.com",
-			"oss-cn-hangzhou.aliyuncs.com",
-			"oss-cn-shenzhen.aliyuncs.com",
-			"oss-us-west-1.aliyuncs.com",
-			"oss-ap-southeast-1.aliyuncs.com",
-		}
+	location, err := s.client.GetBucketLocation(bucketName, oss.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("getting location of bucket %s: %w", bucketName, err)
 	}
+	s.mu.Lock()
+	s.locations[bucketName] = location
+	s.mu.Unlock()
+	return location, nil
+}
 
-	return endpoints
+// clientForEndpoint returns the pooled client for an endpoint, creating it on first use
+func (s *OSSService) clientForEndpoint(endpoint string) (*oss.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if client, ok := s.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := oss.New(endpoint, "", "", oss.SetCredentialsProvider(s.credentials))
+	if err != nil {
+		return nil, fmt.Errorf("creating OSS client for %s: %w", endpoint, err)
+	}
+	s.clients[endpoint] = client
+	return client, nil
 }
 
+// OSSDelimiter separates the folders of object keys
+const OSSDelimiter = "/"
+
 // ObjectListResult holds the result of a paginated object list query
 type ObjectListResult struct {
 	Objects     []oss.ObjectProperties
+	Folders     []string // Common prefixes one level below the listed prefix, e.g. "logs/2024/"
 	NextMarker  string
 	PrevMarker  string
 	IsTruncated bool
@@ -142,20 +144,38 @@ type ObjectListResult struct {
 }
 
 // FetchObjects retrieves objects from a specific bucket with pagination
-func (s *OSSService) FetchObjects(bucketName string, marker string, pageSize int) (*ObjectListResult, error) {
-	// Get the appropriate client for this bucket
-	client, err := s.getClientForBucket(bucketName)
-	if err != nil {
-		return nil, err
-	}
+func (s *OSSService) FetchObjects(ctx context.Context, bucketName string, marker string, pageSize int) (*ObjectListResult, error) {
+	return s.FetchObjectsWithPrefix(ctx, bucketName, "", marker, pageSize)
+}
+
+// FetchObjectsWithPrefix retrieves objects whose keys start with prefix with pagination
+func (s *OSSService) FetchObjectsWithPrefix(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*ObjectListResult, error) {
+	return s.listObjects(ctx, bucketName, prefix, "", marker, pageSize)
+}
+
+// FetchFolder retrieves one level of the key hierarchy under prefix with pagination: the
+// objects directly under it and its subfolders. A page holds up to pageSize of both.
+func (s *OSSService) FetchFolder(ctx context.Context, bucketName string, prefix string, marker string, pageSize int) (*ObjectListResult, error) {
+	return s.listObjects(ctx, bucketName, prefix, OSSDelimiter, marker, pageSize)
+}
 
-	bucket, err := client.Bucket(bucketName)
+// listObjects lists a page of objects under prefix, rolled up into folders at delimiter
+// unless it is empty
+func (s *OSSService) listObjects(ctx context.Context, bucketName, prefix, delimiter, marker string, pageSize int) (*ObjectListResult, error) {
+	bucket, err := s.getBucket(ctx, bucketName)
 	if err != nil {
-		return nil, fmt.Errorf("getting bucket %s: %w", bucketName, err)
+		return nil, err
 	}
 
 	options := []oss.Option{
 		oss.MaxKeys(pageSize),
+		oss.WithContext(ctx),
+	}
+	if prefix != "" {
+		options = append(options, oss.Prefix(prefix))
+	}
+	if delimiter != "" {
+		options = append(options, oss.Delimiter(delimiter))
 	}
 	if marker != "" {
 		options = append(options, oss.Marker(marker))
@@ -168,6 +188,7 @@ func (s *OSSService) FetchObjects(bucketName string, marker string, pageSize int
 
 	return &ObjectListResult{
 		Objects:     result.Objects,
+		Folders:     result.CommonPrefixes,
 		NextMarker:  result.NextMarker,
 		PrevMarker:  marker, // Store the current marker as previous for backward navigation
 		IsTruncated: result.IsTruncated,
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// BucketConfig is the configuration of a bucket, gathered from one API call per section.
+// Sections the bucket has no configuration for are empty. A section that cannot be read,
+// e.g. because the policy denies it, does not fail the others: its error is kept in
+// Errors under the section's name.
+type BucketConfig struct {
+	Info       oss.BucketInfo
+	ACL        string
+	Lifecycle  []oss.LifecycleRule
+	CORS       []oss.CORSRule
+	Versioning string // Enabled or Suspended; empty if versioning was never enabled
+	Encryption *oss.SSEDefaultRule
+	Referer    *oss.GetBucketRefererResult
+	Logging    *oss.LoggingEnabled
+	Website    *oss.GetBucketWebsiteResult
+	Policy     json.RawMessage
+	Errors     map[string]string `json:",omitempty"`
+}
+
+// ossNotConfiguredCodes are the error codes OSS returns for a section a bucket has no
+// configuration for
+var ossNotConfiguredCodes = map[string]bool{
+	"NoSuchLifecycle":                true,
+	"NoSuchCORSConfiguration":        true,
+	"NoSuchWebsiteConfiguration":     true,
+	"NoSuchBucketPolicy":             true,
+	"NoSuchServerSideEncryptionRule": true,
+}
+
+// FetchBucketConfig retrieves the configuration of a bucket. It only fails if the bucket
+// cannot be reached at all or ctx is cancelled.
+func (s *OSSService) FetchBucketConfig(ctx context.Context, bucketName string) (*BucketConfig, error) {
+	client, err := s.getClientForBucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BucketConfig{Errors: map[string]string{}}
+	sections := []struct {
+		name  string
+		fetch func(options ...oss.Option) error
+	}{
+		{"Info", func(options ...oss.Option) error {
+			result, err := client.GetBucketInfo(bucketName, options...)
+			config.Info = result.BucketInfo
+			return err
+		}},
+		{"ACL", func(options ...oss.Option) error {
+			result, err := client.GetBucketACL(bucketName, options...)
+			config.ACL = result.ACL
+			return err
+		}},
+		{"Lifecycle", func(options ...oss.Option) error {
+			result, err := client.GetBucketLifecycle(bucketName, options...)
+			config.Lifecycle = result.Rules
+			return err
+		}},
+		{"CORS", func(options ...oss.Option) error {
+			result, err := client.GetBucketCORS(bucketName, options...)
+			config.CORS = result.CORSRules
+			return err
+		}},
+		{"Versioning", func(options ...oss.Option) error {
+			result, err := client.GetBucketVersioning(bucketName, options...)
+			config.Versioning = result.Status
+			return err
+		}},
+		{"Encryption", func(options ...oss.Option) error {
+			result, err := client.GetBucketEncryption(bucketName, options...)
+			if err == nil {
+				config.Encryption = &result.SSEDefault
+			}
+			return err
+		}},
+		{"Referer", func(options ...oss.Option) error {
+			result, err := client.GetBucketReferer(bucketName, options...)
+			if err == nil {
+				config.Referer = &result
+			}
+			return err
+		}},
+		{"Logging", func(options ...oss.Option) error {
+			result, err := client.GetBucketLogging(bucketName, options...)
+			if err == nil && result.LoggingEnabled.TargetBucket != "" {
+				config.Logging = &result.LoggingEnabled
+			}
+			return err
+		}},
+		{"Website", func(options ...oss.Option) error {
+			result, err := client.GetBucketWebsite(bucketName, options...)
+			if err == nil {
+				config.Website = &result
+			}
+			return err
+		}},
+		{"Policy", func(options ...oss.Option) error {
+			policy, err := client.GetBucketPolicy(bucketName, options...)
+			if err == nil {
+				config.Policy = policyJSON(policy)
+			}
+			return err
+		}},
+	}
+
+	for _, section := range sections {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := section.fetch(oss.WithContext(ctx))
+		var serviceErr oss.ServiceError
+		if err != nil && !(errors.As(err, &serviceErr) && ossNotConfiguredCodes[serviceErr.Code]) {
+			config.Errors[section.name] = err.Error()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// policyJSON keeps a bucket policy as nested JSON so that it is shown like the other
+// sections, or as a JSON string if it does not parse
+func policyJSON(policy string) json.RawMessage {
+	if policy == "" {
+		return nil
+	}
+	if json.Valid([]byte(policy)) {
+		return json.RawMessage(policy)
+	}
+	quoted, _ := json.Marshal(policy)
+	return quoted
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossWalkPageSize is the page size of WalkObjects, the most ListObjects returns at once
+const ossWalkPageSize = 1000
+
+// WalkObjects lists every object whose key starts with prefix, page by page, and calls
+// visit with each page until it returns an error
+func (s *OSSService) WalkObjects(ctx context.Context, bucketName, prefix string, visit func(objects []oss.ObjectProperties) error) error {
+	marker := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := s.listObjects(ctx, bucketName, prefix, "", marker, ossWalkPageSize)
+		if err != nil {
+			return err
+		}
+		if err := visit(result.Objects); err != nil {
+			return err
+		}
+		if !result.IsTruncated || result.NextMarker == "" {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// ObjectFilter selects objects by key, size, modification time and storage class. Zero
+// fields do not filter.
+type ObjectFilter struct {
+	Key            *regexp.Regexp // Matched against the whole key
+	MinSize        int64
+	MaxSize        int64     // Inclusive
+	ModifiedFrom   time.Time // Inclusive
+	ModifiedBefore time.Time // Exclusive
+	StorageClass   string    // Compared case-insensitively
+}
+
+// Match reports whether an object passes the filter
+func (f ObjectFilter) Match(object oss.ObjectProperties) bool {
+	switch {
+	case f.Key != nil && !f.Key.MatchString(object.Key):
+		return false
+	case object.Size < f.MinSize:
+		return false
+	case f.MaxSize > 0 && object.Size > f.MaxSize:
+		return false
+	case !f.ModifiedFrom.IsZero() && object.LastModified.Before(f.ModifiedFrom):
+		return false
+	case !f.ModifiedBefore.IsZero() && !object.LastModified.Before(f.ModifiedBefore):
+		return false
+	case f.StorageClass != "" && !strings.EqualFold(object.StorageClass, f.StorageClass):
+		return false
+	}
+	return true
+}
+
+// ParseKeyPattern compiles a key pattern: a regular expression after "re:", a glob
+// otherwise. Both match the whole key, e.g. "re:.*\.log" but not "re:\.log" matches
+// "app/1.log". In a glob, "*" and "?" do not match "/" but "**" does, and a glob without
+// "/" is matched against the last part of the key, e.g. "*.log" matches "app/1.log".
+func ParseKeyPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(pattern, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()), nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import "testing"
+
+func TestParseKeyPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"*.log", "1.log", true},
+		{"*.log", "app/1.log", true},
+		{"*.log", "app/1.log.gz", false},
+		{"app/*.log", "app/1.log", true},
+		{"app/*.log", "app/2024/1.log", false},
+		{"app/**/*.gz", "app/2024/01/a.gz", true},
+		{"app/**/*.gz", "app/a.gz", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"a+b.txt", "a+b.txt", true},
+		{"a+b.txt", "aab.txt", false},
+		{`re:.*\.log`, "app/1.log", true},
+		{`re:\.log`, "app/1.log", false},
+		{`re:app/\d+\.log`, "app/12.log", true},
+		{`re:app/\d+\.log`, "app/12.log.gz", false},
+		{`re:a|b`, "ab", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.key, func(t *testing.T) {
+			re, err := ParseKeyPattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := re.MatchString(tt.key); got != tt.want {
+				t.Errorf("%s matches %q: %v, want %v", re, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyPatternEmptyAndInvalid(t *testing.T) {
+	if re, err := ParseKeyPattern(""); re != nil || err != nil {
+		t.Errorf("empty pattern: %v, %v, want no filter", re, err)
+	}
+	if _, err := ParseKeyPattern("re:("); err == nil {
+		t.Error("invalid regular expression: no error")
+	}
+}
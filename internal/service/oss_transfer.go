@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossPartSize is the part size of downloads and uploads. Objects larger than one part are
+// transferred in parts by ossTransferRoutines goroutines.
+const (
+	ossPartSize         = 8 << 20
+	ossTransferRoutines = 3
+)
+
+// TransferProgress is called as an object is downloaded or uploaded, with the bytes
+// transferred so far and the total. It is called from the transfer's goroutines.
+type TransferProgress func(transferred, total int64)
+
+// progressListener adapts a TransferProgress to the SDK's progress events
+type progressListener struct {
+	report TransferProgress
+}
+
+func (l progressListener) ProgressChanged(event *oss.ProgressEvent) {
+	if l.report != nil {
+		l.report(event.ConsumedBytes, event.TotalBytes)
+	}
+}
+
+// transferOptions returns the options shared by downloads and uploads
+func transferOptions(ctx context.Context, progress TransferProgress) []oss.Option {
+	return []oss.Option{
+		oss.Routines(ossTransferRoutines),
+		oss.Progress(progressListener{report: progress}),
+		oss.WithContext(ctx),
+	}
+}
+
+// currentCredentials returns the credentials requests are signed with. A provider that
+// fails to refresh them, e.g. when a role can no longer be assumed, reports why instead of
+// handing out empty credentials that only show up as a signature mismatch.
+func (s *OSSService) currentCredentials() (oss.Credentials, error) {
+	if provider, ok := s.credentials.(oss.CredentialsProviderE); ok {
+		credentials, err := provider.GetCredentialsE()
+		if err != nil {
+			return nil, fmt.Errorf("getting OSS credentials: %w", err)
+		}
+		return credentials, nil
+	}
+	return s.credentials.GetCredentials(), nil
+}
+
+// getBucket returns a handle on the bucket, using a client for the bucket's region. The
+// credentials are checked first, so that a transfer does not start without them.
+func (s *OSSService) getBucket(ctx context.Context, bucketName string) (*oss.Bucket, error) {
+	if _, err := s.currentCredentials(); err != nil {
+		return nil, err
+	}
+	client, err := s.getClientForBucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := client.Bucket(bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("getting bucket %s: %w", bucketName, err)
+	}
+	return bucket, nil
+}
+
+// DownloadObject downloads an object to a local file. The download is resumable: when it
+// is interrupted, the parts already downloaded are kept next to the file (in
+// filePath.temp, with the checkpoint in filePath.cp) and the next download of the same
+// object to the same path continues from there.
+func (s *OSSService) DownloadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error {
+	bucket, err := s.getBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	options := append(transferOptions(ctx, progress), oss.Checkpoint(true, filePath+".cp"))
+	if err := bucket.DownloadFile(objectKey, filePath, ossPartSize, options...); err != nil {
+		return fmt.Errorf("downloading oss://%s/%s to %s: %w", bucketName, objectKey, filePath, err)
+	}
+	return nil
+}
+
+// UploadObject uploads a local file as an object, replacing any object with the same key.
+// Large files are uploaded in parts; an interrupted upload of the same file to the same key
+// resumes from a checkpoint kept in the temporary directory.
+func (s *OSSService) UploadObject(ctx context.Context, bucketName, objectKey, filePath string, progress TransferProgress) error {
+	bucket, err := s.getBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	checkpointDir := filepath.Join(os.TempDir(), "tali-oss-checkpoints")
+	if err := os.MkdirAll(checkpointDir, 0o700); err != nil {
+		return fmt.Errorf("creating checkpoint directory: %w", err)
+	}
+	options := append(transferOptions(ctx, progress), oss.CheckpointDir(true, checkpointDir))
+	if err := bucket.UploadFile(objectKey, filePath, ossPartSize, options...); err != nil {
+		return fmt.Errorf("uploading %s to oss://%s/%s: %w", filePath, bucketName, objectKey, err)
+	}
+	return nil
+}
+
+// StatObject returns the size, last modification time and ETag of an object, or nil if no
+// object has the key. Callers check it before an upload to tell whether it replaces an
+// object, whatever part of the bucket they have listed.
+func (s *OSSService) StatObject(ctx context.Context, bucketName, objectKey string) (*oss.ObjectProperties, error) {
+	bucket, err := s.getBucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := bucket.GetObjectMeta(objectKey, oss.WithContext(ctx))
+	if err != nil {
+		var serviceErr oss.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading metadata of oss://%s/%s: %w", bucketName, objectKey, err)
+	}
+	object := &oss.ObjectProperties{Key: objectKey, ETag: header.Get("ETag")}
+	object.Size, _ = strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	object.LastModified, _ = http.ParseTime(header.Get("Last-Modified"))
+	return object, nil
+}
+
+// OpenObject opens the content of an object for streaming. With a positive limit only the
+// first limit bytes are requested. The caller must close the reader.
+func (s *OSSService) OpenObject(ctx context.Context, bucketName, objectKey string, limit int64) (io.ReadCloser, error) {
+	bucket, err := s.getBucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []oss.Option{oss.WithContext(ctx)}
+	if limit > 0 {
+		options = append(options, oss.Range(0, limit-1))
+	}
+	body, err := bucket.GetObject(objectKey, options...)
+	if err != nil {
+		return nil, fmt.Errorf("reading oss://%s/%s: %w", bucketName, objectKey, err)
+	}
+	return body, nil
+}
+
+// SignedURL is a presigned URL and how long it works
+type SignedURL struct {
+	URL     string
+	Expires time.Time
+	// Temporary is set when the URL was signed with temporary credentials, such as those
+	// of an assumed role or an ECS instance role: it stops working when they expire, which
+	// may be before Expires
+	Temporary bool
+}
+
+// SignObjectURL returns a URL that allows anyone holding it to GET the object until it
+// expires. The URL is signed locally with the service's credentials, for the public HTTPS
+// endpoint of the bucket's region whatever endpoint the service uses, so that it works
+// outside Alibaba Cloud.
+func (s *OSSService) SignObjectURL(ctx context.Context, bucketName, objectKey string, expiry time.Duration) (*SignedURL, error) {
+	credentials, err := s.currentCredentials()
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.bucketLocation(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientForEndpoint("https://" + location + ".aliyuncs.com")
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := client.Bucket(bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("getting bucket %s: %w", bucketName, err)
+	}
+
+	signed := &SignedURL{Expires: time.Now().Add(expiry)}
+	signed.URL, err = bucket.SignURL(objectKey, oss.HTTPGet, int64(expiry/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("signing URL for oss://%s/%s: %w", bucketName, objectKey, err)
+	}
+	signed.Temporary = credentials.GetSecurityToken() != ""
+	return signed, nil
+}
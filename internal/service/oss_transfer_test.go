@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// expiredCredentials is a provider whose credentials can no longer be refreshed
+type expiredCredentials struct{}
+
+var errRefresh = errors.New("refreshing credentials: AssumeRole: NoPermission")
+
+func (expiredCredentials) GetCredentials() oss.Credentials { return nil }
+
+func (expiredCredentials) GetCredentialsE() (oss.Credentials, error) { return nil, errRefresh }
+
+func TestOSSCredentialsRefreshError(t *testing.T) {
+	// No client is set: the credentials are checked before any request is made
+	s := &OSSService{credentials: expiredCredentials{}}
+	ctx := context.Background()
+	calls := map[string]error{
+		"download": s.DownloadObject(ctx, "demo-logs", "app.log", t.TempDir()+"/app.log", nil),
+		"upload":   s.UploadObject(ctx, "demo-logs", "app.log", "app.log", nil),
+	}
+	_, calls["sign"] = s.SignObjectURL(ctx, "demo-logs", "app.log", time.Hour)
+	_, calls["stat"] = s.StatObject(ctx, "demo-logs", "app.log")
+	for name, err := range calls {
+		if !errors.Is(err, errRefresh) {
+			t.Errorf("%s: got %v, want the refresh error", name, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossStorageClassOrder is the order storage classes are shown in, from the most expensive
+// to store; classes not listed come after them in alphabetical order
+var ossStorageClassOrder = []string{"Standard", "IA", "Archive", "ColdArchive", "DeepColdArchive"}
+
+// ObjectUsage is the number and total size of a set of objects
+type ObjectUsage struct {
+	Objects int64
+	Bytes   int64
+}
+
+// PrefixUsage is the usage of the objects under a prefix, in total and by storage class
+type PrefixUsage struct {
+	Prefix string // A folder ending with "/", or the prefix of the BucketUsage for the objects directly under it
+	ObjectUsage
+	ByClass map[string]ObjectUsage
+}
+
+func (u *PrefixUsage) add(object oss.ObjectProperties) {
+	u.Objects++
+	u.Bytes += object.Size
+	class := u.ByClass[object.StorageClass]
+	class.Objects++
+	class.Bytes += object.Size
+	u.ByClass[object.StorageClass] = class
+}
+
+// BucketUsage adds up the objects under a prefix like du: for each folder one level below
+// the prefix, and by storage class
+type BucketUsage struct {
+	Prefix  string
+	Total   PrefixUsage
+	folders map[string]*PrefixUsage
+}
+
+// NewBucketUsage creates an empty usage summary of the objects under prefix
+func NewBucketUsage(prefix string) *BucketUsage {
+	return &BucketUsage{
+		Prefix:  prefix,
+		Total:   PrefixUsage{Prefix: prefix, ByClass: map[string]ObjectUsage{}},
+		folders: map[string]*PrefixUsage{},
+	}
+}
+
+// Add counts objects listed under the prefix
+func (u *BucketUsage) Add(objects []oss.ObjectProperties) {
+	for _, object := range objects {
+		folder := u.Prefix
+		if n := strings.Index(object.Key[len(u.Prefix):], OSSDelimiter); n >= 0 {
+			folder = object.Key[:len(u.Prefix)+n+len(OSSDelimiter)]
+		}
+		usage, ok := u.folders[folder]
+		if !ok {
+			usage = &PrefixUsage{Prefix: folder, ByClass: map[string]ObjectUsage{}}
+			u.folders[folder] = usage
+		}
+		usage.add(object)
+		u.Total.add(object)
+	}
+}
+
+// Folders returns the usage of each folder, largest first
+func (u *BucketUsage) Folders() []*PrefixUsage {
+	folders := make([]*PrefixUsage, 0, len(u.folders))
+	for _, usage := range u.folders {
+		folders = append(folders, usage)
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		if folders[i].Bytes != folders[j].Bytes {
+			return folders[i].Bytes > folders[j].Bytes
+		}
+		return folders[i].Prefix < folders[j].Prefix
+	})
+	return folders
+}
+
+// StorageClasses returns the storage classes of the objects counted so far
+func (u *BucketUsage) StorageClasses() []string {
+	var classes []string
+	for class := range u.Total.ByClass {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		a, b := slices.Index(ossStorageClassOrder, classes[i]), slices.Index(ossStorageClassOrder, classes[j])
+		switch {
+		case a >= 0 && b >= 0:
+			return a < b
+		case a >= 0 || b >= 0:
+			return a >= 0
+		}
+		return classes[i] < classes[j]
+	})
+	return classes
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func TestBucketUsageAdd(t *testing.T) {
+	object := func(key string, size int64, class string) oss.ObjectProperties {
+		return oss.ObjectProperties{Key: key, Size: size, StorageClass: class}
+	}
+	tests := []struct {
+		name    string
+		prefix  string
+		pages   [][]oss.ObjectProperties
+		total   ObjectUsage
+		folders []PrefixUsage // Largest first
+		classes []string
+	}{
+		{
+			name:   "folders of the bucket",
+			prefix: "",
+			pages: [][]oss.ObjectProperties{
+				{object("index.html", 10, "Standard"), object("logs/1.log", 100, "IA")},
+				{object("logs/2024/2.log", 200, "Archive"), object("img/a.png", 50, "Standard")},
+			},
+			total: ObjectUsage{Objects: 4, Bytes: 360},
+			folders: []PrefixUsage{
+				{Prefix: "logs/", ObjectUsage: ObjectUsage{2, 300}, ByClass: map[string]ObjectUsage{"IA": {1, 100}, "Archive": {1, 200}}},
+				{Prefix: "img/", ObjectUsage: ObjectUsage{1, 50}, ByClass: map[string]ObjectUsage{"Standard": {1, 50}}},
+				{Prefix: "", ObjectUsage: ObjectUsage{1, 10}, ByClass: map[string]ObjectUsage{"Standard": {1, 10}}},
+			},
+			classes: []string{"Standard", "IA", "Archive"},
+		},
+		{
+			name:   "folders under a prefix",
+			prefix: "logs/",
+			pages: [][]oss.ObjectProperties{
+				{object("logs/1.log", 100, "Standard"), object("logs/a/2.log", 100, "Standard"), object("logs/b/3.log", 100, "Custom")},
+			},
+			total: ObjectUsage{Objects: 3, Bytes: 300},
+			folders: []PrefixUsage{
+				{Prefix: "logs/", ObjectUsage: ObjectUsage{1, 100}, ByClass: map[string]ObjectUsage{"Standard": {1, 100}}},
+				{Prefix: "logs/a/", ObjectUsage: ObjectUsage{1, 100}, ByClass: map[string]ObjectUsage{"Standard": {1, 100}}},
+				{Prefix: "logs/b/", ObjectUsage: ObjectUsage{1, 100}, ByClass: map[string]ObjectUsage{"Custom": {1, 100}}},
+			},
+			classes: []string{"Standard", "Custom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := NewBucketUsage(tt.prefix)
+			for _, page := range tt.pages {
+				usage.Add(page)
+			}
+			if usage.Total.ObjectUsage != tt.total {
+				t.Errorf("total %+v, want %+v", usage.Total.ObjectUsage, tt.total)
+			}
+			var folders []PrefixUsage
+			for _, folder := range usage.Folders() {
+				folders = append(folders, *folder)
+			}
+			if !reflect.DeepEqual(folders, tt.folders) {
+				t.Errorf("folders\n%+v\nwant\n%+v", folders, tt.folders)
+			}
+			if classes := usage.StorageClasses(); !reflect.DeepEqual(classes, tt.classes) {
+				t.Errorf("storage classes %v, want %v", classes, tt.classes)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -18,12 +19,15 @@ func NewRDSService(client *rds.Client) *RDSService {
 }
 
 // FetchInstances retrieves all RDS instances using pagination
-func (s *RDSService) FetchInstances() ([]rds.DBInstance, error) {
+func (s *RDSService) FetchInstances(ctx context.Context) ([]rds.DBInstance, error) {
 	var allInstances []rds.DBInstance
 	pageNumber := 1
 	pageSize := 100 // 使用最大页面大小以减少请求次数
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := rds.CreateDescribeDBInstancesRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(pageNumber)
@@ -55,7 +59,7 @@ func (s *RDSService) FetchInstances() ([]rds.DBInstance, error) {
 }
 
 // FetchDatabases retrieves all databases for a specific RDS instance
-func (s *RDSService) FetchDatabases(dbInstanceId string) ([]rds.Database, error) {
+func (s *RDSService) FetchDatabases(ctx context.Context, dbInstanceId string) ([]rds.Database, error) {
 	request := rds.CreateDescribeDatabasesRequest()
 	request.Scheme = "https"
 	request.DBInstanceId = dbInstanceId
@@ -69,7 +73,7 @@ func (s *RDSService) FetchDatabases(dbInstanceId string) ([]rds.Database, error)
 }
 
 // FetchAccounts retrieves all accounts for a specific RDS instance
-func (s *RDSService) FetchAccounts(dbInstanceId string) ([]rds.DBInstanceAccount, error) {
+func (s *RDSService) FetchAccounts(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceAccount, error) {
 	request := rds.CreateDescribeAccountsRequest()
 	request.Scheme = "https"
 	request.DBInstanceId = dbInstanceId
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+)
+
+// rdsDetailPageSize is the page size of the paginated RDS detail calls, the most they return at once
+const rdsDetailPageSize = 100
+
+// RDSParameter is a parameter of an RDS instance: the value it runs with and, if it was
+// changed since, the value applied at the next restart
+type RDSParameter struct {
+	Name         string
+	Value        string
+	PendingValue string `json:",omitempty"` // Empty when the running value is current
+	DefaultValue string `json:",omitempty"`
+	ValueRange   string `json:",omitempty"`
+	Description  string
+}
+
+// Modified reports whether the parameter differs from its default value, when known
+func (p RDSParameter) Modified() bool {
+	return p.DefaultValue != "" && p.Value != p.DefaultValue
+}
+
+// FetchNetInfo retrieves the connection strings, ports and addresses of an RDS instance
+func (s *RDSService) FetchNetInfo(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceNetInfo, error) {
+	request := rds.CreateDescribeDBInstanceNetInfoRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = dbInstanceId
+
+	response, err := s.client.DescribeDBInstanceNetInfo(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing network info for instance %s: %w", dbInstanceId, err)
+	}
+
+	return response.DBInstanceNetInfos.DBInstanceNetInfo, nil
+}
+
+// FetchIPWhitelists retrieves the IP whitelist groups of an RDS instance
+func (s *RDSService) FetchIPWhitelists(ctx context.Context, dbInstanceId string) ([]rds.DBInstanceIPArray, error) {
+	request := rds.CreateDescribeDBInstanceIPArrayListRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = dbInstanceId
+
+	response, err := s.client.DescribeDBInstanceIPArrayList(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing IP whitelists for instance %s: %w", dbInstanceId, err)
+	}
+
+	return response.Items.DBInstanceIPArray, nil
+}
+
+// FetchParameters retrieves the parameters of an RDS instance, sorted by name
+func (s *RDSService) FetchParameters(ctx context.Context, dbInstanceId string) ([]RDSParameter, error) {
+	request := rds.CreateDescribeParametersRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = dbInstanceId
+
+	response, err := s.client.DescribeParameters(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing parameters for instance %s: %w", dbInstanceId, err)
+	}
+
+	return MergeRDSParameters(response.RunningParameters.DBInstanceParameter, response.ConfigParameters.DBInstanceParameter), nil
+}
+
+// MergeRDSParameters combines the running parameters of an instance with the configured
+// ones not applied yet, sorted by name
+func MergeRDSParameters(running, configured []rds.DBInstanceParameter) []RDSParameter {
+	pending := make(map[string]string, len(configured))
+	for _, p := range configured {
+		pending[p.ParameterName] = p.ParameterValue
+	}
+
+	parameters := make([]RDSParameter, 0, len(running))
+	for _, p := range running {
+		parameter := RDSParameter{
+			Name:         p.ParameterName,
+			Value:        p.ParameterValue,
+			DefaultValue: p.ParameterDefaultValue,
+			ValueRange:   p.ParameterValueRange,
+			Description:  p.ParameterDescription,
+		}
+		if value, ok := pending[p.ParameterName]; ok && value != p.ParameterValue {
+			parameter.PendingValue = value
+		}
+		parameters = append(parameters, parameter)
+	}
+	sort.Slice(parameters, func(i, j int) bool { return parameters[i].Name < parameters[j].Name })
+	return parameters
+}
+
+// FetchBackups retrieves every backup set of an RDS instance still retained, newest first
+func (s *RDSService) FetchBackups(ctx context.Context, dbInstanceId string) ([]rds.Backup, error) {
+	var backups []rds.Backup
+	for pageNumber := 1; ; pageNumber++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		request := rds.CreateDescribeBackupsRequest()
+		request.Scheme = "https"
+		request.DBInstanceId = dbInstanceId
+		request.PageNumber = requests.NewInteger(pageNumber)
+		request.PageSize = requests.NewInteger(rdsDetailPageSize)
+
+		response, err := s.client.DescribeBackups(request)
+		if err != nil {
+			return nil, fmt.Errorf("describing backups for instance %s (page %d): %w", dbInstanceId, pageNumber, err)
+		}
+
+		backups = append(backups, response.Items.Backup...)
+		if len(response.Items.Backup) < rdsDetailPageSize {
+			break
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool { return backups[i].BackupStartTime > backups[j].BackupStartTime })
+	return backups, nil
+}
+
+// FetchBackupPolicy retrieves the backup policy of an RDS instance
+func (s *RDSService) FetchBackupPolicy(ctx context.Context, dbInstanceId string) (*rds.DescribeBackupPolicyResponse, error) {
+	request := rds.CreateDescribeBackupPolicyRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = dbInstanceId
+
+	response, err := s.client.DescribeBackupPolicy(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing backup policy for instance %s: %w", dbInstanceId, err)
+	}
+
+	return response, nil
+}
+
+// FetchReadOnlyInstances retrieves the read-only instances replicating from a primary
+// RDS instance
+func (s *RDSService) FetchReadOnlyInstances(ctx context.Context, dbInstanceId string) ([]rds.DBInstance, error) {
+	request := rds.CreateDescribeDBInstancesRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = dbInstanceId
+
+	response, err := s.client.DescribeDBInstances(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing instance %s: %w", dbInstanceId, err)
+	}
+	if len(response.Items.DBInstance) == 0 {
+		return nil, fmt.Errorf("describing instance %s: instance not found", dbInstanceId)
+	}
+
+	var readOnlyIds []string
+	for _, id := range response.Items.DBInstance[0].ReadOnlyDBInstanceIds.ReadOnlyDBInstanceId {
+		readOnlyIds = append(readOnlyIds, id.DBInstanceId)
+	}
+	if len(readOnlyIds) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	request = rds.CreateDescribeDBInstancesRequest()
+	request.Scheme = "https"
+	request.DBInstanceId = strings.Join(readOnlyIds, ",")
+	request.PageSize = requests.NewInteger(rdsDetailPageSize)
+
+	response, err = s.client.DescribeDBInstances(request)
+	if err != nil {
+		return nil, fmt.Errorf("describing read-only instances of %s: %w", dbInstanceId, err)
+	}
+
+	return response.Items.DBInstance, nil
+}
+
+// FetchSlowLogs retrieves the daily slow query statistics of an RDS instance between two
+// days, one entry per SQL template and day, the longest total execution time first
+func (s *RDSService) FetchSlowLogs(ctx context.Context, dbInstanceId string, start, end time.Time) ([]rds.SQLSlowLog, error) {
+	var logs []rds.SQLSlowLog
+	for pageNumber := 1; ; pageNumber++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		request := rds.CreateDescribeSlowLogsRequest()
+		request.Scheme = "https"
+		request.DBInstanceId = dbInstanceId
+		request.StartTime = start.UTC().Format("2006-01-02Z")
+		request.EndTime = end.UTC().Format("2006-01-02Z")
+		request.PageNumber = requests.NewInteger(pageNumber)
+		request.PageSize = requests.NewInteger(rdsDetailPageSize)
+
+		response, err := s.client.DescribeSlowLogs(request)
+		if err != nil {
+			return nil, fmt.Errorf("describing slow logs for instance %s (page %d): %w", dbInstanceId, pageNumber, err)
+		}
+
+		logs = append(logs, response.Items.SQLSlowLog...)
+		if len(response.Items.SQLSlowLog) < rdsDetailPageSize || len(logs) >= response.TotalRecordCount {
+			break
+		}
+	}
+
+	SortSlowLogs(logs)
+	return logs, nil
+}
+
+// SlowLogExecutions returns the number of executions of a slow query, for any engine
+func SlowLogExecutions(log rds.SQLSlowLog) int64 {
+	return max(log.MySQLTotalExecutionCounts, log.SQLServerTotalExecutionCounts)
+}
+
+// SlowLogTotalTime returns the total execution time of a slow query in seconds, for any engine
+func SlowLogTotalTime(log rds.SQLSlowLog) int64 {
+	return max(log.MySQLTotalExecutionTimes, log.SQLServerTotalExecutionTimes)
+}
+
+// SortSlowLogs orders slow query statistics by total execution time, longest first
+func SortSlowLogs(logs []rds.SQLSlowLog) {
+	sort.SliceStable(logs, func(i, j int) bool { return SlowLogTotalTime(logs[i]) > SlowLogTotalTime(logs[j]) })
+}
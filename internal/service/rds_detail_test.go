@@ -0,0 +1,61 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+)
+
+func TestMergeRDSParameters(t *testing.T) {
+	parameter := func(name, value string) rds.DBInstanceParameter {
+		return rds.DBInstanceParameter{ParameterName: name, ParameterValue: value}
+	}
+	tests := []struct {
+		name       string
+		running    []rds.DBInstanceParameter
+		configured []rds.DBInstanceParameter
+		want       []RDSParameter
+	}{
+		{
+			name:    "sorted by name",
+			running: []rds.DBInstanceParameter{parameter("max_connections", "2000"), parameter("innodb_lock_wait_timeout", "50")},
+			want: []RDSParameter{
+				{Name: "innodb_lock_wait_timeout", Value: "50"},
+				{Name: "max_connections", Value: "2000"},
+			},
+		},
+		{
+			name:       "configured value not applied yet",
+			running:    []rds.DBInstanceParameter{parameter("max_connections", "2000"), parameter("wait_timeout", "28800")},
+			configured: []rds.DBInstanceParameter{parameter("max_connections", "4000"), parameter("wait_timeout", "28800")},
+			want: []RDSParameter{
+				{Name: "max_connections", Value: "2000", PendingValue: "4000"},
+				{Name: "wait_timeout", Value: "28800"},
+			},
+		},
+		{
+			name:       "configured only",
+			running:    []rds.DBInstanceParameter{parameter("wait_timeout", "28800")},
+			configured: []rds.DBInstanceParameter{parameter("max_connections", "4000")},
+			want:       []RDSParameter{{Name: "wait_timeout", Value: "28800"}},
+		},
+		{
+			name:    "details kept",
+			running: []rds.DBInstanceParameter{{ParameterName: "wait_timeout", ParameterValue: "600", ParameterDefaultValue: "28800", ParameterValueRange: "[1-31536000]", ParameterDescription: "Idle timeout"}},
+			want:    []RDSParameter{{Name: "wait_timeout", Value: "600", DefaultValue: "28800", ValueRange: "[1-31536000]", Description: "Idle timeout"}},
+		},
+		{
+			name: "none",
+			want: []RDSParameter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeRDSParameters(tt.running, tt.configured)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
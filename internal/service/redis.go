@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	r_kvstore "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
@@ -17,7 +18,7 @@ func NewRedisService(client *r_kvstore.Client) *RedisService {
 }
 
 // FetchInstances fetches all Redis instances
-func (s *RedisService) FetchInstances() ([]r_kvstore.KVStoreInstance, error) {
+func (s *RedisService) FetchInstances(ctx context.Context) ([]r_kvstore.KVStoreInstance, error) {
 	request := r_kvstore.CreateDescribeInstancesRequest()
 	request.Scheme = "https"
 	// Set PageSize to a large number to fetch all instances in one go,
@@ -33,7 +34,7 @@ func (s *RedisService) FetchInstances() ([]r_kvstore.KVStoreInstance, error) {
 }
 
 // FetchAccounts fetches all accounts for a specific Redis instance
-func (s *RedisService) FetchAccounts(instanceID string) ([]r_kvstore.Account, error) {
+func (s *RedisService) FetchAccounts(ctx context.Context, instanceID string) ([]r_kvstore.Account, error) {
 	request := r_kvstore.CreateDescribeAccountsRequest()
 	request.Scheme = "https"
 	request.InstanceId = instanceID
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	ons20190214 "github.com/alibabacloud-go/ons-20190214/v3/client"
@@ -53,7 +54,7 @@ type RocketMQGroup struct {
 }
 
 // FetchInstances retrieves all RocketMQ instances
-func (s *RocketMQService) FetchInstances() ([]RocketMQInstance, error) {
+func (s *RocketMQService) FetchInstances(ctx context.Context) ([]RocketMQInstance, error) {
 	request := &ons20190214.OnsInstanceInServiceListRequest{}
 
 	response, err := s.client.OnsInstanceInServiceList(request)
@@ -83,7 +84,7 @@ func (s *RocketMQService) FetchInstances() ([]RocketMQInstance, error) {
 }
 
 // FetchTopics retrieves all topics for a specific RocketMQ instance
-func (s *RocketMQService) FetchTopics(instanceId string) ([]RocketMQTopic, error) {
+func (s *RocketMQService) FetchTopics(ctx context.Context, instanceId string) ([]RocketMQTopic, error) {
 	request := &ons20190214.OnsTopicListRequest{
 		InstanceId: tea.String(instanceId),
 	}
@@ -114,7 +115,7 @@ func (s *RocketMQService) FetchTopics(instanceId string) ([]RocketMQTopic, error
 }
 
 // FetchGroups retrieves all consumer groups for a specific RocketMQ instance
-func (s *RocketMQService) FetchGroups(instanceId string) ([]RocketMQGroup, error) {
+func (s *RocketMQService) FetchGroups(ctx context.Context, instanceId string) ([]RocketMQGroup, error) {
 	request := &ons20190214.OnsGroupListRequest{
 		InstanceId: tea.String(instanceId),
 	}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+// Severity of a security group finding, see SeverityRank
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// SeverityRank orders severities, the most severe first
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Kinds of security group findings
+const (
+	FindingExposedPort   = "exposed-port"    // A sensitive port is open to the internet
+	FindingWidePortRange = "wide-port-range" // A rule allows more ports than needed
+	FindingUnusedGroup   = "unused-group"    // No instance uses the group
+	FindingDuplicateRule = "duplicate-rule"  // The same rule exists twice
+	FindingShadowedRule  = "shadowed-rule"   // Another rule always decides first
+)
+
+// SensitivePorts lists the ports of services that should never be reachable from the
+// internet, with the service usually behind them
+var SensitivePorts = map[int]string{
+	21:    "FTP",
+	22:    "SSH",
+	23:    "Telnet",
+	445:   "SMB",
+	1433:  "SQL Server",
+	2375:  "Docker",
+	3306:  "MySQL",
+	3389:  "RDP",
+	5432:  "PostgreSQL",
+	5601:  "Kibana",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	9300:  "Elasticsearch",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// WidePortRange is the number of ports above which an ingress rule's range is flagged
+const WidePortRange = 1000
+
+// SecurityGroupFinding is a problem found in a security group, or in one of its rules
+type SecurityGroupFinding struct {
+	Severity          string   `json:"severity"`
+	Kind              string   `json:"kind"`
+	SecurityGroupId   string   `json:"security_group_id"`
+	SecurityGroupName string   `json:"security_group_name"`
+	RuleId            string   `json:"rule_id,omitempty"` // Empty for findings about the group
+	Rule              string   `json:"rule,omitempty"`    // The rule in words, see DescribeRule
+	Detail            string   `json:"detail"`
+	InstanceIds       []string `json:"instance_ids"` // The instances the group applies to
+}
+
+// SecurityGroupAudit is everything the audit of one security group looks at
+type SecurityGroupAudit struct {
+	Group       ecs.SecurityGroup
+	Rules       []ecs.Permission
+	InstanceIds []string
+}
+
+// maxAuditFetchers bounds the security groups whose rules and instances are fetched at the
+// same time by AuditSecurityGroups, like the regions of a regional list
+const maxAuditFetchers = 8
+
+// AuditSecurityGroups fetches every security group with its rules and instances and
+// audits them, see AuditSecurityGroup. The groups are fetched concurrently; the first
+// error, in the order of the groups, fails the audit. The findings are sorted by severity.
+func AuditSecurityGroups(ctx context.Context, ecsService ECS) ([]SecurityGroupFinding, error) {
+	groups, err := ecsService.FetchSecurityGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]SecurityGroupAudit, len(groups))
+	errs := make([]error, len(groups))
+	semaphore := make(chan struct{}, maxAuditFetchers)
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group ecs.SecurityGroup) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if errs[i] = ctx.Err(); errs[i] != nil {
+				return
+			}
+			audits[i], errs[i] = fetchSecurityGroupAudit(ctx, ecsService, group)
+		}(i, group)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var findings []SecurityGroupFinding
+	for i, audit := range audits {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		findings = append(findings, AuditSecurityGroup(audit)...)
+	}
+
+	SortSecurityGroupFindings(findings, FindingSortSeverity, false)
+	return findings, nil
+}
+
+// fetchSecurityGroupAudit fetches the rules and instances of a security group
+func fetchSecurityGroupAudit(ctx context.Context, ecsService ECS, group ecs.SecurityGroup) (SecurityGroupAudit, error) {
+	rules, err := ecsService.FetchSecurityGroupRules(ctx, group.SecurityGroupId)
+	if err != nil {
+		return SecurityGroupAudit{}, err
+	}
+	instances, err := ecsService.FetchInstancesBySecurityGroup(ctx, group.SecurityGroupId)
+	if err != nil {
+		return SecurityGroupAudit{}, err
+	}
+
+	audit := SecurityGroupAudit{Group: group, Rules: rules.Permissions.Permission, InstanceIds: []string{}}
+	for _, instance := range instances {
+		audit.InstanceIds = append(audit.InstanceIds, instance.InstanceId)
+	}
+	return audit, nil
+}
+
+// AuditSecurityGroup looks for the problems of one security group: sensitive ports open to
+// the internet, overly wide port ranges, no instance using the group, and rules that are
+// duplicated or shadowed by another rule
+func AuditSecurityGroup(audit SecurityGroupAudit) []SecurityGroupFinding {
+	group := audit.Group
+	finding := func(severity, kind string, rule *ecs.Permission, detail string) SecurityGroupFinding {
+		f := SecurityGroupFinding{
+			Severity:          severity,
+			Kind:              kind,
+			SecurityGroupId:   group.SecurityGroupId,
+			SecurityGroupName: group.SecurityGroupName,
+			Detail:            detail,
+			InstanceIds:       audit.InstanceIds,
+		}
+		if rule != nil {
+			f.RuleId = rule.SecurityGroupRuleId
+			f.Rule = DescribeRule(SecurityGroupRuleSpecOf(*rule))
+		}
+		return f
+	}
+
+	var findings []SecurityGroupFinding
+	if len(audit.InstanceIds) == 0 {
+		findings = append(findings, finding(SeverityLow, FindingUnusedGroup, nil, "No instance uses this security group"))
+	}
+
+	specs := make([]SecurityGroupRuleSpec, len(audit.Rules))
+	for i, rule := range audit.Rules {
+		specs[i] = SecurityGroupRuleSpecOf(rule)
+	}
+	for i := range audit.Rules {
+		rule, spec := &audit.Rules[i], specs[i]
+		if spec.Direction != SecurityGroupRuleIngress || spec.Policy != "accept" {
+			continue
+		}
+		open := OpenToAnyAddress(spec.Peer)
+		if exposed := exposedServices(spec); open && len(exposed) > 0 {
+			findings = append(findings, finding(SeverityHigh, FindingExposedPort, rule,
+				"Open to the internet: "+strings.Join(exposed, ", ")))
+		}
+		if ports := portCount(spec); ports > WidePortRange {
+			severity, reach := SeverityLow, ""
+			if open {
+				severity, reach = SeverityMedium, " from the internet"
+			}
+			findings = append(findings, finding(severity, FindingWidePortRange, rule,
+				fmt.Sprintf("Allows %d ports%s", ports, reach)))
+		}
+	}
+
+	for i := range audit.Rules {
+		for j := range audit.Rules {
+			if i == j || !covers(specs[j], specs[i]) {
+				continue
+			}
+			if sameRule(specs[i], specs[j]) {
+				if j < i { // Report each pair once, on the later rule
+					findings = append(findings, finding(SeverityLow, FindingDuplicateRule, &audit.Rules[i],
+						"Same as rule "+ruleName(audit.Rules[j], j)))
+					break
+				}
+				continue
+			}
+			if decidesFirst(specs[j], specs[i]) {
+				findings = append(findings, finding(SeverityMedium, FindingShadowedRule, &audit.Rules[i],
+					fmt.Sprintf("Never applies: rule %s (%s) matches the same traffic first", ruleName(audit.Rules[j], j), DescribeRule(specs[j]))))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// DescribeRule names a rule in findings and confirmations, e.g. "ingress TCP 22/22 from
+// 203.0.113.7/32, accept, priority 1"
+func DescribeRule(spec SecurityGroupRuleSpec) string {
+	peer := "from " + spec.Peer
+	if spec.Direction == SecurityGroupRuleEgress {
+		peer = "to " + spec.Peer
+	}
+	return fmt.Sprintf("%s %s %s %s, %s, priority %d", spec.Direction, spec.IpProtocol, spec.PortRange, peer, spec.Policy, spec.Priority)
+}
+
+// ruleName refers to a rule by its ID, or by its position for rules without one
+func ruleName(rule ecs.Permission, index int) string {
+	if rule.SecurityGroupRuleId != "" {
+		return rule.SecurityGroupRuleId
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// OpenToAnyAddress reports whether a peer is every IPv4 or IPv6 address
+func OpenToAnyAddress(peer string) bool {
+	return peer == "0.0.0.0/0" || peer == "::/0"
+}
+
+// exposedServices returns the sensitive ports a rule allows, e.g. "SSH (22)"
+func exposedServices(spec SecurityGroupRuleSpec) []string {
+	var exposed []string
+	for _, port := range slices.Sorted(maps.Keys(SensitivePorts)) {
+		if allowsPort(spec, port) {
+			exposed = append(exposed, fmt.Sprintf("%s (%d)", SensitivePorts[port], port))
+		}
+	}
+	return exposed
+}
+
+// allowsPort reports whether a rule matches TCP or UDP traffic to a port
+func allowsPort(spec SecurityGroupRuleSpec, port int) bool {
+	switch spec.IpProtocol {
+	case "ALL":
+		return true
+	case "TCP", "UDP":
+		from, to, ok := parsePortRange(spec.PortRange)
+		return ok && from <= port && port <= to
+	}
+	return false
+}
+
+// portCount returns the number of ports a rule matches: every port for ALL, none for
+// protocols without ports
+func portCount(spec SecurityGroupRuleSpec) int {
+	switch spec.IpProtocol {
+	case "ALL":
+		return 65535
+	case "TCP", "UDP":
+		if from, to, ok := parsePortRange(spec.PortRange); ok {
+			return to - from + 1
+		}
+	}
+	return 0
+}
+
+// parsePortRange parses a "from/to" port range; "-1/-1" is every port
+func parsePortRange(portRange string) (from, to int, ok bool) {
+	if portRange == AllPorts {
+		return 1, 65535, true
+	}
+	fromText, toText, found := strings.Cut(portRange, "/")
+	if !found {
+		return 0, 0, false
+	}
+	from, errFrom := strconv.Atoi(fromText)
+	to, errTo := strconv.Atoi(toText)
+	return from, to, errFrom == nil && errTo == nil
+}
+
+// covers reports whether every packet matched by inner is also matched by outer
+func covers(outer, inner SecurityGroupRuleSpec) bool {
+	if outer.Direction != inner.Direction {
+		return false
+	}
+	if outer.IpProtocol != "ALL" && outer.IpProtocol != inner.IpProtocol {
+		return false
+	}
+	if inner.HasPorts() && outer.HasPorts() {
+		outerFrom, outerTo, ok1 := parsePortRange(outer.PortRange)
+		innerFrom, innerTo, ok2 := parsePortRange(inner.PortRange)
+		if !ok1 || !ok2 || innerFrom < outerFrom || innerTo > outerTo {
+			return false
+		}
+	}
+	return peerCovers(outer.Peer, inner.Peer)
+}
+
+// peerCovers reports whether the addresses of the outer peer include those of the inner
+// one. Security groups only cover themselves.
+func peerCovers(outer, inner string) bool {
+	if outer == inner {
+		return true
+	}
+	_, outerNet, err := net.ParseCIDR(outer)
+	if err != nil {
+		return false
+	}
+	innerIP, innerNet, err := net.ParseCIDR(inner)
+	if err != nil {
+		return false
+	}
+	outerOnes, outerBits := outerNet.Mask.Size()
+	innerOnes, innerBits := innerNet.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outerNet.Contains(innerIP)
+}
+
+// sameRule reports whether two rules match the same traffic with the same outcome
+func sameRule(a, b SecurityGroupRuleSpec) bool {
+	a.Description, b.Description = "", ""
+	return a == b
+}
+
+// decidesFirst reports whether rule a is evaluated before rule b: a lower priority number
+// goes first, and at equal priority a drop rule goes before an accept rule. An accept rule
+// covering another accept rule of equal priority makes it redundant as well.
+func decidesFirst(a, b SecurityGroupRuleSpec) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.Policy == "drop" || a.Policy == b.Policy
+}
+
+// Columns the findings can be sorted by
+const (
+	FindingSortSeverity  = "severity"
+	FindingSortKind      = "kind"
+	FindingSortGroup     = "group"
+	FindingSortInstances = "instances"
+)
+
+// FindingSortKeys lists the columns the findings can be sorted by, in the order the
+// TUI cycles through them
+var FindingSortKeys = []string{FindingSortSeverity, FindingSortKind, FindingSortGroup, FindingSortInstances}
+
+// SortSecurityGroupFindings orders findings by a column, see FindingSortKeys. Severity
+// sorts the most severe first and instances the most instances first; reverse turns the
+// order around. Ties keep the severity order, then the group.
+func SortSecurityGroupFindings(findings []SecurityGroupFinding, by string, reverse bool) {
+	compare := func(a, b SecurityGroupFinding) int {
+		switch by {
+		case FindingSortKind:
+			return strings.Compare(a.Kind, b.Kind)
+		case FindingSortGroup:
+			return strings.Compare(a.SecurityGroupId, b.SecurityGroupId)
+		case FindingSortInstances:
+			return len(b.InstanceIds) - len(a.InstanceIds)
+		}
+		return SeverityRank(a.Severity) - SeverityRank(b.Severity)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if c := compare(a, b); c != 0 {
+			return (c < 0) != reverse
+		}
+		if c := SeverityRank(a.Severity) - SeverityRank(b.Severity); c != 0 {
+			return c < 0
+		}
+		return a.SecurityGroupId < b.SecurityGroupId
+	})
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+// ingressRule returns an ingress TCP rule as DescribeSecurityGroupAttribute reports it
+func ingressRule(id, portRange, source, policy, priority string) ecs.Permission {
+	return ecs.Permission{
+		SecurityGroupRuleId: id,
+		Direction:           "ingress",
+		IpProtocol:          "TCP",
+		PortRange:           portRange,
+		SourceCidrIp:        source,
+		Policy:              policy,
+		Priority:            priority,
+	}
+}
+
+func TestAuditSecurityGroup(t *testing.T) {
+	// finding is the part of a finding the tests compare
+	type finding struct {
+		Severity, Kind, RuleId, Detail string
+	}
+	tests := []struct {
+		name  string
+		audit SecurityGroupAudit
+		want  []finding
+	}{
+		{
+			name: "no problem",
+			audit: SecurityGroupAudit{
+				Rules: []ecs.Permission{
+					ingressRule("sgr-https", "443/443", "0.0.0.0/0", "Accept", "1"),
+					ingressRule("sgr-ssh", "22/22", "10.0.0.0/8", "Accept", "1"),
+					ingressRule("sgr-notelnet", "23/23", "0.0.0.0/0", "Drop", "1"),
+					{SecurityGroupRuleId: "sgr-out", Direction: "egress", IpProtocol: "ALL", PortRange: "-1/-1", DestCidrIp: "0.0.0.0/0", Policy: "Accept", Priority: "100"},
+				},
+				InstanceIds: []string{"i-web"},
+			},
+		},
+		{
+			name:  "unused",
+			audit: SecurityGroupAudit{InstanceIds: []string{}},
+			want:  []finding{{SeverityLow, FindingUnusedGroup, "", "No instance uses this security group"}},
+		},
+		{
+			name: "exposed, duplicated and shadowed rules",
+			audit: SecurityGroupAudit{
+				Rules: []ecs.Permission{
+					ingressRule("sgr-rdp", "3389/3389", "0.0.0.0/0", "Accept", "1"),
+					ingressRule("sgr-rdp2", "3389/3389", "0.0.0.0/0", "Accept", "1"),
+					ingressRule("sgr-internal", "1/65535", "10.0.0.0/8", "Accept", "1"),
+					ingressRule("sgr-jump", "22/22", "10.1.0.0/16", "Accept", "10"),
+				},
+				InstanceIds: []string{"i-legacy"},
+			},
+			want: []finding{
+				{SeverityHigh, FindingExposedPort, "sgr-rdp", "Open to the internet: RDP (3389)"},
+				{SeverityHigh, FindingExposedPort, "sgr-rdp2", "Open to the internet: RDP (3389)"},
+				{SeverityLow, FindingWidePortRange, "sgr-internal", "Allows 65535 ports"},
+				{SeverityLow, FindingDuplicateRule, "sgr-rdp2", "Same as rule sgr-rdp"},
+				{SeverityMedium, FindingShadowedRule, "sgr-jump", "Never applies: rule sgr-internal (ingress TCP 1/65535 from 10.0.0.0/8, accept, priority 1) matches the same traffic first"},
+			},
+		},
+		{
+			name: "wide range open to the internet",
+			audit: SecurityGroupAudit{
+				Rules:       []ecs.Permission{ingressRule("sgr-high", "30000/40000", "::/0", "Accept", "1")},
+				InstanceIds: []string{"i-web"},
+			},
+			want: []finding{{SeverityMedium, FindingWidePortRange, "sgr-high", "Allows 10001 ports from the internet"}},
+		},
+		{
+			name: "rule without an ID shadowed by a drop rule",
+			audit: SecurityGroupAudit{
+				Rules: []ecs.Permission{
+					ingressRule("", "80/80", "0.0.0.0/0", "Drop", "1"),
+					ingressRule("sgr-http", "80/80", "203.0.113.0/24", "Accept", "1"),
+				},
+				InstanceIds: []string{"i-web"},
+			},
+			want: []finding{
+				{SeverityMedium, FindingShadowedRule, "sgr-http", "Never applies: rule #1 (ingress TCP 80/80 from 0.0.0.0/0, drop, priority 1) matches the same traffic first"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []finding
+			for _, f := range AuditSecurityGroup(tt.audit) {
+				got = append(got, finding{f.Severity, f.Kind, f.RuleId, f.Detail})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// auditECS serves the lookups of AuditSecurityGroups from memory and records how many
+// groups are fetched at the same time; the rules of a group missing from rules cannot be
+// described
+type auditECS struct {
+	ECS
+	groups []ecs.SecurityGroup
+	rules  map[string][]ecs.Permission
+
+	mu            sync.Mutex
+	active, maxed int
+	release       chan struct{} // Closed once the fetches may return
+}
+
+func (s *auditECS) FetchSecurityGroups(ctx context.Context) ([]ecs.SecurityGroup, error) {
+	return s.groups, nil
+}
+
+func (s *auditECS) FetchSecurityGroupRules(ctx context.Context, securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+	s.mu.Lock()
+	s.active++
+	s.maxed = max(s.maxed, s.active)
+	s.mu.Unlock()
+	<-s.release
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+
+	rules, ok := s.rules[securityGroupId]
+	if !ok {
+		return nil, fmt.Errorf("describing security group rules for %s: Throttling", securityGroupId)
+	}
+	response := &ecs.DescribeSecurityGroupAttributeResponse{SecurityGroupId: securityGroupId}
+	response.Permissions.Permission = rules
+	return response, nil
+}
+
+func (s *auditECS) FetchInstancesBySecurityGroup(ctx context.Context, securityGroupId string) ([]ecs.Instance, error) {
+	return []ecs.Instance{{InstanceId: "i-" + securityGroupId}}, nil
+}
+
+func TestAuditSecurityGroups(t *testing.T) {
+	newECS := func() *auditECS {
+		s := &auditECS{rules: map[string][]ecs.Permission{}, release: make(chan struct{})}
+		for i := range 2 * maxAuditFetchers {
+			id := fmt.Sprintf("sg-%02d", i)
+			s.groups = append(s.groups, ecs.SecurityGroup{SecurityGroupId: id})
+			s.rules[id] = []ecs.Permission{ingressRule("sgr-rdp-"+id, "3389/3389", "0.0.0.0/0", "Accept", "1")}
+		}
+		return s
+	}
+
+	ecsService := newECS()
+	go func() {
+		// Let the fetches pile up to the bound, if they ever get there, before any returns
+		defer close(ecsService.release)
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			ecsService.mu.Lock()
+			active := ecsService.active
+			ecsService.mu.Unlock()
+			if active == maxAuditFetchers {
+				return
+			}
+		}
+	}()
+	findings, err := AuditSecurityGroups(context.Background(), ecsService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != len(ecsService.groups) {
+		t.Errorf("%d findings, want one per group", len(findings))
+	}
+	if ecsService.maxed != maxAuditFetchers {
+		t.Errorf("%d groups fetched at the same time, want %d", ecsService.maxed, maxAuditFetchers)
+	}
+
+	ecsService = newECS()
+	close(ecsService.release)
+	delete(ecsService.rules, "sg-03")
+	delete(ecsService.rules, "sg-12")
+	if _, err := AuditSecurityGroups(context.Background(), ecsService); err == nil || !strings.Contains(err.Error(), "sg-03") {
+		t.Errorf("got %v, want the error of sg-03", err)
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SelfPeer stands for the group itself as the peer of a rule, so rules authorizing their
+// own group compare equal across groups and are cloned to refer to the target group
+const SelfPeer = "self"
+
+// SecurityGroupRef names a security group, possibly in another profile or region than
+// the one in use: "sg-xxx", "cn-beijing/sg-xxx", "prod:sg-xxx" or "prod:cn-beijing/sg-xxx".
+// An empty Profile or Region means the one in use.
+type SecurityGroupRef struct {
+	Profile         string `json:"profile,omitempty"`
+	Region          string `json:"region,omitempty"`
+	SecurityGroupId string `json:"security_group_id"`
+}
+
+// ParseSecurityGroupRef parses a security group reference, see SecurityGroupRef
+func ParseSecurityGroupRef(text string) (SecurityGroupRef, error) {
+	var ref SecurityGroupRef
+	rest := text
+	if profile, after, found := strings.Cut(rest, ":"); found {
+		ref.Profile, rest = profile, after
+	}
+	if region, after, found := strings.Cut(rest, "/"); found {
+		ref.Region, rest = region, after
+	}
+	ref.SecurityGroupId = rest
+	if !strings.HasPrefix(ref.SecurityGroupId, "sg-") {
+		return ref, fmt.Errorf("%q is not a security group: expected [profile:][region/]sg-id", text)
+	}
+	return ref, nil
+}
+
+// String formats the reference as ParseSecurityGroupRef reads it
+func (r SecurityGroupRef) String() string {
+	text := r.SecurityGroupId
+	if r.Region != "" {
+		text = r.Region + "/" + text
+	}
+	if r.Profile != "" {
+		text = r.Profile + ":" + text
+	}
+	return text
+}
+
+// Resolve fills in the profile and region of a reference that names none, so references
+// compare equal when they name the same account and region
+func (r SecurityGroupRef) Resolve(profile, region string) SecurityGroupRef {
+	if r.Profile == "" {
+		r.Profile = profile
+	}
+	if r.Region == "" {
+		r.Region = region
+	}
+	return r
+}
+
+// SecurityGroupRule is a rule of a security group with its ID, see SecurityGroupRuleSet
+type SecurityGroupRule struct {
+	RuleId string                `json:"rule_id"`
+	Spec   SecurityGroupRuleSpec `json:"spec"`
+}
+
+// SecurityGroupRuleSet holds the rules of a security group, normalized for comparison:
+// the group itself as a peer is SelfPeer, and the rules are sorted by the traffic they
+// match
+type SecurityGroupRuleSet struct {
+	Ref   SecurityGroupRef    `json:"ref"`
+	Name  string              `json:"name"`
+	Rules []SecurityGroupRule `json:"rules"`
+}
+
+// FetchSecurityGroupRuleSet fetches the rules of a security group as a normalized set
+func FetchSecurityGroupRuleSet(ctx context.Context, ecsService ECS, ref SecurityGroupRef) (*SecurityGroupRuleSet, error) {
+	response, err := ecsService.FetchSecurityGroupRules(ctx, ref.SecurityGroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &SecurityGroupRuleSet{Ref: ref, Name: response.SecurityGroupName, Rules: []SecurityGroupRule{}}
+	for _, rule := range response.Permissions.Permission {
+		spec := SecurityGroupRuleSpecOf(rule)
+		if spec.Peer == ref.SecurityGroupId {
+			spec.Peer = SelfPeer
+		}
+		set.Rules = append(set.Rules, SecurityGroupRule{RuleId: rule.SecurityGroupRuleId, Spec: spec})
+	}
+	slices.SortStableFunc(set.Rules, func(a, b SecurityGroupRule) int { return compareRules(a.Spec, b.Spec) })
+	return set, nil
+}
+
+// compareRules orders rules by the traffic they match: ingress first, then by protocol,
+// ports and peer, and by priority for rules matching the same traffic
+func compareRules(a, b SecurityGroupRuleSpec) int {
+	if a.Direction != b.Direction {
+		return strings.Compare(b.Direction, a.Direction) // "ingress" before "egress"
+	}
+	if c := strings.Compare(a.IpProtocol, b.IpProtocol); c != 0 {
+		return c
+	}
+	aFrom, aTo, _ := parsePortRange(a.PortRange)
+	bFrom, bTo, _ := parsePortRange(b.PortRange)
+	if aFrom != bFrom {
+		return aFrom - bFrom
+	}
+	if aTo != bTo {
+		return aTo - bTo
+	}
+	if c := strings.Compare(a.Peer, b.Peer); c != 0 {
+		return c
+	}
+	return a.Priority - b.Priority
+}
+
+// ruleTraffic identifies the traffic a rule matches. Rules matching the same traffic are
+// paired up by a diff and compared on their policy, priority and description.
+func ruleTraffic(spec SecurityGroupRuleSpec) string {
+	return strings.Join([]string{spec.Direction, spec.IpProtocol, spec.PortRange, spec.Peer}, " ")
+}
+
+// How the rules of two security groups differ
+const (
+	RuleDiffSame      = "same"
+	RuleDiffChanged   = "changed"    // Same traffic, different policy, priority or description
+	RuleDiffLeftOnly  = "left-only"  // Only the left group has the rule
+	RuleDiffRightOnly = "right-only" // Only the right group has the rule
+)
+
+// SecurityGroupRuleDiff is a line of the diff of two security groups: a rule of either
+// group, or a rule of each matching the same traffic
+type SecurityGroupRuleDiff struct {
+	Kind  string             `json:"kind"` // One of the RuleDiff constants
+	Left  *SecurityGroupRule `json:"left,omitempty"`
+	Right *SecurityGroupRule `json:"right,omitempty"`
+}
+
+// Spec returns the rule of the line, from the left group if it has one
+func (d SecurityGroupRuleDiff) Spec() SecurityGroupRuleSpec {
+	if d.Left != nil {
+		return d.Left.Spec
+	}
+	return d.Right.Spec
+}
+
+// DescribeRuleDecision describes what a rule of a diff does with the traffic it matches,
+// e.g. `accept, priority 1, "office"`; empty for the side of a diff without the rule
+func DescribeRuleDecision(rule *SecurityGroupRule) string {
+	if rule == nil {
+		return ""
+	}
+	text := fmt.Sprintf("%s, priority %d", rule.Spec.Policy, rule.Spec.Priority)
+	if rule.Spec.Description != "" {
+		text += fmt.Sprintf(", %q", rule.Spec.Description)
+	}
+	return text
+}
+
+// CountRuleDiffs counts the lines of a diff by kind
+func CountRuleDiffs(diff []SecurityGroupRuleDiff) map[string]int {
+	counts := make(map[string]int)
+	for _, line := range diff {
+		counts[line.Kind]++
+	}
+	return counts
+}
+
+// DiffSecurityGroupRules compares the rules of two security groups. Rules are paired up
+// by the traffic they match, in the order of the normalized sets, so the diff lists the
+// rules of both groups sorted the same way.
+func DiffSecurityGroupRules(left, right *SecurityGroupRuleSet) []SecurityGroupRuleDiff {
+	unpaired := make(map[string][]int) // Traffic -> indexes of the right rules not paired yet
+	for i, rule := range right.Rules {
+		traffic := ruleTraffic(rule.Spec)
+		unpaired[traffic] = append(unpaired[traffic], i)
+	}
+
+	var diff []SecurityGroupRuleDiff
+	paired := make([]bool, len(right.Rules))
+	for i := range left.Rules {
+		leftRule := &left.Rules[i]
+		traffic := ruleTraffic(leftRule.Spec)
+		candidates := unpaired[traffic]
+		if len(candidates) == 0 {
+			diff = append(diff, SecurityGroupRuleDiff{Kind: RuleDiffLeftOnly, Left: leftRule})
+			continue
+		}
+		// Prefer an identical rule, then one differing only in its description, then the
+		// first one matching the same traffic
+		pick := slices.IndexFunc(candidates, func(j int) bool { return right.Rules[j].Spec == leftRule.Spec })
+		if pick < 0 {
+			pick = max(slices.IndexFunc(candidates, func(j int) bool { return sameRule(right.Rules[j].Spec, leftRule.Spec) }), 0)
+		}
+		j := candidates[pick]
+		unpaired[traffic] = slices.Delete(candidates, pick, pick+1)
+		paired[j] = true
+
+		kind := RuleDiffChanged
+		if leftRule.Spec == right.Rules[j].Spec {
+			kind = RuleDiffSame
+		}
+		diff = append(diff, SecurityGroupRuleDiff{Kind: kind, Left: leftRule, Right: &right.Rules[j]})
+	}
+	for j := range right.Rules {
+		if !paired[j] {
+			diff = append(diff, SecurityGroupRuleDiff{Kind: RuleDiffRightOnly, Right: &right.Rules[j]})
+		}
+	}
+
+	slices.SortStableFunc(diff, func(a, b SecurityGroupRuleDiff) int { return compareRules(a.Spec(), b.Spec()) })
+	return diff
+}
+
+// What cloning does with a rule of the source group
+const (
+	CloneAdd    = "add"    // The target lacks the rule
+	CloneModify = "modify" // The target has a rule for the same traffic that differs
+	CloneSkip   = "skip"   // The rule cannot be cloned, see CloneStep.Reason
+)
+
+// CloneStep is a change cloning makes to the target group
+type CloneStep struct {
+	Action string                `json:"action"`            // CloneAdd, CloneModify or CloneSkip
+	RuleId string                `json:"rule_id,omitempty"` // The target rule a modification changes
+	Spec   SecurityGroupRuleSpec `json:"spec"`              // The rule as the target gets it
+	Reason string                `json:"reason,omitempty"`  // Why a rule is skipped
+}
+
+// SecurityGroupClonePlan lists the changes that make the target group have every rule of
+// the source group. Rules only the target has are kept.
+type SecurityGroupClonePlan struct {
+	Source SecurityGroupRef `json:"source"`
+	Target SecurityGroupRef `json:"target"`
+	Steps  []CloneStep      `json:"steps"`
+}
+
+// Changes returns the number of rules the plan adds or modifies
+func (p *SecurityGroupClonePlan) Changes() int {
+	changes := 0
+	for _, step := range p.Steps {
+		if step.Action != CloneSkip {
+			changes++
+		}
+	}
+	return changes
+}
+
+// PlanSecurityGroupClone plans cloning the rules of the source group into the target
+// group. A rule referring to another security group or to a prefix list is skipped
+// unless both groups are in the same profile and region, where it refers to the same
+// one, so both references should be resolved, see SecurityGroupRef.Resolve.
+func PlanSecurityGroupClone(source, target *SecurityGroupRuleSet) *SecurityGroupClonePlan {
+	plan := &SecurityGroupClonePlan{Source: source.Ref, Target: target.Ref, Steps: []CloneStep{}}
+	sameAccount := source.Ref.Profile == target.Ref.Profile && source.Ref.Region == target.Ref.Region
+
+	// The rules of the target once the plan is applied, normalized like the rule sets:
+	// adding one that differs from them only in its description would fail
+	planned := make([]SecurityGroupRuleSpec, 0, len(target.Rules))
+	for _, rule := range target.Rules {
+		planned = append(planned, rule.Spec)
+	}
+
+	for _, line := range DiffSecurityGroupRules(source, target) {
+		step := CloneStep{}
+		switch line.Kind {
+		case RuleDiffLeftOnly:
+			step.Action = CloneAdd
+		case RuleDiffChanged:
+			step.Action, step.RuleId = CloneModify, line.Right.RuleId
+		default:
+			continue
+		}
+		step.Spec = line.Left.Spec
+		self := step.Spec.Peer == SelfPeer
+		if self {
+			step.Spec.Peer = target.Ref.SecurityGroupId
+		}
+
+		duplicate := step.Action == CloneAdd && slices.ContainsFunc(planned, func(spec SecurityGroupRuleSpec) bool { return sameRule(spec, line.Left.Spec) })
+		switch {
+		case duplicate:
+			step.Action, step.Reason = CloneSkip, "the target already has the rule with another description"
+		case (step.Spec.PeerIsGroup() || step.Spec.PeerIsPrefixList()) && !self && !sameAccount:
+			step.Action, step.Reason = CloneSkip, fmt.Sprintf("refers to %s, which is not in the profile and region of the target", step.Spec.Peer)
+		case step.Action == CloneModify && step.RuleId == "":
+			step.Action, step.Reason = CloneSkip, "the rule of the target has no rule ID and cannot be modified"
+		default:
+			if err := ValidateSecurityGroupRule(step.Spec); err != nil {
+				step.Action, step.Reason = CloneSkip, err.Error()
+			}
+		}
+		if step.Action != CloneSkip {
+			planned = append(planned, line.Left.Spec)
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan
+}
+
+// Call returns the API call of a step that changes the target group
+func (s CloneStep) Call(targetGroupId string) (APICall, error) {
+	if s.Action == CloneModify {
+		return ModifySecurityGroupRuleCall(targetGroupId, s.RuleId, s.Spec)
+	}
+	return AuthorizeSecurityGroupRuleCall(targetGroupId, s.Spec)
+}
+
+// ApplySecurityGroupClone makes the changes of a clone plan to the target group, one rule
+// at a time, and stops at the first failure. It returns the number of changes made.
+func ApplySecurityGroupClone(ctx context.Context, ecsService ECS, plan *SecurityGroupClonePlan) (int, error) {
+	targetId := plan.Target.SecurityGroupId
+	applied := 0
+	for _, step := range plan.Steps {
+		var err error
+		switch step.Action {
+		case CloneAdd:
+			err = ecsService.AuthorizeSecurityGroupRule(ctx, targetId, step.Spec)
+		case CloneModify:
+			err = ecsService.ModifySecurityGroupRule(ctx, targetId, step.RuleId, step.Spec)
+		default:
+			continue
+		}
+		if err != nil {
+			return applied, fmt.Errorf("cloning %s into %s after %d of %d changes: %w", plan.Source, plan.Target, applied, plan.Changes(), err)
+		}
+		applied++
+	}
+	return applied, nil
+}
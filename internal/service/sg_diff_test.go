@@ -0,0 +1,134 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ruleSet returns a rule set of TCP ingress rules, accepting and of priority 1 unless
+// set otherwise. The rules are given in the order FetchSecurityGroupRuleSet sorts them.
+func ruleSet(ref SecurityGroupRef, rules ...SecurityGroupRule) *SecurityGroupRuleSet {
+	for i := range rules {
+		spec := &rules[i].Spec
+		spec.Direction, spec.IpProtocol = SecurityGroupRuleIngress, "TCP"
+		if spec.Policy == "" {
+			spec.Policy = "accept"
+		}
+		if spec.Priority == 0 {
+			spec.Priority = 1
+		}
+	}
+	return &SecurityGroupRuleSet{Ref: ref, Rules: rules}
+}
+
+// tcpRule returns a rule of a rule set, see ruleSet
+func tcpRule(id, portRange, peer, description string) SecurityGroupRule {
+	return SecurityGroupRule{RuleId: id, Spec: SecurityGroupRuleSpec{PortRange: portRange, Peer: peer, Description: description}}
+}
+
+func TestDiffSecurityGroupRules(t *testing.T) {
+	left := ruleSet(SecurityGroupRef{SecurityGroupId: "sg-left"},
+		tcpRule("sgr-l1", "22/22", "10.0.0.0/8", "SSH"),
+		tcpRule("sgr-l2", "443/443", "0.0.0.0/0", "HTTPS"),
+		tcpRule("sgr-l3", "3306/3306", SelfPeer, ""),
+		tcpRule("sgr-l4", "8080/8080", "10.0.0.0/8", "debug"),
+	)
+	right := ruleSet(SecurityGroupRef{SecurityGroupId: "sg-right"},
+		tcpRule("sgr-r1", "22/22", "10.0.0.0/8", "SSH from office"),
+		tcpRule("sgr-r2", "443/443", "0.0.0.0/0", "HTTPS"),
+		tcpRule("sgr-r3", "5432/5432", SelfPeer, ""),
+		tcpRule("sgr-r4", "8080/8080", "10.0.0.0/8", "debug port"),
+		tcpRule("sgr-r5", "8080/8080", "10.0.0.0/8", "debug"),
+	)
+
+	type line struct{ Kind, Left, Right string }
+	want := []line{
+		{RuleDiffChanged, "sgr-l1", "sgr-r1"},
+		{RuleDiffSame, "sgr-l2", "sgr-r2"},
+		{RuleDiffLeftOnly, "sgr-l3", ""},
+		{RuleDiffRightOnly, "", "sgr-r3"},
+		{RuleDiffSame, "sgr-l4", "sgr-r5"}, // The identical rule is paired first
+		{RuleDiffRightOnly, "", "sgr-r4"},
+	}
+	var got []line
+	for _, diff := range DiffSecurityGroupRules(left, right) {
+		l := line{Kind: diff.Kind}
+		if diff.Left != nil {
+			l.Left = diff.Left.RuleId
+		}
+		if diff.Right != nil {
+			l.Right = diff.Right.RuleId
+		}
+		got = append(got, l)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestPlanSecurityGroupClone(t *testing.T) {
+	source := SecurityGroupRef{Profile: "prod", Region: "cn-hangzhou", SecurityGroupId: "sg-source"}
+	sameAccount := SecurityGroupRef{Profile: "prod", Region: "cn-hangzhou", SecurityGroupId: "sg-target"}
+	otherRegion := SecurityGroupRef{Profile: "prod", Region: "cn-beijing", SecurityGroupId: "sg-target"}
+	sourceRules := []SecurityGroupRule{
+		tcpRule("sgr-s1", "22/22", "10.0.0.0/8", "SSH"),
+		tcpRule("sgr-s2", "22/22", "10.0.0.0/8", "SSH again"),
+		tcpRule("sgr-s3", "443/443", "0.0.0.0/0", "HTTPS"),
+		tcpRule("sgr-s4", "3306/3306", SelfPeer, "MySQL"),
+		tcpRule("sgr-s5", "5432/5432", "sg-app", "PostgreSQL"),
+		tcpRule("sgr-s6", "6379/6379", "pl-office", "Redis"),
+		tcpRule("sgr-s7", "8080/8080", "10.0.0.0/8", "debug"),
+	}
+	targetRules := []SecurityGroupRule{
+		tcpRule("sgr-t1", "22/22", "10.0.0.0/8", "SSH"),
+		tcpRule("sgr-t2", "443/443", "0.0.0.0/0", "HTTPS"),
+		tcpRule("", "8080/8080", "10.0.0.0/8", "debug port"),
+	}
+	targetRules[1].Spec.Priority = 10
+
+	type step struct{ Action, RuleId, Peer, Reason string }
+	tests := []struct {
+		name   string
+		target SecurityGroupRef
+		want   []step
+	}{
+		{
+			name:   "same profile and region",
+			target: sameAccount,
+			want: []step{
+				{CloneSkip, "", "10.0.0.0/8", "the target already has the rule with another description"},
+				{CloneModify, "sgr-t2", "0.0.0.0/0", ""},
+				{CloneAdd, "", "sg-target", ""},
+				{CloneAdd, "", "sg-app", ""},
+				{CloneAdd, "", "pl-office", ""},
+				{CloneSkip, "", "10.0.0.0/8", "the rule of the target has no rule ID and cannot be modified"},
+			},
+		},
+		{
+			name:   "another region",
+			target: otherRegion,
+			want: []step{
+				{CloneSkip, "", "10.0.0.0/8", "the target already has the rule with another description"},
+				{CloneModify, "sgr-t2", "0.0.0.0/0", ""},
+				{CloneAdd, "", "sg-target", ""},
+				{CloneSkip, "", "sg-app", "refers to sg-app, which is not in the profile and region of the target"},
+				{CloneSkip, "", "pl-office", "refers to pl-office, which is not in the profile and region of the target"},
+				{CloneSkip, "", "10.0.0.0/8", "the rule of the target has no rule ID and cannot be modified"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanSecurityGroupClone(
+				ruleSet(source, append([]SecurityGroupRule(nil), sourceRules...)...),
+				ruleSet(tt.target, append([]SecurityGroupRule(nil), targetRules...)...))
+			var got []step
+			for _, s := range plan.Steps {
+				got = append(got, step{s.Action, s.RuleId, s.Spec.Peer, s.Reason})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
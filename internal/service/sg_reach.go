@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+// ReachabilityProtocols lists the protocols a reachability check can be asked about
+var ReachabilityProtocols = []string{"TCP", "UDP", "ICMP"}
+
+// ReachabilityQuery asks whether traffic from a source reaches a port of an ECS instance
+type ReachabilityQuery struct {
+	Source     string // An ECS instance ID, an IP address or a CIDR block
+	InstanceId string // The destination instance
+	Protocol   string // One of ReachabilityProtocols
+	Port       int    // Ignored for ICMP
+}
+
+// Traffic names the traffic of the query, e.g. "TCP/22" or "ICMP"
+func (q ReachabilityQuery) Traffic() string {
+	if q.Protocol == "ICMP" {
+		return q.Protocol
+	}
+	return fmt.Sprintf("%s/%d", q.Protocol, q.Port)
+}
+
+// How a rule matches the traffic of a reachability check
+const (
+	RuleMatches       = "match"
+	RuleMatchesPartly = "partial" // Only part of the source range matches
+	RuleDoesNotMatch  = "no"
+	RuleNotEvaluated  = "skipped" // An earlier rule decided
+)
+
+// IntraGroupPolicyId stands for the rule ID of the intra-group policy in a reachability
+// check, when it decides
+const IntraGroupPolicyId = "intra-group"
+
+// ReachabilityRule is an ingress rule of the destination, as evaluated by a reachability
+// check
+type ReachabilityRule struct {
+	SecurityGroupId string `json:"security_group_id"`
+	RuleId          string `json:"rule_id"`
+	Index           int    `json:"index"` // Position among the rules of its group, names rules without an ID
+	Rule            string `json:"rule"`  // The rule in words, see DescribeRule
+	Policy          string `json:"policy"`
+	Priority        int    `json:"priority"`
+	Match           string `json:"match"`  // RuleMatches, RuleMatchesPartly, RuleDoesNotMatch or RuleNotEvaluated
+	Reason          string `json:"reason"` // Why the rule does or does not match
+	Decides         bool   `json:"decides"`
+}
+
+// Reachability is the outcome of a reachability check
+type Reachability struct {
+	Allowed          bool               `json:"allowed"`
+	Verdict          string             `json:"verdict"`
+	Source           string             `json:"source"`      // The source and the address the destination sees
+	Destination      string             `json:"destination"` // The instance and the address it is reached at
+	SecurityGroupIds []string           `json:"security_group_ids"`
+	Rules            []ReachabilityRule `json:"rules"` // Ingress rules in evaluation order
+	Notes            []string           `json:"notes,omitempty"`
+}
+
+// reachSource is the traffic source of a reachability check as the destination sees it
+type reachSource struct {
+	name     string     // How the source was given
+	network  *net.IPNet // The address or range the traffic comes from
+	groupIds []string   // Security groups of a source instance, only set on the private network
+}
+
+// ValidateReachabilityQuery checks a reachability query before anything is fetched
+func ValidateReachabilityQuery(query ReachabilityQuery) error {
+	if query.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+	if !strings.HasPrefix(query.Source, "i-") {
+		if _, err := parseSourceNetwork(query.Source); err != nil {
+			return err
+		}
+	}
+	if !strings.HasPrefix(query.InstanceId, "i-") {
+		return fmt.Errorf("destination must be an ECS instance ID, got %q", query.InstanceId)
+	}
+	if !slices.Contains(ReachabilityProtocols, query.Protocol) {
+		return fmt.Errorf("protocol must be one of %s, got %q", strings.Join(ReachabilityProtocols, ", "), query.Protocol)
+	}
+	if query.Protocol != "ICMP" && (query.Port < 1 || query.Port > 65535) {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", query.Port)
+	}
+	return nil
+}
+
+// CheckReachability reports whether the security groups of the destination instance let
+// traffic from the source in. Only the ingress rules of the destination are evaluated:
+// the egress rules of a source instance, routes, network ACLs and firewalls on the
+// instance are not.
+func CheckReachability(ctx context.Context, ecsService ECS, query ReachabilityQuery) (*Reachability, error) {
+	if err := ValidateReachabilityQuery(query); err != nil {
+		return nil, err
+	}
+	destination, err := ecsService.FetchInstance(ctx, query.InstanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Reachability{}
+	var source reachSource
+	destinationAddress := ""
+	if strings.HasPrefix(query.Source, "i-") {
+		instance, err := ecsService.FetchInstance(ctx, query.Source)
+		if err != nil {
+			return nil, err
+		}
+		if instance.VpcAttributes.VpcId != "" && instance.VpcAttributes.VpcId == destination.VpcAttributes.VpcId {
+			// Instances of a VPC reach each other on their private addresses, where rules
+			// authorizing a security group apply
+			source.network = hostNetwork(privateAddress(*instance))
+			source.groupIds = instance.SecurityGroupIds.SecurityGroupId
+			destinationAddress = privateAddress(*destination)
+			if source.network == nil {
+				return nil, fmt.Errorf("instance %s has no private address", query.Source)
+			}
+		} else {
+			source.network = hostNetwork(publicAddress(*instance))
+			destinationAddress = publicAddress(*destination)
+			result.Notes = append(result.Notes, "The instances are not in the same VPC, so the traffic goes over their public addresses")
+			if source.network == nil {
+				return nil, fmt.Errorf("instance %s has no public address and is not in the VPC of %s", query.Source, query.InstanceId)
+			}
+		}
+		source.name = fmt.Sprintf("%s (%s)", query.Source, source.network.IP)
+	} else {
+		source.network, _ = parseSourceNetwork(query.Source)
+		source.name = source.network.String()
+	}
+	result.Source = source.name
+	result.Destination = query.InstanceId
+	if destinationAddress != "" {
+		result.Destination = fmt.Sprintf("%s (%s)", query.InstanceId, destinationAddress)
+	}
+
+	// A group left out could hold the rule that decides, so any group that cannot be read
+	// fails the check rather than being skipped
+	var groups []ecs.SecurityGroup
+	rules := make(map[string][]ecs.Permission)
+	innerPolicies := make(map[string]string)
+	for _, groupId := range destination.SecurityGroupIds.SecurityGroupId {
+		group, err := ecsService.FetchSecurityGroup(ctx, groupId)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+		response, err := ecsService.FetchSecurityGroupRules(ctx, groupId)
+		if err != nil {
+			return nil, err
+		}
+		rules[groupId] = response.Permissions.Permission
+		innerPolicies[groupId] = innerAccessPolicy(*group, response.InnerAccessPolicy)
+	}
+
+	evaluateReachability(result, source, groups, rules, innerPolicies, query)
+	if destination.Status != "" && destination.Status != "Running" {
+		result.Notes = append(result.Notes, fmt.Sprintf("The destination instance is %s", destination.Status))
+	}
+	return result, nil
+}
+
+// evaluateReachability evaluates the ingress rules of every security group of the
+// destination as one list: the lowest priority number first and, at equal priority, drop
+// rules before accept rules. The first rule matching the traffic decides. Without one,
+// instances of the same basic security group may still reach each other, and any other
+// traffic is dropped.
+func evaluateReachability(result *Reachability, source reachSource, groups []ecs.SecurityGroup, rules map[string][]ecs.Permission, innerPolicies map[string]string, query ReachabilityQuery) {
+	for _, group := range groups {
+		result.SecurityGroupIds = append(result.SecurityGroupIds, group.SecurityGroupId)
+		for index, rule := range rules[group.SecurityGroupId] {
+			spec := SecurityGroupRuleSpecOf(rule)
+			if spec.Direction != SecurityGroupRuleIngress {
+				continue
+			}
+			result.Rules = append(result.Rules, ReachabilityRule{
+				SecurityGroupId: group.SecurityGroupId,
+				RuleId:          rule.SecurityGroupRuleId,
+				Index:           index,
+				Rule:            DescribeRule(spec),
+				Policy:          spec.Policy,
+				Priority:        spec.Priority,
+			})
+			r := &result.Rules[len(result.Rules)-1]
+			r.Match, r.Reason = matchRule(spec, source, query)
+		}
+	}
+	slices.SortStableFunc(result.Rules, func(a, b ReachabilityRule) int {
+		if a.Priority != b.Priority {
+			return a.Priority - b.Priority
+		}
+		return policyRank(a.Policy) - policyRank(b.Policy)
+	})
+
+	var partial []string
+	for i := range result.Rules {
+		r := &result.Rules[i]
+		if result.Verdict != "" {
+			r.Match, r.Reason = RuleNotEvaluated, "An earlier rule decided"
+			continue
+		}
+		switch r.Match {
+		case RuleMatchesPartly:
+			partial = append(partial, ruleName(ecs.Permission{SecurityGroupRuleId: r.RuleId}, r.Index))
+		case RuleMatches:
+			r.Decides = true
+			result.Allowed = r.Policy == "accept"
+			verb := "Allowed"
+			if !result.Allowed {
+				verb = "Dropped"
+			}
+			result.Verdict = fmt.Sprintf("%s by rule %s of %s: %s", verb, ruleName(ecs.Permission{SecurityGroupRuleId: r.RuleId}, r.Index), r.SecurityGroupId, r.Rule)
+		}
+	}
+	if len(partial) > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("Part of the source range matches rule %s; other addresses of the range may get a different answer", strings.Join(partial, ", ")))
+	}
+	if result.Verdict != "" {
+		return
+	}
+
+	for _, groupId := range source.groupIds {
+		if slices.Contains(result.SecurityGroupIds, groupId) && innerPolicies[groupId] == "Accept" {
+			result.Allowed = true
+			result.Verdict = fmt.Sprintf("Allowed by the intra-group policy of %s: no rule matches, and instances of the group reach each other on the private network", groupId)
+			result.Rules = append(result.Rules, ReachabilityRule{
+				SecurityGroupId: groupId,
+				RuleId:          IntraGroupPolicyId,
+				Rule:            "Instances of the same group accept each other's traffic",
+				Policy:          "accept",
+				Match:           RuleMatches,
+				Reason:          "The source instance is in this group",
+				Decides:         true,
+			})
+			return
+		}
+	}
+	result.Verdict = "Dropped: no rule accepts the traffic, and security groups drop inbound traffic by default"
+}
+
+// matchRule reports whether an ingress rule matches the traffic of a reachability check,
+// and why
+func matchRule(spec SecurityGroupRuleSpec, source reachSource, query ReachabilityQuery) (string, string) {
+	switch {
+	case spec.IpProtocol != "ALL" && spec.IpProtocol != query.Protocol:
+		return RuleDoesNotMatch, fmt.Sprintf("Protocol %s only", spec.IpProtocol)
+	case query.Protocol != "ICMP" && !allowsPort(spec, query.Port):
+		return RuleDoesNotMatch, fmt.Sprintf("Port %d is not in %s", query.Port, spec.PortRange)
+	}
+
+	if spec.PeerIsGroup() {
+		if slices.Contains(source.groupIds, spec.Peer) {
+			return RuleMatches, fmt.Sprintf("The source instance is in %s", spec.Peer)
+		}
+		if source.groupIds == nil {
+			return RuleDoesNotMatch, fmt.Sprintf("Applies to instances of %s on the private network only", spec.Peer)
+		}
+		return RuleDoesNotMatch, fmt.Sprintf("The source instance is not in %s", spec.Peer)
+	}
+	if spec.PeerIsPrefixList() {
+		return RuleMatchesPartly, fmt.Sprintf("The CIDR blocks of prefix list %s are not checked", spec.Peer)
+	}
+
+	_, peer, err := net.ParseCIDR(spec.Peer)
+	if err != nil {
+		return RuleDoesNotMatch, fmt.Sprintf("Cannot read the source %q", spec.Peer)
+	}
+	if peerCovers(peer.String(), source.network.String()) {
+		return RuleMatches, fmt.Sprintf("%s is in %s", source.network, spec.Peer)
+	}
+	if peer.Contains(source.network.IP) || source.network.Contains(peer.IP) {
+		return RuleMatchesPartly, fmt.Sprintf("%s covers only part of %s", spec.Peer, source.network)
+	}
+	return RuleDoesNotMatch, fmt.Sprintf("%s is not in %s", source.network, spec.Peer)
+}
+
+// policyRank orders drop rules before accept rules of the same priority
+func policyRank(policy string) int {
+	if policy == "drop" {
+		return 0
+	}
+	return 1
+}
+
+// innerAccessPolicy returns whether instances of a group reach each other. Groups that do
+// not say follow their type: basic groups accept, enterprise groups drop.
+func innerAccessPolicy(group ecs.SecurityGroup, policy string) string {
+	if policy != "" {
+		return policy
+	}
+	if group.SecurityGroupType == "enterprise" {
+		return "Drop"
+	}
+	return "Accept"
+}
+
+// parseSourceNetwork parses the source of a reachability check given as an IP address or
+// a CIDR block
+func parseSourceNetwork(source string) (*net.IPNet, error) {
+	if network := hostNetwork(source); network != nil {
+		return network, nil
+	}
+	_, network, err := net.ParseCIDR(source)
+	if err != nil {
+		return nil, fmt.Errorf("source must be an ECS instance ID, an IP address or a CIDR block, got %q", source)
+	}
+	return network, nil
+}
+
+// hostNetwork returns the network of a single address, or nil if it is not one
+func hostNetwork(address string) *net.IPNet {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// privateAddress returns the first private address of an instance
+func privateAddress(instance ecs.Instance) string {
+	if addresses := instance.VpcAttributes.PrivateIpAddress.IpAddress; len(addresses) > 0 {
+		return addresses[0]
+	}
+	if addresses := instance.InnerIpAddress.IpAddress; len(addresses) > 0 {
+		return addresses[0]
+	}
+	return ""
+}
+
+// publicAddress returns the elastic IP of an instance, or else its first public address
+func publicAddress(instance ecs.Instance) string {
+	if instance.EipAddress.IpAddress != "" {
+		return instance.EipAddress.IpAddress
+	}
+	if addresses := instance.PublicIpAddress.IpAddress; len(addresses) > 0 {
+		return addresses[0]
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+)
+
+func TestEvaluateReachability(t *testing.T) {
+	network := func(cidr string) *net.IPNet {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	groups := []ecs.SecurityGroup{{SecurityGroupId: "sg-web"}, {SecurityGroupId: "sg-db"}}
+	rules := map[string][]ecs.Permission{
+		"sg-web": {
+			{Direction: "egress", IpProtocol: "ALL", PortRange: "-1/-1", DestCidrIp: "0.0.0.0/0", Policy: "Accept", Priority: "100"},
+			ingressRule("sgr-https", "443/443", "0.0.0.0/0", "Accept", "1"),
+			ingressRule("", "22/22", "10.1.0.0/16", "Accept", "1"),
+			ingressRule("sgr-blocked", "80/80", "203.0.113.0/24", "Drop", "5"),
+			ingressRule("sgr-http", "80/80", "0.0.0.0/0", "Accept", "5"),
+		},
+		"sg-db": {
+			{SecurityGroupRuleId: "sgr-mysql", Direction: "ingress", IpProtocol: "TCP", PortRange: "3306/3306", SourceGroupId: "sg-app", Policy: "Accept", Priority: "1"},
+		},
+	}
+	innerPolicies := map[string]string{"sg-web": "Drop", "sg-db": "Accept"}
+
+	tests := []struct {
+		name    string
+		source  reachSource
+		query   ReachabilityQuery
+		allowed bool
+		verdict string
+		decides string // The rule ID of the deciding rule
+		notes   []string
+	}{
+		{
+			name:    "allowed by a rule",
+			source:  reachSource{network: network("198.51.100.7/32")},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 443},
+			allowed: true,
+			verdict: "Allowed by rule sgr-https of sg-web: ingress TCP 443/443 from 0.0.0.0/0, accept, priority 1",
+			decides: "sgr-https",
+		},
+		{
+			name:    "drop rule first at equal priority",
+			source:  reachSource{network: network("203.0.113.7/32")},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 80},
+			verdict: "Dropped by rule sgr-blocked of sg-web: ingress TCP 80/80 from 203.0.113.0/24, drop, priority 5",
+			decides: "sgr-blocked",
+		},
+		{
+			name:    "no rule matches",
+			source:  reachSource{network: network("198.51.100.7/32")},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 22},
+			verdict: "Dropped: no rule accepts the traffic, and security groups drop inbound traffic by default",
+		},
+		{
+			name:    "part of the source range, rule named by its position in its group",
+			source:  reachSource{network: network("10.0.0.0/8")},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 22},
+			verdict: "Dropped: no rule accepts the traffic, and security groups drop inbound traffic by default",
+			notes:   []string{"Part of the source range matches rule #3; other addresses of the range may get a different answer"},
+		},
+		{
+			name:    "rule naming a security group",
+			source:  reachSource{network: network("192.168.0.5/32"), groupIds: []string{"sg-app"}},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 3306},
+			allowed: true,
+			verdict: "Allowed by rule sgr-mysql of sg-db: ingress TCP 3306/3306 from sg-app, accept, priority 1",
+			decides: "sgr-mysql",
+		},
+		{
+			name:    "intra-group policy",
+			source:  reachSource{network: network("192.168.0.6/32"), groupIds: []string{"sg-db"}},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 5432},
+			allowed: true,
+			verdict: "Allowed by the intra-group policy of sg-db: no rule matches, and instances of the group reach each other on the private network",
+			decides: IntraGroupPolicyId,
+		},
+		{
+			name:    "intra-group policy dropping",
+			source:  reachSource{network: network("192.168.0.7/32"), groupIds: []string{"sg-web"}},
+			query:   ReachabilityQuery{Protocol: "TCP", Port: 5432},
+			verdict: "Dropped: no rule accepts the traffic, and security groups drop inbound traffic by default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &Reachability{}
+			evaluateReachability(result, tt.source, groups, rules, innerPolicies, tt.query)
+			if result.Allowed != tt.allowed || result.Verdict != tt.verdict {
+				t.Errorf("got %v, %q\nwant %v, %q", result.Allowed, result.Verdict, tt.allowed, tt.verdict)
+			}
+			if !reflect.DeepEqual(result.Notes, tt.notes) {
+				t.Errorf("notes %q, want %q", result.Notes, tt.notes)
+			}
+
+			decides := ""
+			for _, rule := range result.Rules {
+				if rule.Decides {
+					decides = rule.RuleId
+				}
+			}
+			if decides != tt.decides {
+				t.Errorf("decided by %q, want %q", decides, tt.decides)
+			}
+		})
+	}
+}
+
+// reachECS serves the lookups of CheckReachability from memory; the rules of a group
+// missing from rules cannot be described
+type reachECS struct {
+	ECS
+	instance ecs.Instance
+	rules    map[string][]ecs.Permission
+}
+
+func (s *reachECS) FetchInstance(ctx context.Context, instanceId string) (*ecs.Instance, error) {
+	return &s.instance, nil
+}
+
+func (s *reachECS) FetchSecurityGroup(ctx context.Context, securityGroupId string) (*ecs.SecurityGroup, error) {
+	return &ecs.SecurityGroup{SecurityGroupId: securityGroupId}, nil
+}
+
+func (s *reachECS) FetchSecurityGroupRules(ctx context.Context, securityGroupId string) (*ecs.DescribeSecurityGroupAttributeResponse, error) {
+	rules, ok := s.rules[securityGroupId]
+	if !ok {
+		return nil, fmt.Errorf("describing security group rules for %s: Forbidden.RAM", securityGroupId)
+	}
+	response := &ecs.DescribeSecurityGroupAttributeResponse{SecurityGroupId: securityGroupId}
+	response.Permissions.Permission = rules
+	return response, nil
+}
+
+func TestCheckReachabilityUnreadableGroup(t *testing.T) {
+	ecsService := &reachECS{
+		instance: ecs.Instance{InstanceId: "i-web", Status: "Running"},
+		rules:    map[string][]ecs.Permission{"sg-web": {ingressRule("sgr-https", "443/443", "0.0.0.0/0", "Accept", "1")}},
+	}
+	ecsService.instance.SecurityGroupIds.SecurityGroupId = []string{"sg-web", "sg-deny"}
+	query := ReachabilityQuery{Source: "198.51.100.7", InstanceId: "i-web", Protocol: "TCP", Port: 443}
+
+	// sg-deny may hold a drop rule deciding first, so no verdict is given without it
+	result, err := CheckReachability(context.Background(), ecsService, query)
+	if err == nil || !strings.Contains(err.Error(), "sg-deny") {
+		t.Fatalf("got %+v, %v; want an error naming sg-deny", result, err)
+	}
+
+	ecsService.rules["sg-deny"] = nil
+	result, err = CheckReachability(context.Background(), ecsService, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed || !reflect.DeepEqual(result.SecurityGroupIds, []string{"sg-web", "sg-deny"}) {
+		t.Errorf("allowed %v by %v, want allowed by both groups", result.Allowed, result.SecurityGroupIds)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -19,12 +20,15 @@ func NewSLBService(client *slb.Client) *SLBService {
 }
 
 // FetchInstances retrieves all SLB instances using pagination
-func (s *SLBService) FetchInstances() ([]slb.LoadBalancer, error) {
+func (s *SLBService) FetchInstances(ctx context.Context) ([]slb.LoadBalancer, error) {
 	var allLoadBalancers []slb.LoadBalancer
 	pageNumber := int64(1)
 	pageSize := int64(100)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		request := slb.CreateDescribeLoadBalancersRequest()
 		request.Scheme = "https"
 		request.PageNumber = requests.NewInteger(int(pageNumber))
@@ -63,7 +67,7 @@ type ListenerDetail struct {
 }
 
 // FetchListeners retrieves all listeners for a specific SLB instance
-func (s *SLBService) FetchListeners(loadBalancerId string) (*slb.DescribeLoadBalancerAttributeResponse, error) {
+func (s *SLBService) FetchListeners(ctx context.Context, loadBalancerId string) (*slb.DescribeLoadBalancerAttributeResponse, error) {
 	request := slb.CreateDescribeLoadBalancerAttributeRequest()
 	request.Scheme = "https"
 	request.LoadBalancerId = loadBalancerId
@@ -77,9 +81,9 @@ func (s *SLBService) FetchListeners(loadBalancerId string) (*slb.DescribeLoadBal
 }
 
 // FetchDetailedListeners retrieves detailed information for all listeners of an SLB instance
-func (s *SLBService) FetchDetailedListeners(loadBalancerId string) ([]ListenerDetail, error) {
+func (s *SLBService) FetchDetailedListeners(ctx context.Context, loadBalancerId string) ([]ListenerDetail, error) {
 	// First get the basic listener info
-	basicResponse, err := s.FetchListeners(loadBalancerId)
+	basicResponse, err := s.FetchListeners(ctx, loadBalancerId)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +92,9 @@ func (s *SLBService) FetchDetailedListeners(loadBalancerId string) ([]ListenerDe
 
 	// For each listener port, try to get detailed information
 	for _, port := range basicResponse.ListenerPorts.ListenerPort {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// Try HTTP listener first
 		if httpDetail := s.fetchHTTPListenerDetail(loadBalancerId, port); httpDetail != nil {
 			detailedListeners = append(detailedListeners, *httpDetail)
@@ -277,7 +284,7 @@ type VServerGroupDetail struct {
 }
 
 // FetchVServerGroups retrieves all virtual server groups for a specific SLB instance
-func (s *SLBService) FetchVServerGroups(loadBalancerId string) ([]slb.VServerGroup, error) {
+func (s *SLBService) FetchVServerGroups(ctx context.Context, loadBalancerId string) ([]slb.VServerGroup, error) {
 	request := slb.CreateDescribeVServerGroupsRequest()
 	request.Scheme = "https"
 	request.LoadBalancerId = loadBalancerId
@@ -291,15 +298,15 @@ func (s *SLBService) FetchVServerGroups(loadBalancerId string) ([]slb.VServerGro
 }
 
 // FetchDetailedVServerGroups retrieves detailed information for all virtual server groups
-func (s *SLBService) FetchDetailedVServerGroups(loadBalancerId string) ([]VServerGroupDetail, error) {
+func (s *SLBService) FetchDetailedVServerGroups(ctx context.Context, loadBalancerId string) ([]VServerGroupDetail, error) {
 	// Get basic VServer groups
-	vServerGroups, err := s.FetchVServerGroups(loadBalancerId)
+	vServerGroups, err := s.FetchVServerGroups(ctx, loadBalancerId)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get detailed listeners to find associations
-	listeners, err := s.FetchDetailedListeners(loadBalancerId)
+	listeners, err := s.FetchDetailedListeners(ctx, loadBalancerId)
 	if err != nil {
 		return nil, err
 	}
@@ -307,8 +314,11 @@ func (s *SLBService) FetchDetailedVServerGroups(loadBalancerId string) ([]VServe
 	var detailedVServerGroups []VServerGroupDetail
 
 	for _, vsg := range vServerGroups {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// Get backend server count
-		backendServers, err := s.FetchVServerGroupBackendServers(vsg.VServerGroupId)
+		backendServers, err := s.FetchVServerGroupBackendServers(ctx, vsg.VServerGroupId)
 		if err != nil {
 			// If we can't get backend servers, set count to 0
 			backendServers = []slb.BackendServerInDescribeVServerGroupAttribute{}
@@ -346,7 +356,7 @@ type BackendServerDetail struct {
 }
 
 // FetchVServerGroupBackendServers retrieves backend servers for a specific virtual server group
-func (s *SLBService) FetchVServerGroupBackendServers(vServerGroupId string) ([]slb.BackendServerInDescribeVServerGroupAttribute, error) {
+func (s *SLBService) FetchVServerGroupBackendServers(ctx context.Context, vServerGroupId string) ([]slb.BackendServerInDescribeVServerGroupAttribute, error) {
 	request := slb.CreateDescribeVServerGroupAttributeRequest()
 	request.Scheme = "https"
 	request.VServerGroupId = vServerGroupId
@@ -360,9 +370,9 @@ func (s *SLBService) FetchVServerGroupBackendServers(vServerGroupId string) ([]s
 }
 
 // FetchDetailedBackendServers retrieves detailed information for backend servers including ECS details
-func (s *SLBService) FetchDetailedBackendServers(vServerGroupId string, ecsClient *ecs.Client) ([]BackendServerDetail, error) {
+func (s *SLBService) FetchDetailedBackendServers(ctx context.Context, vServerGroupId string, ecsClient *ecs.Client) ([]BackendServerDetail, error) {
 	// Get basic backend servers
-	backendServers, err := s.FetchVServerGroupBackendServers(vServerGroupId)
+	backendServers, err := s.FetchVServerGroupBackendServers(ctx, vServerGroupId)
 	if err != nil {
 		return nil, err
 	}
@@ -370,6 +380,9 @@ func (s *SLBService) FetchDetailedBackendServers(vServerGroupId string, ecsClien
 	var detailedServers []BackendServerDetail
 
 	for _, server := range backendServers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		detail := BackendServerDetail{
 			ServerId:         server.ServerId,
 			Port:             server.Port,
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+)
+
+// UnlimitedBandwidth is the bandwidth of listeners of pay-by-traffic load balancers
+const UnlimitedBandwidth = -1
+
+// ListenerAttributes holds the full configuration of a listener, whatever its protocol.
+// Settings a protocol does not have are left empty.
+type ListenerAttributes struct {
+	LoadBalancerId   string
+	Protocol         string // HTTP, HTTPS, TCP or UDP
+	Port             int
+	BackendPort      int
+	Status           string
+	Description      string
+	Scheduler        string
+	VServerGroupId   string
+	VServerGroupName string
+	Bandwidth        int // Peak bandwidth in Mbit/s, or UnlimitedBandwidth
+
+	HealthCheck   ListenerHealthCheck
+	StickySession ListenerStickySession  // HTTP and HTTPS
+	XForwardedFor ListenerForwardedFor   // HTTP and HTTPS
+	Acl           ListenerAcl            // Access control list bound to the listener
+	TLS           ListenerTLS            // HTTPS
+	Rules         []slb.Rule             // Forwarding rules by domain and URL, HTTP and HTTPS
+	Domains       []slb.DomainExtension  // Certificates of additional domains, HTTPS
+	HTTP          ListenerHTTPSettings   // HTTP and HTTPS
+	Connection    ListenerConnectionOpts // TCP and UDP
+}
+
+// ListenerHealthCheck holds how a listener checks its backend servers
+type ListenerHealthCheck struct {
+	Enabled            string // "on" or "off"
+	Type               string // "tcp" or "http" for TCP listeners
+	Method             string // HEAD or GET
+	Domain             string
+	URI                string
+	ConnectPort        int // 0 for the backend port
+	Interval           int // Seconds
+	Timeout            int // Seconds
+	HealthyThreshold   int
+	UnhealthyThreshold int
+	HttpCodes          string // e.g. "http_2xx,http_3xx"
+	Request            string // Request sent by UDP health checks
+	ExpectedResponse   string // Response UDP health checks expect
+}
+
+// ListenerStickySession holds how an HTTP or HTTPS listener keeps a client on one server
+type ListenerStickySession struct {
+	Enabled       string // "on" or "off"
+	Type          string // "insert" for a cookie set by SLB, "server" for the backend's cookie
+	Cookie        string // Cookie of the backend, with Type "server"
+	CookieTimeout int    // Seconds, with Type "insert"
+}
+
+// ListenerForwardedFor holds the headers an HTTP or HTTPS listener adds to requests,
+// each "on" or "off"
+type ListenerForwardedFor struct {
+	XForwardedFor string // X-Forwarded-For, the client IP
+	SLBIP         string // SLB-IP, the address of the load balancer
+	SLBID         string // SLB-ID, the ID of the load balancer
+	Proto         string // X-Forwarded-Proto, the protocol of the listener
+	SLBPort       string // X-Forwarded-Port, the port of the listener
+	ClientSrcPort string // X-Forwarded-Client-srcport, the port of the client
+}
+
+// ListenerAcl holds the access control of a listener
+type ListenerAcl struct {
+	Status string   // "on" or "off"
+	Type   string   // "white" allows only the listed addresses, "black" refuses them
+	Ids    []string // Access control lists
+}
+
+// ListenerTLS holds the certificates and TLS settings of an HTTPS listener
+type ListenerTLS struct {
+	ServerCertificateId string
+	CACertificateId     string // For mutual authentication, empty when off
+	CipherPolicy        string
+	EnableHttp2         string // "on" or "off"
+}
+
+// ListenerHTTPSettings holds the settings of HTTP and HTTPS listeners not covered above
+type ListenerHTTPSettings struct {
+	Gzip           string // "on" or "off"
+	IdleTimeout    int    // Seconds
+	RequestTimeout int    // Seconds
+	ForwardToPort  int    // HTTPS listener port HTTP requests are redirected to, 0 when off
+}
+
+// ListenerConnectionOpts holds the connection settings of TCP and UDP listeners
+type ListenerConnectionOpts struct {
+	PersistenceTimeout     int    // Seconds a client stays on one server, 0 when off
+	ConnectionDrain        string // "on" or "off"
+	ConnectionDrainTimeout int    // Seconds
+	EstablishedTimeout     int    // Seconds an idle TCP connection is kept
+}
+
+// FetchListenerAttributes retrieves the full configuration of a listener. The protocol
+// decides which API describes it, as listeners of different protocols may share a port.
+func (s *SLBService) FetchListenerAttributes(ctx context.Context, loadBalancerId, protocol string, port int) (*ListenerAttributes, error) {
+	var attributes *ListenerAttributes
+	var err error
+	switch strings.ToUpper(protocol) {
+	case "HTTP":
+		attributes, err = s.fetchHTTPListenerAttributes(loadBalancerId, port)
+	case "HTTPS":
+		attributes, err = s.fetchHTTPSListenerAttributes(loadBalancerId, port)
+	case "TCP":
+		attributes, err = s.fetchTCPListenerAttributes(loadBalancerId, port)
+	case "UDP":
+		attributes, err = s.fetchUDPListenerAttributes(loadBalancerId, port)
+	default:
+		return nil, fmt.Errorf("describing listener %s:%d of SLB %s: unsupported protocol", protocol, port, loadBalancerId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("describing listener %s:%d of SLB %s: %w", protocol, port, loadBalancerId, err)
+	}
+
+	if attributes.VServerGroupId != "" {
+		if name, err := s.getVServerGroupName(attributes.VServerGroupId); err == nil {
+			attributes.VServerGroupName = name
+		}
+	}
+	return attributes, nil
+}
+
+// fetchHTTPListenerAttributes describes an HTTP listener
+func (s *SLBService) fetchHTTPListenerAttributes(loadBalancerId string, port int) (*ListenerAttributes, error) {
+	request := slb.CreateDescribeLoadBalancerHTTPListenerAttributeRequest()
+	request.Scheme = "https"
+	request.LoadBalancerId = loadBalancerId
+	request.ListenerPort = requests.NewInteger(port)
+
+	response, err := s.client.DescribeLoadBalancerHTTPListenerAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := &ListenerAttributes{
+		LoadBalancerId: loadBalancerId,
+		Protocol:       "HTTP",
+		Port:           port,
+		BackendPort:    response.BackendServerPort,
+		Status:         response.Status,
+		Description:    response.Description,
+		Scheduler:      response.Scheduler,
+		VServerGroupId: response.VServerGroupId,
+		Bandwidth:      response.Bandwidth,
+		HealthCheck: ListenerHealthCheck{
+			Enabled:            response.HealthCheck,
+			Type:               response.HealthCheckType,
+			Method:             response.HealthCheckMethod,
+			Domain:             response.HealthCheckDomain,
+			URI:                response.HealthCheckURI,
+			ConnectPort:        response.HealthCheckConnectPort,
+			Interval:           response.HealthCheckInterval,
+			Timeout:            response.HealthCheckTimeout,
+			HealthyThreshold:   response.HealthyThreshold,
+			UnhealthyThreshold: response.UnhealthyThreshold,
+			HttpCodes:          response.HealthCheckHttpCode,
+		},
+		StickySession: ListenerStickySession{
+			Enabled:       response.StickySession,
+			Type:          response.StickySessionType,
+			Cookie:        response.Cookie,
+			CookieTimeout: response.CookieTimeout,
+		},
+		XForwardedFor: ListenerForwardedFor{
+			XForwardedFor: response.XForwardedFor,
+			SLBIP:         response.XForwardedForSLBIP,
+			SLBID:         response.XForwardedForSLBID,
+			Proto:         response.XForwardedForProto,
+			SLBPort:       response.XForwardedForSLBPORT,
+			ClientSrcPort: response.XForwardedForClientSrcPort,
+		},
+		Acl:   ListenerAcl{Status: response.AclStatus, Type: response.AclType, Ids: aclIds(response.AclId, response.AclIds.AclId)},
+		Rules: response.Rules.Rule,
+		HTTP: ListenerHTTPSettings{
+			Gzip:           response.Gzip,
+			IdleTimeout:    response.IdleTimeout,
+			RequestTimeout: response.RequestTimeout,
+		},
+	}
+	if response.ListenerForward == "on" {
+		attributes.HTTP.ForwardToPort = response.ForwardPort
+	}
+	return attributes, nil
+}
+
+// fetchHTTPSListenerAttributes describes an HTTPS listener
+func (s *SLBService) fetchHTTPSListenerAttributes(loadBalancerId string, port int) (*ListenerAttributes, error) {
+	request := slb.CreateDescribeLoadBalancerHTTPSListenerAttributeRequest()
+	request.Scheme = "https"
+	request.LoadBalancerId = loadBalancerId
+	request.ListenerPort = requests.NewInteger(port)
+
+	response, err := s.client.DescribeLoadBalancerHTTPSListenerAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListenerAttributes{
+		LoadBalancerId: loadBalancerId,
+		Protocol:       "HTTPS",
+		Port:           port,
+		BackendPort:    response.BackendServerPort,
+		Status:         response.Status,
+		Description:    response.Description,
+		Scheduler:      response.Scheduler,
+		VServerGroupId: response.VServerGroupId,
+		Bandwidth:      response.Bandwidth,
+		HealthCheck: ListenerHealthCheck{
+			Enabled:            response.HealthCheck,
+			Type:               response.HealthCheckType,
+			Method:             response.HealthCheckMethod,
+			Domain:             response.HealthCheckDomain,
+			URI:                response.HealthCheckURI,
+			ConnectPort:        response.HealthCheckConnectPort,
+			Interval:           response.HealthCheckInterval,
+			Timeout:            response.HealthCheckTimeout,
+			HealthyThreshold:   response.HealthyThreshold,
+			UnhealthyThreshold: response.UnhealthyThreshold,
+			HttpCodes:          response.HealthCheckHttpCode,
+		},
+		StickySession: ListenerStickySession{
+			Enabled:       response.StickySession,
+			Type:          response.StickySessionType,
+			Cookie:        response.Cookie,
+			CookieTimeout: response.CookieTimeout,
+		},
+		XForwardedFor: ListenerForwardedFor{
+			XForwardedFor: response.XForwardedFor,
+			SLBIP:         response.XForwardedForSLBIP,
+			SLBID:         response.XForwardedForSLBID,
+			Proto:         response.XForwardedForProto,
+			SLBPort:       response.XForwardedForSLBPORT,
+			ClientSrcPort: response.XForwardedForClientSrcPort,
+		},
+		Acl: ListenerAcl{Status: response.AclStatus, Type: response.AclType, Ids: aclIds(response.AclId, response.AclIds.AclId)},
+		TLS: ListenerTLS{
+			ServerCertificateId: response.ServerCertificateId,
+			CACertificateId:     response.CACertificateId,
+			CipherPolicy:        response.TLSCipherPolicy,
+			EnableHttp2:         response.EnableHttp2,
+		},
+		Rules:   response.Rules.Rule,
+		Domains: response.DomainExtensions.DomainExtension,
+		HTTP: ListenerHTTPSettings{
+			Gzip:           response.Gzip,
+			IdleTimeout:    response.IdleTimeout,
+			RequestTimeout: response.RequestTimeout,
+		},
+	}, nil
+}
+
+// fetchTCPListenerAttributes describes a TCP listener
+func (s *SLBService) fetchTCPListenerAttributes(loadBalancerId string, port int) (*ListenerAttributes, error) {
+	request := slb.CreateDescribeLoadBalancerTCPListenerAttributeRequest()
+	request.Scheme = "https"
+	request.LoadBalancerId = loadBalancerId
+	request.ListenerPort = requests.NewInteger(port)
+
+	response, err := s.client.DescribeLoadBalancerTCPListenerAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListenerAttributes{
+		LoadBalancerId: loadBalancerId,
+		Protocol:       "TCP",
+		Port:           port,
+		BackendPort:    response.BackendServerPort,
+		Status:         response.Status,
+		Description:    response.Description,
+		Scheduler:      response.Scheduler,
+		VServerGroupId: response.VServerGroupId,
+		Bandwidth:      response.Bandwidth,
+		HealthCheck: ListenerHealthCheck{
+			Enabled:            response.HealthCheck,
+			Type:               response.HealthCheckType,
+			Method:             response.HealthCheckMethod,
+			Domain:             response.HealthCheckDomain,
+			URI:                response.HealthCheckURI,
+			ConnectPort:        response.HealthCheckConnectPort,
+			Interval:           response.HealthCheckInterval,
+			Timeout:            response.HealthCheckConnectTimeout,
+			HealthyThreshold:   response.HealthyThreshold,
+			UnhealthyThreshold: response.UnhealthyThreshold,
+			HttpCodes:          response.HealthCheckHttpCode,
+		},
+		Acl: ListenerAcl{Status: response.AclStatus, Type: response.AclType, Ids: aclIds(response.AclId, response.AclIds.AclId)},
+		Connection: ListenerConnectionOpts{
+			PersistenceTimeout:     response.PersistenceTimeout,
+			ConnectionDrain:        response.ConnectionDrain,
+			ConnectionDrainTimeout: response.ConnectionDrainTimeout,
+			EstablishedTimeout:     response.EstablishedTimeout,
+		},
+	}, nil
+}
+
+// fetchUDPListenerAttributes describes a UDP listener
+func (s *SLBService) fetchUDPListenerAttributes(loadBalancerId string, port int) (*ListenerAttributes, error) {
+	request := slb.CreateDescribeLoadBalancerUDPListenerAttributeRequest()
+	request.Scheme = "https"
+	request.LoadBalancerId = loadBalancerId
+	request.ListenerPort = requests.NewInteger(port)
+
+	response, err := s.client.DescribeLoadBalancerUDPListenerAttribute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListenerAttributes{
+		LoadBalancerId: loadBalancerId,
+		Protocol:       "UDP",
+		Port:           port,
+		BackendPort:    response.BackendServerPort,
+		Status:         response.Status,
+		Description:    response.Description,
+		Scheduler:      response.Scheduler,
+		VServerGroupId: response.VServerGroupId,
+		Bandwidth:      response.Bandwidth,
+		HealthCheck: ListenerHealthCheck{
+			Enabled:            response.HealthCheck,
+			ConnectPort:        response.HealthCheckConnectPort,
+			Interval:           response.HealthCheckInterval,
+			Timeout:            response.HealthCheckConnectTimeout,
+			HealthyThreshold:   response.HealthyThreshold,
+			UnhealthyThreshold: response.UnhealthyThreshold,
+			Request:            response.HealthCheckReq,
+			ExpectedResponse:   response.HealthCheckExp,
+		},
+		Acl: ListenerAcl{Status: response.AclStatus, Type: response.AclType, Ids: aclIds(response.AclId, response.AclIds.AclId)},
+		Connection: ListenerConnectionOpts{
+			PersistenceTimeout:     response.PersistenceTimeout,
+			ConnectionDrain:        response.ConnectionDrain,
+			ConnectionDrainTimeout: response.ConnectionDrainTimeout,
+		},
+	}, nil
+}
+
+// aclIds returns the access control lists of a listener: responses list them in AclIds,
+// or only in AclId for listeners bound to a single one
+func aclIds(aclId string, ids []string) []string {
+	if len(ids) == 0 && aclId != "" {
+		return []string{aclId}
+	}
+	return ids
+}
@@ -134,6 +134,20 @@ func CreateTableHeaders(table *tview.Table, headers []string) {
 	}
 }
 
+// InsertRegionColumn inserts a Region column after the first column of a list table.
+// The region of each row is looked up by the resource ID stored as the reference of its first cell.
+func InsertRegionColumn(table *tview.Table, regionByID map[string]string) {
+	table.InsertColumn(1)
+	table.SetCell(0, 1, tview.NewTableCell("Region").SetTextColor(tcell.ColorYellow).SetAlign(tview.AlignCenter).SetSelectable(false).SetExpansion(1))
+	for row := 1; row < table.GetRowCount(); row++ {
+		region := ""
+		if id, ok := table.GetCell(row, 0).GetReference().(string); ok {
+			region = regionByID[id]
+		}
+		table.SetCell(row, 1, tview.NewTableCell(region).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+	}
+}
+
 // WrapTableInFlex wraps table in full-width flex container
 func WrapTableInFlex(table *tview.Table) tview.Primitive {
 	// Create a flex container that forces the table to use full width
@@ -266,6 +280,9 @@ func CreateDetailViewWithInstructions(detailView *tview.TextView) *tview.Flex {
 	return flex
 }
 
+// ReadOnlyBadge marks the mode line while write operations are refused
+const ReadOnlyBadge = "[black:red] RO [-:-]"
+
 // CreateModeLine creates a mode line component showing current profile and shortcuts
 func CreateModeLine(profileName string) *tview.TextView {
 	modeLineText := fmt.Sprintf(" Profile: %s | Press 'O' to switch profile ", profileName)
@@ -311,48 +328,65 @@ func UpdateModeLineWithPageInfo(modeLine *tview.TextView, profileName string, pa
 // GetPageShortcuts returns the shortcut help text for a given page
 func GetPageShortcuts(pageName string) string {
 	shortcuts := map[string]string{
-		PageMainMenu: "Enter: Select current service | j/k: Navigate | Q: Quit | O: Switch profile",
+		PageMainMenu: "Enter: Select current service | j/k: Navigate | Q: Quit | O: Switch profile | R: Switch region",
 
 		// ECS related pages
-		PageEcsList:   "j/k: Navigate | Enter: Details | /: Search | n/N: Next/Prev search | yy: Copy | q: Back | O: Profile",
+		PageEcsList:   "j/k: Navigate | Enter: Details | g: Security groups | c: Check reachability | S/X/B: Start/Stop/Reboot | M: Modify | /: Search | yy: Copy | r: Refresh | q: Back",
 		PageEcsDetail: "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
 
 		// Security Groups related pages
-		PageSecurityGroups:         "j/k: Navigate | Enter: Rules | s: Instances | /: Search | yy: Copy | q: Back",
+		PageSecurityGroups:         "j/k: Navigate | Enter: Rules | s: Instances | a: Audit | d: Diff | /: Search | yy: Copy | r: Refresh | q: Back",
 		PageSecurityGroupDetail:    "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
-		PageSecurityGroupRules:     "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageSecurityGroupInstances: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageInstanceSecurityGroups: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageSecurityGroupRules:     "j/k: Navigate | A: Add | E: Edit | D: Revoke | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageSecurityGroupInstances: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageSecurityGroupAudit:     "j/k: Navigate | Enter: Rule | s: Instances | o: Sort | i: Reverse | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageReachability:           "j/k: Navigate | Enter: Rule | s: Instances | /: Search | yy: Copy | r: Check again | q: Back | Q: Quit",
+		PageSecurityGroupDiff:      "j/k: Navigate | C: Clone left into right | w: Swap sides | h: Hide same | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageInstanceSecurityGroups: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
 
 		// DNS related pages
-		PageDnsDomains: "j/k: Navigate | Enter: Records | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageDnsRecords: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageDnsDomains:    "j/k: Navigate | Enter: Records | X: Export zone | I: Import zone | /: Search | yy: Copy | r: Refresh | q: Back",
+		PageDnsRecords:    "j/k: Navigate | A: Add | E: Edit | D: Delete | T: Enable/Disable | /: Search | yy: Copy | r: Refresh | q: Back",
+		PageDnsZoneImport: "j/k: Navigate | A: Apply changes | /: Search | q: Back | Q: Quit",
 
 		// SLB related pages
-		PageSlbList:                       "j/k: Navigate | Enter: Details | l: Listeners | v: VServer Groups | /: Search | yy: Copy | q: Back",
+		PageSlbList:                       "j/k: Navigate | Enter: Details | l: Listeners | v: VServer Groups | /: Search | yy: Copy | r: Refresh | q: Back",
 		PageSlbDetail:                     "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
-		PageSlbListeners:                  "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageSlbVServerGroups:              "j/k: Navigate | Enter: Backend Servers | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageSlbVServerGroupBackendServers: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageSlbListeners:                  "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageSlbListenerDetail:             "j/k: Navigate | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageSlbVServerGroups:              "j/k: Navigate | Enter: Backend Servers | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageSlbVServerGroupBackendServers: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
 
 		// OSS related pages
-		PageOssBuckets: "j/k: Navigate | Enter: Objects | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageOssObjects: "j/k: Navigate | Enter: Details | [/]: Prev/Next page | 0: First page | /: Search | yy: Copy | q: Back",
+		PageOssBuckets:       "j/k: Navigate | Enter: Objects | I: Configuration | u: Usage | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageOssObjects:       "j/k: Navigate | Enter: Open folder/Details | P: Preview | V: Pager | D: Download | U: Upload | S: Share URL | H: Versions | F: Find | u: Usage | [/]: Prev/Next page | 0: First page | /: Search | yy: Copy | r: Refresh | q: Up/Back",
+		PageOssBucketConfig:  "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | r: Refresh | Q: Quit",
+		PageOssSearch:        "j/k: Navigate | Enter: Details | V: Pager | D: Download | S: Share URL | c: Stop search | F: New search | /: Search | q: Back (stops search)",
+		PageOssUsage:         "j/k: Navigate | Enter: Usage of folder | c: Stop counting | /: Search | q: Up/Back",
+		PageOssVersions:      "j/k: Navigate | D: Download version | C: Restore as current | /: Search | r: Refresh | q: Back",
+		PageOssObjectPreview: "j/k: Scroll | v: View whole object in pager | q/Esc: Back | Q: Quit",
 
 		// RDS related pages
-		PageRdsList:      "j/k: Navigate | Enter: Details | D: Databases | A: Accounts | /: Search | yy: Copy | q: Back",
-		PageRdsDetail:    "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
-		PageRdsDatabases: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageRdsAccounts:  "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageRdsList:       "j/k: Navigate | Enter: Details | D: Databases | A: Accounts | C: Connections | W: Whitelists | P: Parameters | B: Backups | o: Read-only | L: Slow logs | /: Search | yy: Copy | r: Refresh | q: Back",
+		PageRdsDetail:     "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
+		PageRdsDatabases:  "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsAccounts:   "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsNetInfo:    "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsWhitelists: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsParameters: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsBackups:    "j/k: Navigate | Enter: Details | P: Backup policy | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsReadOnly:   "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsSlowLogs:   "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRdsItemDetail: "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
 
 		// Redis related pages
-		PageRedisList:     "j/k: Navigate | Enter: Details | A: Accounts | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageRedisAccounts: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageRedisList:     "j/k: Navigate | Enter: Details | A: Accounts | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRedisAccounts: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
 
 		// RocketMQ related pages
-		PageRocketMQList:   "j/k: Navigate | Enter: Details | T: Topics | G: Groups | /: Search | yy: Copy | q: Back",
-		PageRocketMQTopics: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
-		PageRocketMQGroups: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | q: Back | Q: Quit",
+		PageRocketMQList:   "j/k: Navigate | Enter: Details | T: Topics | G: Groups | /: Search | yy: Copy | r: Refresh | q: Back",
+		PageRocketMQTopics: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
+		PageRocketMQGroups: "j/k: Navigate | Enter: Details | /: Search | yy: Copy | r: Refresh | q: Back | Q: Quit",
 
 		// Detail pages (using string literals for non-constant page names)
 		"ossObjectDetail":     "q/Esc: Back | yy: Copy JSON | e: Edit | v: View in pager | /: Search | n/N: Next/Prev | Q: Quit",
@@ -428,3 +462,14 @@ func UpdateModeLineWithPageInfoAndShortcuts(modeLine *tview.TextView, profileNam
 	modeLineText := leftText + leftSpacingStr + middleText + rightSpacingStr + rightText
 	modeLine.SetText(modeLineText)
 }
+
+// SpinnerFrames are the animation frames of the loading indicator
+var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// UpdateModeLineWithLoading shows a loading indicator in the mode line. frame selects the
+// spinner frame and wraps around.
+func UpdateModeLineWithLoading(modeLine *tview.TextView, profileName string, frame int, label string) {
+	spinner := SpinnerFrames[frame%len(SpinnerFrames)]
+	modeLineText := fmt.Sprintf(" Profile: %s | %s Loading %s... (Esc to cancel) ", profileName, spinner, label)
+	modeLine.SetText(modeLineText)
+}
@@ -10,22 +10,43 @@ const (
 	PageSecurityGroupRules            = "securityGroupRules"
 	PageSecurityGroupInstances        = "securityGroupInstances"
 	PageInstanceSecurityGroups        = "instanceSecurityGroups"
+	PageSecurityGroupAudit            = "securityGroupAudit"
+	PageReachability                  = "reachability"
+	PageSecurityGroupDiff             = "securityGroupDiff"
 	PageDnsDomains                    = "dnsDomains"
 	PageDnsRecords                    = "dnsRecords"
+	PageDnsZoneImport                 = "dnsZoneImport"
 	PageSlbList                       = "slbList"
 	PageSlbDetail                     = "slbDetail"
 	PageSlbListeners                  = "slbListeners"
+	PageSlbListenerDetail             = "slbListenerDetail"
 	PageSlbVServerGroups              = "slbVServerGroups"
 	PageSlbVServerGroupBackendServers = "slbVServerGroupBackendServers"
 	PageOssBuckets                    = "ossBuckets"
+	PageOssBucketConfig               = "ossBucketConfig"
 	PageOssObjects                    = "ossObjects"
+	PageOssObjectPreview              = "ossObjectPreview"
+	PageOssSearch                     = "ossSearch"
+	PageOssSearchDetail               = "ossSearchDetail"
+	PageOssUsage                      = "ossUsage"
+	PageOssVersions                   = "ossVersions"
 	PageRdsList                       = "rdsList"
 	PageRdsDetail                     = "rdsDetail"
 	PageRdsDatabases                  = "rdsDatabases"
 	PageRdsAccounts                   = "rdsAccounts"
+	PageRdsNetInfo                    = "rdsNetInfo"
+	PageRdsWhitelists                 = "rdsWhitelists"
+	PageRdsParameters                 = "rdsParameters"
+	PageRdsBackups                    = "rdsBackups"
+	PageRdsReadOnly                   = "rdsReadOnly"
+	PageRdsSlowLogs                   = "rdsSlowLogs"
+	PageRdsItemDetail                 = "rdsItemDetail"
 	PageRedisList                     = "redisList"
 	PageRedisAccounts                 = "redisAccounts"
 	PageRocketMQList                  = "rocketmqList"
 	PageRocketMQTopics                = "rocketmqTopics"
 	PageRocketMQGroups                = "rocketmqGroups"
 )
+
+// AllRegions is the region selection that lists resources from every region
+const AllRegions = "all"
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// ShowDnsRecordForm shows a form for adding or editing a DNS record, filled with spec.
+// Save validates the record with service.ValidateDomainRecord: an invalid record keeps the
+// form open with the problem in its title, a valid one is passed to onSubmit. Cancel or Esc
+// calls onCancel.
+func ShowDnsRecordForm(pages *tview.Pages, app *tview.Application, title string, spec service.DomainRecordSpec, onSubmit func(service.DomainRecordSpec), onCancel func()) {
+	form := tview.NewForm()
+	var closeDialog func()
+	cancel := func() {
+		closeDialog()
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+
+	rrField := tview.NewInputField().SetLabel("RR").SetText(spec.RR)
+	typeField := tview.NewDropDown().SetLabel("Type").
+		SetOptions(service.DomainRecordTypes, nil).
+		SetCurrentOption(indexOf(service.DomainRecordTypes, spec.Type))
+	valueField := tview.NewInputField().SetLabel("Value").SetText(spec.Value)
+	ttlField := tview.NewInputField().SetLabel("TTL").
+		SetText(strconv.FormatInt(spec.TTL, 10)).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+
+	lines := service.DomainRecordLines
+	if spec.Line != "" && indexOf(lines, spec.Line) < 0 {
+		lines = append(append([]string(nil), lines...), spec.Line) // Keep a line only the account knows
+	}
+	lineField := tview.NewDropDown().SetLabel("Line").
+		SetOptions(lines, nil).
+		SetCurrentOption(max(indexOf(lines, spec.Line), 0))
+
+	priority := ""
+	if spec.Priority > 0 {
+		priority = strconv.FormatInt(spec.Priority, 10)
+	}
+	priorityField := tview.NewInputField().SetLabel("Priority (MX)").
+		SetText(priority).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+
+	form.AddFormItem(rrField).
+		AddFormItem(typeField).
+		AddFormItem(valueField).
+		AddFormItem(ttlField).
+		AddFormItem(lineField).
+		AddFormItem(priorityField)
+
+	form.AddButton("Save", func() {
+		entered := service.DomainRecordSpec{
+			RR:    strings.TrimSpace(rrField.GetText()),
+			Value: strings.TrimSpace(valueField.GetText()),
+		}
+		_, entered.Type = typeField.GetCurrentOption()
+		_, entered.Line = lineField.GetCurrentOption()
+		entered.TTL, _ = strconv.ParseInt(ttlField.GetText(), 10, 64)
+		if entered.Type == "MX" {
+			entered.Priority, _ = strconv.ParseInt(priorityField.GetText(), 10, 64)
+		}
+
+		if err := service.ValidateDomainRecord(entered); err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%s[-] ", tview.Escape(err.Error())))
+			return
+		}
+		closeDialog()
+		if onSubmit != nil {
+			onSubmit(entered)
+		}
+	})
+	form.AddButton("Cancel", cancel)
+	form.SetCancelFunc(cancel)
+
+	form.SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(tcell.ColorDefault)
+
+	closeDialog = showFormPage(pages, app, "dnsRecordForm", form, 17)
+}
+
+// indexOf returns the index of value in values, or -1 if it is missing
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -21,27 +24,78 @@ func ShowErrorModal(pages *tview.Pages, app *tview.Application, message string,
 	app.SetFocus(modal)
 }
 
+// ShowMessageModal shows an informational message with an OK button, e.g. the outcome of
+// an action that has no page of its own
+func ShowMessageModal(pages *tview.Pages, app *tview.Application, message string, onDone func()) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"OK"}).
+		SetBackgroundColor(tcell.ColorDefault).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("messageModal")
+			if onDone != nil {
+				onDone()
+			}
+		})
+	pages.AddPage("messageModal", modal, false, true)
+	app.SetFocus(modal)
+}
+
 // ShowProfileSelectionDialog creates and shows a profile selection dialog
 func ShowProfileSelectionDialog(pages *tview.Pages, app *tview.Application, profiles []string, currentProfile string, onSelect func(string), onCancel func()) {
-	list := tview.NewList()
-
-	// Add profiles to the list
-	for _, profile := range profiles {
-		profileName := profile // Capture for closure
-		displayText := profileName
+	labels := make([]string, len(profiles))
+	for i, profileName := range profiles {
+		labels[i] = profileName
 		if profileName == currentProfile {
-			displayText = profileName + " (current)"
+			labels[i] = profileName + " (current)"
+		}
+	}
+	showSelectionDialog(pages, app, "profileDialog", "Select Profile", labels, profiles, onSelect, onCancel)
+}
+
+// ShowRegionSelectionDialog creates and shows a region selection dialog. The first entry
+// selects AllRegions; currentRegion is AllRegions when the all-regions mode is active.
+func ShowRegionSelectionDialog(pages *tview.Pages, app *tview.Application, regions []ecs.Region, currentRegion string, onSelect func(string), onCancel func()) {
+	labels := []string{"All regions"}
+	values := []string{AllRegions}
+	for _, region := range regions {
+		label := region.RegionId
+		if region.LocalName != "" {
+			label = fmt.Sprintf("%s (%s)", region.RegionId, region.LocalName)
+		}
+		labels = append(labels, label)
+		values = append(values, region.RegionId)
+	}
+	for i, value := range values {
+		if value == currentRegion {
+			labels[i] += " (current)"
 		}
-		list.AddItem(displayText, "", 0, func() {
-			pages.RemovePage("profileDialog")
+	}
+	showSelectionDialog(pages, app, "regionDialog", "Select Region", labels, values, onSelect, onCancel)
+}
+
+// showSelectionDialog shows a centered list dialog on its own page. Selecting an item
+// calls onSelect with the corresponding value; Esc or q calls onCancel.
+func showSelectionDialog(pages *tview.Pages, app *tview.Application, pageName, title string, labels, values []string, onSelect func(string), onCancel func()) {
+	list := tview.NewList()
+
+	// Set up global input capture for this dialog
+	originalInputCapture := app.GetInputCapture()
+
+	// Add items to the list
+	for i, label := range labels {
+		value := values[i] // Capture for closure
+		list.AddItem(label, "", 0, func() {
+			pages.RemovePage(pageName)
+			app.SetInputCapture(originalInputCapture) // Restore original capture
 			if onSelect != nil {
-				onSelect(profileName)
+				onSelect(value)
 			}
 		})
 	}
 
 	list.SetBorder(true).
-		SetTitle("Select Profile").
+		SetTitle(title).
 		SetBackgroundColor(tcell.ColorDefault)
 
 	// Set up j/k navigation and cancel keys
@@ -77,32 +131,154 @@ func ShowProfileSelectionDialog(pages *tview.Pages, app *tview.Application, prof
 			AddItem(nil, 0, 1, false), 0, 2, true).
 		AddItem(nil, 0, 1, false)
 
-	pages.AddPage("profileDialog", flex, true, true)
+	pages.AddPage(pageName, flex, true, true)
 
-	// Set up global input capture for this dialog
-	originalInputCapture := app.GetInputCapture()
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		// Only handle events when the profile dialog is active
-		if pages.HasPage("profileDialog") {
-			// Handle Escape key
-			if event.Key() == tcell.KeyEscape {
-				pages.RemovePage("profileDialog")
+		// Only handle events when the dialog is active
+		if pages.HasPage(pageName) {
+			// Handle Escape and 'q' keys
+			if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+				pages.RemovePage(pageName)
 				app.SetInputCapture(originalInputCapture) // Restore original capture
 				if onCancel != nil {
 					onCancel()
 				}
 				return nil
 			}
+		}
 
-			// Handle 'q' key
-			if event.Rune() == 'q' {
-				pages.RemovePage("profileDialog")
-				app.SetInputCapture(originalInputCapture) // Restore original capture
-				if onCancel != nil {
-					onCancel()
+		// Pass through to original handler or default behavior
+		if originalInputCapture != nil {
+			return originalInputCapture(event)
+		}
+		return event
+	})
+
+	app.SetFocus(list)
+}
+
+// ShowConfirmModal asks for confirmation of an action. The modal offers the given actions
+// and a Cancel button; choosing an action calls onConfirm with its label, while Cancel or
+// Esc calls onCancel.
+func ShowConfirmModal(pages *tview.Pages, app *tview.Application, message string, actions []string, onConfirm func(action string), onCancel func()) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons(append(append([]string(nil), actions...), "Cancel")).
+		SetBackgroundColor(tcell.ColorDefault).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.RemovePage("confirmModal")
+			if buttonIndex >= 0 && buttonIndex < len(actions) {
+				if onConfirm != nil {
+					onConfirm(buttonLabel)
 				}
-				return nil
+				return
+			}
+			if onCancel != nil {
+				onCancel()
+			}
+		})
+	pages.AddPage("confirmModal", modal, false, true)
+	app.SetFocus(modal)
+}
+
+// ShowFormDialog shows a centered form with one input field per label, filled with the
+// given values. Save calls onSubmit with the entered values; Cancel or Esc calls onCancel.
+func ShowFormDialog(pages *tview.Pages, app *tview.Application, title string, labels, values []string, onSubmit func([]string), onCancel func()) {
+	form := tview.NewForm()
+	var closeDialog func()
+	cancel := func() {
+		closeDialog()
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+
+	for i, label := range labels {
+		form.AddInputField(label, values[i], 0, nil, nil)
+	}
+	form.AddButton("Save", func() {
+		entered := make([]string, len(labels))
+		for i := range labels {
+			entered[i] = form.GetFormItem(i).(*tview.InputField).GetText()
+		}
+		closeDialog()
+		if onSubmit != nil {
+			onSubmit(entered)
+		}
+	})
+	form.AddButton("Cancel", cancel)
+	form.SetCancelFunc(cancel)
+
+	form.SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(tcell.ColorDefault)
+
+	closeDialog = showFormPage(pages, app, "formDialog", form, 2*len(labels)+5)
+}
+
+// ShowTypedConfirmModal asks for confirmation of a destructive action by having the user
+// type expected, usually the ID of the resource. The action buttons do nothing until the
+// typed text matches; choosing one then calls onConfirm with its label, while Cancel or
+// Esc calls onCancel.
+func ShowTypedConfirmModal(pages *tview.Pages, app *tview.Application, message, expected string, actions []string, onConfirm func(action string), onCancel func()) {
+	form := tview.NewForm()
+	var closeDialog func()
+	cancel := func() {
+		closeDialog()
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+
+	input := tview.NewInputField().SetLabel(fmt.Sprintf("Type %s to confirm ", expected))
+	form.AddTextView("", message, 0, 4, true, false)
+	form.AddFormItem(input)
+	for _, action := range actions {
+		action := action // Capture for closure
+		form.AddButton(action, func() {
+			if input.GetText() != expected {
+				form.SetTitle(fmt.Sprintf(" [red]Type %s exactly to confirm[-] ", expected))
+				app.SetFocus(input)
+				return
+			}
+			closeDialog()
+			if onConfirm != nil {
+				onConfirm(action)
 			}
+		})
+	}
+	form.AddButton("Cancel", cancel)
+	form.SetCancelFunc(cancel)
+	form.SetFocus(1) // Start in the input field
+
+	form.SetBorder(true).
+		SetTitle(" Confirm ").
+		SetTitleColor(tcell.ColorRed).
+		SetBackgroundColor(tcell.ColorDefault)
+
+	closeDialog = showFormPage(pages, app, "typedConfirmModal", form, 12)
+}
+
+// showFormPage shows a fully built form centered on its own page and returns a function
+// that removes it again. While the page is open, keys go to the form rather than the global shortcuts,
+// so that typing q or O in a field does not navigate away.
+func showFormPage(pages *tview.Pages, app *tview.Application, pageName string, form *tview.Form, height int) (closeDialog func()) {
+	originalInputCapture := app.GetInputCapture()
+
+	// Create a flex container to center the form
+	flex := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, height, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	pages.AddPage(pageName, flex, true, true)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if pages.HasPage(pageName) && event.Key() != tcell.KeyCtrlC {
+			return event // The form handles Esc through its cancel func
 		}
 
 		// Pass through to original handler or default behavior
@@ -112,5 +288,10 @@ func ShowProfileSelectionDialog(pages *tview.Pages, app *tview.Application, prof
 		return event
 	})
 
-	app.SetFocus(list)
+	app.SetFocus(form)
+
+	return func() {
+		pages.RemovePage(pageName)
+		app.SetInputCapture(originalInputCapture) // Restore original capture
+	}
 }
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// progressBarWidth is the number of cells of the bar drawn by ProgressDialog
+const progressBarWidth = 30
+
+// ProgressDialog is a modal showing the progress of a long-running transfer
+type ProgressDialog struct {
+	pages *tview.Pages
+	modal *tview.Modal
+	title string
+}
+
+// ShowProgressDialog shows a progress modal titled e.g. "Downloading app.log". Its Cancel
+// button, or Esc, calls onCancel; the dialog stays open until Close is called.
+func ShowProgressDialog(pages *tview.Pages, app *tview.Application, title string, onCancel func()) *ProgressDialog {
+	d := &ProgressDialog{pages: pages, title: title}
+	d.modal = tview.NewModal().
+		AddButtons([]string{"Cancel"}).
+		SetBackgroundColor(tcell.ColorDefault).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if onCancel != nil {
+				onCancel()
+			}
+		})
+	d.Update(0, 0)
+	pages.AddPage("progressDialog", d.modal, false, true)
+	app.SetFocus(d.modal)
+	return d
+}
+
+// Update redraws the bar for the given progress. It must be called on the UI goroutine.
+func (d *ProgressDialog) Update(transferred, total int64) {
+	d.modal.SetText(fmt.Sprintf("%s\n\n%s", d.title, FormatProgress(transferred, total)))
+}
+
+// Close removes the dialog
+func (d *ProgressDialog) Close() {
+	d.pages.RemovePage("progressDialog")
+}
+
+// FormatProgress renders progress as a bar followed by the percentage and sizes, e.g.
+// "[#######-------]  50%  1.0 MiB / 2.0 MiB"
+func FormatProgress(transferred, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("[%s]  %s", strings.Repeat("-", progressBarWidth), FormatBytes(transferred))
+	}
+	transferred = min(transferred, total)
+	filled := int(transferred * progressBarWidth / total)
+	return fmt.Sprintf("[%s%s] %3d%%  %s / %s",
+		strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled),
+		transferred*100/total, FormatBytes(transferred), FormatBytes(total))
+}
+
+// FormatBytes formats a size in bytes with a binary unit, e.g. "512 B" or "1.5 MiB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes parses a size with an optional binary unit, e.g. "512", "10K", "1.5 GiB" or
+// "2GB"; K, KB and KiB all mean 1024 bytes
+func ParseBytes(text string) (int64, error) {
+	number := strings.TrimSpace(text)
+	unit := strings.TrimLeft(number, "0123456789.")
+	number = strings.TrimSpace(strings.TrimSuffix(number, unit))
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+
+	unit = strings.ToUpper(strings.TrimSpace(unit))
+	unit = strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I")
+	exp := 0
+	if unit != "" {
+		exp = strings.Index("KMGTPE", unit) + 1
+		if len(unit) != 1 || exp == 0 {
+			return 0, fmt.Errorf("invalid size %q: unknown unit", text)
+		}
+	}
+	return int64(value * math.Pow(1024, float64(exp))), nil
+}
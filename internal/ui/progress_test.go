@@ -0,0 +1,44 @@
+package ui
+
+import "testing"
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int64
+		wantErr bool
+	}{
+		{text: "512", want: 512},
+		{text: "0", want: 0},
+		{text: "10K", want: 10 << 10},
+		{text: "1kb", want: 1 << 10},
+		{text: "1KiB", want: 1 << 10},
+		{text: " 3 M ", want: 3 << 20},
+		{text: "1.5 GiB", want: 3 << 29},
+		{text: "2GB", want: 2 << 30},
+		{text: "1T", want: 1 << 40},
+		{text: "", wantErr: true},
+		{text: "abc", wantErr: true},
+		{text: "-1", wantErr: true},
+		{text: "10X", wantErr: true},
+		{text: "10KM", wantErr: true},
+		{text: "1.2.3K", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseBytes(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %d, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// rdsSQLTextWidth is how much of a slow query is shown in the slow log table
+const rdsSQLTextWidth = 60
+
+// RdsWhitelistReference identifies an IP whitelist group of an instance: the same group
+// name can exist once for IPv4 and once for IPv6
+func RdsWhitelistReference(group rds.DBInstanceIPArray) string {
+	return group.DBInstanceIPArrayName + "/" + group.SecurityIPType
+}
+
+// RdsSlowLogReference identifies a slow query statistic: a SQL template on one day
+func RdsSlowLogReference(log rds.SQLSlowLog) string {
+	id := log.SQLHASH
+	if id == "" {
+		id = log.SQLText
+	}
+	return log.CreateTime + "/" + log.DBName + "/" + id
+}
+
+// CreateRdsNetInfoView creates the list of the connection strings of an RDS instance
+func CreateRdsNetInfoView(netInfos []rds.DBInstanceNetInfo, instanceId string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Connection String", "Port", "IP Address", "Network", "Type", "VPC", "VSwitch"}
+	CreateTableHeaders(table, headers)
+
+	if len(netInfos) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No connection strings found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, info := range netInfos {
+			color := tcell.ColorWhite
+			if info.IPType == "Public" {
+				color = tcell.ColorYellow
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(info.ConnectionString).SetTextColor(color).SetReference(info.ConnectionString).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(info.Port).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(info.IPAddress).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(info.IPType).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(info.ConnectionStringType).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(info.VPCId).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 6, tview.NewTableCell(info.VSwitchId).SetTextColor(color).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("Connection Strings for RDS Instance: %s", instanceId)).SetBorder(true)
+	return table
+}
+
+// CreateRdsWhitelistView creates the list of the IP whitelist groups of an RDS instance.
+// Groups open to any address are shown in red.
+func CreateRdsWhitelistView(groups []rds.DBInstanceIPArray, instanceId string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Group", "IP Type", "Network", "Attribute", "Entries", "Addresses"}
+	CreateTableHeaders(table, headers)
+
+	if len(groups) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No IP whitelists found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, group := range groups {
+			var addresses []string
+			if group.SecurityIPList != "" {
+				addresses = strings.Split(group.SecurityIPList, ",")
+			}
+			color := tcell.ColorWhite
+			for _, address := range addresses {
+				if address == "0.0.0.0/0" || address == "::/0" {
+					color = tcell.ColorRed
+				}
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(group.DBInstanceIPArrayName).SetTextColor(color).SetReference(RdsWhitelistReference(group)).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(group.SecurityIPType).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(group.WhitelistNetworkType).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(group.DBInstanceIPArrayAttribute).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(fmt.Sprintf("%d", len(addresses))).SetTextColor(color).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(strings.Join(addresses, ", ")).SetTextColor(color).SetMaxWidth(60).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("IP Whitelists for RDS Instance: %s", instanceId)).SetBorder(true)
+	return table
+}
+
+// CreateRdsParametersView creates the list of the parameters of an RDS instance. Values
+// changed from the default are yellow; values changed but waiting for a restart are orange.
+func CreateRdsParametersView(parameters []service.RDSParameter, instanceId string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Parameter", "Value", "Pending Value", "Default", "Range", "Description"}
+	CreateTableHeaders(table, headers)
+
+	modified, pending := 0, 0
+	if len(parameters) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No parameters found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, parameter := range parameters {
+			color := tcell.ColorWhite
+			if parameter.Modified() {
+				color = tcell.ColorYellow
+				modified++
+			}
+			if parameter.PendingValue != "" {
+				color = tcell.ColorOrange
+				pending++
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(parameter.Name).SetTextColor(color).SetReference(parameter.Name).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(parameter.Value).SetTextColor(color).SetMaxWidth(30).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(parameter.PendingValue).SetTextColor(color).SetMaxWidth(30).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(parameter.DefaultValue).SetTextColor(color).SetMaxWidth(30).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(parameter.ValueRange).SetTextColor(color).SetMaxWidth(30).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(parameter.Description).SetTextColor(color).SetMaxWidth(60).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("Parameters for RDS Instance: %s (%d modified, %d pending restart)", instanceId, modified, pending)).SetBorder(true)
+	return table
+}
+
+// CreateRdsBackupsView creates the list of the backup sets of an RDS instance, with a
+// summary of its backup policy in the title. Failed backups are red.
+func CreateRdsBackupsView(backups []rds.Backup, policy *rds.DescribeBackupPolicyResponse, instanceId string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Backup ID", "Start", "End", "Method", "Type", "Mode", "Status", "Size", "Location"}
+	CreateTableHeaders(table, headers)
+
+	if len(backups) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No backups found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, backup := range backups {
+			color := tcell.ColorWhite
+			if backup.BackupStatus != "Success" {
+				color = tcell.ColorRed
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(backup.BackupId).SetTextColor(color).SetReference(backup.BackupId).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(backup.BackupStartTime).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(backup.BackupEndTime).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(backup.BackupMethod).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(backup.BackupType).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(backup.BackupMode).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 6, tview.NewTableCell(backup.BackupStatus).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 7, tview.NewTableCell(FormatBytes(backup.BackupSize)).SetTextColor(color).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 8, tview.NewTableCell(backup.BackupLocation).SetTextColor(color).SetExpansion(1))
+		}
+	}
+
+	title := fmt.Sprintf("Backups for RDS Instance: %s", instanceId)
+	if policy != nil {
+		title = fmt.Sprintf("Backups for RDS Instance: %s (kept %d days, %s at %s, log backup %s)", instanceId,
+			policy.BackupRetentionPeriod, policy.PreferredBackupPeriod, policy.PreferredBackupTime, strings.ToLower(policy.BackupLog))
+	}
+	table.SetTitle(title).SetBorder(true)
+	return table
+}
+
+// CreateRdsReadOnlyView creates the list of the read-only instances of a primary RDS instance
+func CreateRdsReadOnlyView(instances []rds.DBInstance, instanceId string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Instance ID", "Class", "Status", "Zone", "Connection String", "Description"}
+	CreateTableHeaders(table, headers)
+
+	if len(instances) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No read-only instances found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, inst := range instances {
+			color := tcell.ColorWhite
+			if inst.DBInstanceStatus != "Running" {
+				color = tcell.ColorYellow
+			}
+			table.SetCell(r+1, 0, tview.NewTableCell(inst.DBInstanceId).SetTextColor(color).SetReference(inst.DBInstanceId).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(inst.DBInstanceClass).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(inst.DBInstanceStatus).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(inst.ZoneId).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(inst.ConnectionString).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(inst.DBInstanceDescription).SetTextColor(color).SetMaxWidth(40).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("Read-only Instances of RDS Instance: %s", instanceId)).SetBorder(true)
+	return table
+}
+
+// CreateRdsSlowLogsView creates the list of the daily slow query statistics of an RDS
+// instance over the given number of days
+func CreateRdsSlowLogsView(logs []rds.SQLSlowLog, instanceId string, days int) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Day", "Database", "Executions", "Total Time (s)", "Max Time (s)", "Avg Time (s)", "Max Rows Examined", "SQL"}
+	CreateTableHeaders(table, headers)
+
+	if len(logs) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No slow queries found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, log := range logs {
+			table.SetCell(r+1, 0, tview.NewTableCell(strings.TrimSuffix(log.CreateTime, "Z")).SetTextColor(tcell.ColorWhite).SetReference(RdsSlowLogReference(log)).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(log.DBName).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(fmt.Sprintf("%d", service.SlowLogExecutions(log))).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(fmt.Sprintf("%d", service.SlowLogTotalTime(log))).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(fmt.Sprintf("%d", log.MaxExecutionTime)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(fmt.Sprintf("%d", log.AvgExecutionTime)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 6, tview.NewTableCell(fmt.Sprintf("%d", log.ParseMaxRowCount)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+			table.SetCell(r+1, 7, tview.NewTableCell(log.SQLText).SetTextColor(tcell.ColorWhite).SetMaxWidth(rdsSQLTextWidth).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("Slow Queries of RDS Instance: %s (last %d days, longest total time first)", instanceId, days)).SetBorder(true)
+	return table
+}
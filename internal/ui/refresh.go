@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TableSnapshot records the cell texts of a list table by the resource ID stored as the
+// reference of the first cell of each row
+type TableSnapshot map[string][]string
+
+// SnapshotTable records the current cell texts of a list table
+func SnapshotTable(table *tview.Table) TableSnapshot {
+	snapshot := make(TableSnapshot)
+	for row := 1; row < table.GetRowCount(); row++ {
+		id, ok := rowReference(table, row)
+		if !ok {
+			continue
+		}
+		texts := make([]string, table.GetColumnCount())
+		for col := range texts {
+			if cell := table.GetCell(row, col); cell != nil {
+				texts[col] = cell.Text
+			}
+		}
+		snapshot[id] = texts
+	}
+	return snapshot
+}
+
+// HighlightTableChanges compares a freshly rendered list table with a snapshot taken before
+// the refresh. Changed cells show the transition, e.g. "Stopping → Stopped", and rows that
+// were not there before are shown in green.
+func HighlightTableChanges(table *tview.Table, previous TableSnapshot) {
+	for row := 1; row < table.GetRowCount(); row++ {
+		id, ok := rowReference(table, row)
+		if !ok {
+			continue
+		}
+		before, existed := previous[id]
+		for col := 0; col < table.GetColumnCount(); col++ {
+			cell := table.GetCell(row, col)
+			if cell == nil {
+				continue
+			}
+			switch {
+			case !existed:
+				cell.SetTextColor(tcell.ColorGreen)
+			case col < len(before) && before[col] != cell.Text:
+				cell.SetText(before[col] + " → " + cell.Text).SetTextColor(tcell.ColorOrange)
+			}
+		}
+	}
+}
+
+// SelectRowByReference selects the row whose first cell references id. It returns false
+// if there is no such row.
+func SelectRowByReference(table *tview.Table, id string) bool {
+	for row := 1; row < table.GetRowCount(); row++ {
+		if ref, ok := rowReference(table, row); ok && ref == id {
+			table.Select(row, 0)
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedReference returns the resource ID of the selected row of a list table
+func SelectedReference(table *tview.Table) (string, bool) {
+	row, _ := table.GetSelection()
+	if row < 1 {
+		return "", false
+	}
+	return rowReference(table, row)
+}
+
+// rowReference returns the resource ID stored as the reference of the first cell of a row
+func rowReference(table *tview.Table, row int) (string, bool) {
+	cell := table.GetCell(row, 0)
+	if cell == nil {
+		return "", false
+	}
+	id, ok := cell.GetReference().(string)
+	return id, ok
+}
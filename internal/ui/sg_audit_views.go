@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// SecurityGroupFindingReference identifies a finding of the security group audit: a kind
+// of problem with a rule, or with the group itself
+func SecurityGroupFindingReference(finding service.SecurityGroupFinding) string {
+	return finding.SecurityGroupId + "/" + finding.RuleId + "/" + finding.Kind
+}
+
+// CreateSecurityGroupAuditView creates the findings table of the security group audit,
+// ordered as given. High severity findings are shown in red and medium ones in yellow.
+func CreateSecurityGroupAuditView(findings []service.SecurityGroupFinding, sortBy string, reverse bool) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Severity", "Finding", "Security Group", "Rule", "Detail", "Instances"}
+	CreateTableHeaders(table, headers)
+
+	if len(findings) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No findings: every security group passed the audit.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, finding := range findings {
+			color := tcell.ColorWhite
+			switch finding.Severity {
+			case service.SeverityHigh:
+				color = tcell.ColorRed
+			case service.SeverityMedium:
+				color = tcell.ColorYellow
+			}
+			group := finding.SecurityGroupId
+			if finding.SecurityGroupName != "" {
+				group = fmt.Sprintf("%s (%s)", finding.SecurityGroupId, finding.SecurityGroupName)
+			}
+			rule := finding.Rule
+			if finding.RuleId != "" {
+				rule = fmt.Sprintf("%s: %s", finding.RuleId, finding.Rule)
+			}
+
+			table.SetCell(r+1, 0, tview.NewTableCell(strings.ToUpper(finding.Severity)).SetTextColor(color).SetReference(SecurityGroupFindingReference(finding)).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(finding.Kind).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(group).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(rule).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(finding.Detail).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(strconv.Itoa(len(finding.InstanceIds))).SetTextColor(color).SetExpansion(1))
+		}
+	}
+
+	order := "ascending"
+	if reverse {
+		order = "reversed"
+	}
+	table.SetTitle(fmt.Sprintf("Security Group Audit: %d findings (sorted by %s, %s)", len(findings), sortBy, order)).SetBorder(true)
+	return table
+}
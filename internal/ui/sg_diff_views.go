@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// ShowSecurityGroupCompareDialog asks for the security group to compare a group with: its
+// profile, one of profiles, its region and its ID, filled with target. An ID that is not
+// a security group keeps the dialog open with the problem in its title; otherwise Compare
+// calls onSubmit with the entered group. Cancel or Esc calls onCancel.
+func ShowSecurityGroupCompareDialog(pages *tview.Pages, app *tview.Application, title string, profiles []string, target service.SecurityGroupRef, onSubmit func(service.SecurityGroupRef), onCancel func()) {
+	form := tview.NewForm()
+	var closeDialog func()
+	cancel := func() {
+		closeDialog()
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+
+	profileField := tview.NewDropDown().SetLabel("Profile").
+		SetOptions(profiles, nil).
+		SetCurrentOption(max(indexOf(profiles, target.Profile), 0))
+	regionField := tview.NewInputField().SetLabel("Region").SetText(target.Region)
+	groupField := tview.NewInputField().SetLabel("Security group ID").SetText(target.SecurityGroupId)
+
+	form.AddFormItem(profileField).
+		AddFormItem(regionField).
+		AddFormItem(groupField)
+
+	form.AddButton("Compare", func() {
+		entered := service.SecurityGroupRef{
+			Region:          strings.TrimSpace(regionField.GetText()),
+			SecurityGroupId: strings.TrimSpace(groupField.GetText()),
+		}
+		_, entered.Profile = profileField.GetCurrentOption()
+		if !strings.HasPrefix(entered.SecurityGroupId, "sg-") {
+			form.SetTitle(fmt.Sprintf(" [red]%s is not a security group ID[-] ", tview.Escape(strconv.Quote(entered.SecurityGroupId))))
+			return
+		}
+		closeDialog()
+		if onSubmit != nil {
+			onSubmit(entered)
+		}
+	})
+	form.AddButton("Cancel", cancel)
+	form.SetCancelFunc(cancel)
+	form.SetFocus(2) // Start in the group ID
+
+	form.SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(tcell.ColorDefault)
+
+	closeDialog = showFormPage(pages, app, "securityGroupCompareDialog", form, 11)
+}
+
+// CreateSecurityGroupDiffView creates the side-by-side diff of the rules of two security
+// groups. Rules only the left group has are shown in red, rules only the right group has
+// in green and rules that differ in yellow; identical rules are left out when hideSame is
+// set. Rows are referenced by their position in diff.
+func CreateSecurityGroupDiffView(left, right *service.SecurityGroupRuleSet, diff []service.SecurityGroupRuleDiff, hideSame bool) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"Status", "Direction", "Protocol", "Port Range", "Source/Dest", "Left: " + left.Ref.String(), "Right: " + right.Ref.String()}
+	CreateTableHeaders(table, headers)
+
+	row := 1
+	for i, line := range diff {
+		if hideSame && line.Kind == service.RuleDiffSame {
+			continue
+		}
+		color := tcell.ColorWhite
+		switch line.Kind {
+		case service.RuleDiffLeftOnly:
+			color = tcell.ColorRed
+		case service.RuleDiffRightOnly:
+			color = tcell.ColorGreen
+		case service.RuleDiffChanged:
+			color = tcell.ColorYellow
+		}
+		spec := line.Spec()
+
+		table.SetCell(row, 0, tview.NewTableCell(line.Kind).SetTextColor(color).SetReference(strconv.Itoa(i)).SetExpansion(1))
+		table.SetCell(row, 1, tview.NewTableCell(spec.Direction).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 2, tview.NewTableCell(spec.IpProtocol).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 3, tview.NewTableCell(spec.PortRange).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 4, tview.NewTableCell(spec.Peer).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 5, tview.NewTableCell(service.DescribeRuleDecision(line.Left)).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 6, tview.NewTableCell(service.DescribeRuleDecision(line.Right)).SetTextColor(color).SetExpansion(1))
+		row++
+	}
+	if row == 1 {
+		message := "Neither group has rules."
+		if len(diff) > 0 {
+			message = "The groups have the same rules."
+		}
+		table.SetCell(1, 0, tview.NewTableCell(message).SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	}
+
+	counts := service.CountRuleDiffs(diff)
+	table.SetTitle(fmt.Sprintf("Rules of %s vs %s: %d same, %d changed, %d only left, %d only right",
+		groupLabel(left), groupLabel(right), counts[service.RuleDiffSame], counts[service.RuleDiffChanged],
+		counts[service.RuleDiffLeftOnly], counts[service.RuleDiffRightOnly])).SetBorder(true)
+	return table
+}
+
+// groupLabel names a security group by its ID and name
+func groupLabel(set *service.SecurityGroupRuleSet) string {
+	if set.Name == "" {
+		return set.Ref.SecurityGroupId
+	}
+	return fmt.Sprintf("%s (%s)", set.Ref.SecurityGroupId, set.Name)
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// CreateReachabilityView creates the page of a reachability check: the verdict above the
+// ingress rules of the destination in evaluation order. The rule that decided is shown in
+// green if it accepts and in red if it drops, rules matching part of the source range in
+// yellow and rules never evaluated in gray. Rows are referenced by their position.
+func CreateReachabilityView(reachability *service.Reachability, traffic string) (tview.Primitive, *tview.Table) {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+
+	headers := []string{"#", "Security Group", "Rule ID", "Rule", "Match", "Reason"}
+	CreateTableHeaders(table, headers)
+
+	if len(reachability.Rules) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("The destination has no ingress rules.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, rule := range reachability.Rules {
+			color := tcell.ColorWhite
+			switch {
+			case rule.Decides && rule.Policy == "accept":
+				color = tcell.ColorGreen
+			case rule.Decides:
+				color = tcell.ColorRed
+			case rule.Match == service.RuleMatchesPartly:
+				color = tcell.ColorYellow
+			case rule.Match == service.RuleNotEvaluated:
+				color = tcell.ColorGray
+			}
+			match := rule.Match
+			if rule.Decides {
+				match += " (decides)"
+			}
+
+			table.SetCell(r+1, 0, tview.NewTableCell(strconv.Itoa(r+1)).SetTextColor(color).SetReference(strconv.Itoa(r)).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(rule.SecurityGroupId).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(rule.RuleId).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(rule.Rule).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(match).SetTextColor(color).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(rule.Reason).SetTextColor(color).SetExpansion(1))
+		}
+	}
+
+	verdict := "[red]DROPPED[-]"
+	if reachability.Allowed {
+		verdict = "[green]ALLOWED[-]"
+	}
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%s  %s -> %s %s\n%s", verdict, tview.Escape(reachability.Source),
+		tview.Escape(reachability.Destination), tview.Escape(traffic), tview.Escape(reachability.Verdict))
+	for _, note := range reachability.Notes {
+		fmt.Fprintf(&summary, "\n[yellow]Note:[-] %s", tview.Escape(note))
+	}
+	summaryView := tview.NewTextView().
+		SetText(summary.String()).
+		SetDynamicColors(true).
+		SetWrap(true)
+	summaryView.SetBackgroundColor(tcell.ColorReset)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.AddItem(summaryView, 3+len(reachability.Notes), 0, false)
+	flex.AddItem(table, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf("Reachability of %s", reachability.Destination)).SetBorder(true)
+	flex.SetBackgroundColor(tcell.ColorReset)
+	return flex, table
+}
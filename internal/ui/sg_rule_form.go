@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// securityGroupRuleDirections lists the directions in the order the form offers them
+var securityGroupRuleDirections = []string{service.SecurityGroupRuleIngress, service.SecurityGroupRuleEgress}
+
+// ShowSecurityGroupRuleForm shows a form for adding or editing a security group rule, filled
+// with spec. The direction of an existing rule cannot change, so fixedDirection disables
+// it. Save validates the rule with service.ValidateSecurityGroupRule: an invalid rule keeps
+// the form open with the problem in its title, a valid one is passed to onSubmit. Cancel or
+// Esc calls onCancel.
+func ShowSecurityGroupRuleForm(pages *tview.Pages, app *tview.Application, title string, spec service.SecurityGroupRuleSpec, fixedDirection bool, onSubmit func(service.SecurityGroupRuleSpec), onCancel func()) {
+	form := tview.NewForm()
+	var closeDialog func()
+	cancel := func() {
+		closeDialog()
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+
+	directionField := tview.NewDropDown().SetLabel("Direction").
+		SetOptions(securityGroupRuleDirections, nil).
+		SetCurrentOption(max(indexOf(securityGroupRuleDirections, spec.Direction), 0))
+	directionField.SetDisabled(fixedDirection)
+	protocolField := tview.NewDropDown().SetLabel("Protocol").
+		SetOptions(service.SecurityGroupRuleProtocols, nil).
+		SetCurrentOption(max(indexOf(service.SecurityGroupRuleProtocols, spec.IpProtocol), 0))
+	portField := tview.NewInputField().SetLabel("Port range (TCP/UDP)").SetText(spec.PortRange)
+	peerField := tview.NewInputField().SetLabel("Source/Dest CIDR, group or prefix list").SetText(spec.Peer)
+	policyField := tview.NewDropDown().SetLabel("Policy").
+		SetOptions(service.SecurityGroupRulePolicies, nil).
+		SetCurrentOption(max(indexOf(service.SecurityGroupRulePolicies, spec.Policy), 0))
+	priorityField := tview.NewInputField().SetLabel("Priority (1-100)").
+		SetText(strconv.Itoa(spec.Priority)).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	descriptionField := tview.NewInputField().SetLabel("Description").SetText(spec.Description)
+
+	form.AddFormItem(directionField).
+		AddFormItem(protocolField).
+		AddFormItem(portField).
+		AddFormItem(peerField).
+		AddFormItem(policyField).
+		AddFormItem(priorityField).
+		AddFormItem(descriptionField)
+
+	form.AddButton("Preview", func() {
+		entered := service.SecurityGroupRuleSpec{
+			Peer:        strings.TrimSpace(peerField.GetText()),
+			Description: strings.TrimSpace(descriptionField.GetText()),
+		}
+		_, entered.Direction = directionField.GetCurrentOption()
+		_, entered.IpProtocol = protocolField.GetCurrentOption()
+		_, entered.Policy = policyField.GetCurrentOption()
+		entered.Priority, _ = strconv.Atoi(priorityField.GetText())
+		entered.PortRange = rulePortRange(entered, strings.TrimSpace(portField.GetText()))
+
+		if err := service.ValidateSecurityGroupRule(entered); err != nil {
+			form.SetTitle(fmt.Sprintf(" [red]%s[-] ", tview.Escape(err.Error())))
+			return
+		}
+		closeDialog()
+		if onSubmit != nil {
+			onSubmit(entered)
+		}
+	})
+	form.AddButton("Cancel", cancel)
+	form.SetCancelFunc(cancel)
+
+	form.SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(tcell.ColorDefault)
+
+	closeDialog = showFormPage(pages, app, "securityGroupRuleForm", form, 19)
+}
+
+// rulePortRange returns the port range entered for a rule: protocols without ports always
+// use service.AllPorts, and a single TCP or UDP port such as "22" stands for "22/22"
+func rulePortRange(spec service.SecurityGroupRuleSpec, entered string) string {
+	if !spec.HasPorts() {
+		return service.AllPorts
+	}
+	if entered != "" && !strings.Contains(entered, "/") {
+		return entered + "/" + entered
+	}
+	return entered
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"aliyun-tui-viewer/internal/service"
+)
+
+// SlbListenerReference identifies a listener of a load balancer: a TCP and a UDP listener
+// can share a port
+func SlbListenerReference(protocol string, port int) string {
+	return fmt.Sprintf("%s:%d", protocol, port)
+}
+
+// listenerDetailTable fills the setting table of a listener section by section
+type listenerDetailTable struct {
+	table *tview.Table
+	row   int
+}
+
+// section starts a section of settings
+func (t *listenerDetailTable) section(title string) {
+	t.table.SetCell(t.row, 0, tview.NewTableCell(title).SetTextColor(tcell.ColorYellow).SetAttributes(tcell.AttrBold).SetSelectable(false).SetExpansion(1))
+	t.table.SetCell(t.row, 1, tview.NewTableCell("").SetSelectable(false).SetExpansion(3))
+	t.row++
+}
+
+// setting adds a setting to the current section; settings that are off are gray
+func (t *listenerDetailTable) setting(name, value string) {
+	color := tcell.ColorWhite
+	if value == "off" || value == "" {
+		color = tcell.ColorGray
+	}
+	if value == "" {
+		value = "--"
+	}
+	t.table.SetCell(t.row, 0, tview.NewTableCell("  "+name).SetTextColor(color).SetReference(name).SetExpansion(1))
+	t.table.SetCell(t.row, 1, tview.NewTableCell(value).SetTextColor(color).SetExpansion(3))
+	t.row++
+}
+
+// CreateSlbListenerDetailView creates the page of the full configuration of a listener,
+// with the sections its protocol has
+func CreateSlbListenerDetailView(attributes *service.ListenerAttributes) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+	CreateTableHeaders(table, []string{"Setting", "Value"})
+
+	protocol := strings.ToUpper(attributes.Protocol)
+	web := protocol == "HTTP" || protocol == "HTTPS"
+	t := &listenerDetailTable{table: table, row: 1}
+
+	t.section("Listener")
+	t.setting("Frontend", SlbListenerReference(protocol, attributes.Port))
+	t.setting("Backend port", portOrDefault(attributes.BackendPort, "--"))
+	t.setting("Status", attributes.Status)
+	t.setting("Description", attributes.Description)
+	t.setting("Scheduler", attributes.Scheduler)
+	t.setting("Server group", vServerGroupLabel(attributes.VServerGroupId, attributes.VServerGroupName))
+	bandwidth := fmt.Sprintf("%d Mbit/s", attributes.Bandwidth)
+	if attributes.Bandwidth == service.UnlimitedBandwidth {
+		bandwidth = "unlimited"
+	}
+	t.setting("Bandwidth", bandwidth)
+
+	if web {
+		t.setting("Gzip", attributes.HTTP.Gzip)
+		t.setting("Idle timeout", seconds(attributes.HTTP.IdleTimeout))
+		t.setting("Request timeout", seconds(attributes.HTTP.RequestTimeout))
+		if protocol == "HTTP" {
+			redirect := "off"
+			if attributes.HTTP.ForwardToPort > 0 {
+				redirect = "to " + SlbListenerReference("HTTPS", attributes.HTTP.ForwardToPort)
+			}
+			t.setting("Redirect", redirect)
+		}
+	} else {
+		t.setting("Session persistence", secondsOrOff(attributes.Connection.PersistenceTimeout))
+		drain := attributes.Connection.ConnectionDrain
+		if drain == "on" {
+			drain = fmt.Sprintf("on, %s", seconds(attributes.Connection.ConnectionDrainTimeout))
+		}
+		t.setting("Connection drain", drain)
+		if protocol == "TCP" {
+			t.setting("Idle timeout", seconds(attributes.Connection.EstablishedTimeout))
+		}
+	}
+
+	if protocol == "HTTPS" {
+		t.section("TLS")
+		t.setting("Server certificate", attributes.TLS.ServerCertificateId)
+		caCertificate := attributes.TLS.CACertificateId
+		if caCertificate == "" {
+			caCertificate = "off"
+		}
+		t.setting("CA certificate", caCertificate)
+		t.setting("Cipher policy", attributes.TLS.CipherPolicy)
+		t.setting("HTTP/2", attributes.TLS.EnableHttp2)
+	}
+
+	health := attributes.HealthCheck
+	t.section("Health Check")
+	t.setting("Enabled", health.Enabled)
+	if health.Enabled == "on" {
+		switch {
+		case protocol == "UDP":
+			t.setting("Request", health.Request)
+			t.setting("Expected response", health.ExpectedResponse)
+		case web || health.Type == "http":
+			if protocol == "TCP" {
+				t.setting("Type", health.Type)
+			}
+			t.setting("Method", strings.ToUpper(health.Method))
+			domain := health.Domain
+			if domain == "" {
+				domain = "backend server IP"
+			}
+			t.setting("Domain", domain)
+			t.setting("URI", health.URI)
+			t.setting("Healthy HTTP codes", strings.ReplaceAll(health.HttpCodes, ",", ", "))
+		default:
+			t.setting("Type", health.Type)
+		}
+		t.setting("Port", portOrDefault(health.ConnectPort, "backend port"))
+		t.setting("Interval", seconds(health.Interval))
+		t.setting("Timeout", seconds(health.Timeout))
+		t.setting("Healthy threshold", strconv.Itoa(health.HealthyThreshold))
+		t.setting("Unhealthy threshold", strconv.Itoa(health.UnhealthyThreshold))
+	}
+
+	if web {
+		sticky := attributes.StickySession
+		t.section("Sticky Session")
+		t.setting("Enabled", sticky.Enabled)
+		if sticky.Enabled == "on" {
+			switch sticky.Type {
+			case "insert":
+				t.setting("Type", "insert cookie")
+				t.setting("Cookie timeout", seconds(sticky.CookieTimeout))
+			case "server":
+				t.setting("Type", "rewrite cookie")
+				t.setting("Cookie", sticky.Cookie)
+			default:
+				t.setting("Type", sticky.Type)
+			}
+		}
+
+		headers := attributes.XForwardedFor
+		t.section("X-Forwarded-For Headers")
+		t.setting("X-Forwarded-For", headers.XForwardedFor)
+		t.setting("X-Forwarded-Proto", headers.Proto)
+		t.setting("X-Forwarded-Port", headers.SLBPort)
+		t.setting("X-Forwarded-Client-srcport", headers.ClientSrcPort)
+		t.setting("SLB-IP", headers.SLBIP)
+		t.setting("SLB-ID", headers.SLBID)
+	}
+
+	t.section("Access Control")
+	t.setting("Enabled", attributes.Acl.Status)
+	if attributes.Acl.Status == "on" {
+		aclType := attributes.Acl.Type
+		switch aclType {
+		case "white":
+			aclType = "whitelist, only listed addresses are allowed"
+		case "black":
+			aclType = "blacklist, listed addresses are refused"
+		}
+		t.setting("Type", aclType)
+		t.setting("Access control lists", strings.Join(attributes.Acl.Ids, ", "))
+	}
+
+	if web {
+		t.section(fmt.Sprintf("Forwarding Rules (%d)", len(attributes.Rules)))
+		for _, rule := range attributes.Rules {
+			name := rule.RuleId
+			if rule.RuleName != "" {
+				name = fmt.Sprintf("%s (%s)", rule.RuleName, rule.RuleId)
+			}
+			t.setting(name, fmt.Sprintf("%s%s -> %s", rule.Domain, rule.Url, rule.VServerGroupId))
+		}
+	}
+	if protocol == "HTTPS" {
+		t.section(fmt.Sprintf("Domain Extensions (%d)", len(attributes.Domains)))
+		for _, extension := range attributes.Domains {
+			t.setting(extension.Domain, fmt.Sprintf("certificate %s (%s)", extension.ServerCertificateId, extension.DomainExtensionId))
+		}
+	}
+
+	table.SetTitle(fmt.Sprintf("Listener %s of SLB: %s", SlbListenerReference(protocol, attributes.Port), attributes.LoadBalancerId)).SetBorder(true)
+	return table
+}
+
+// vServerGroupLabel names a virtual server group by its name and ID
+func vServerGroupLabel(id, name string) string {
+	if id == "" || name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
+
+// portOrDefault formats a port, or what is used when it is not set
+func portOrDefault(port int, unset string) string {
+	if port <= 0 {
+		return unset
+	}
+	return strconv.Itoa(port)
+}
+
+// seconds formats a duration in seconds
+func seconds(n int) string {
+	return fmt.Sprintf("%ds", n)
+}
+
+// secondsOrOff formats a duration in seconds that turns a setting off when zero
+func secondsOrOff(n int) string {
+	if n <= 0 {
+		return "off"
+	}
+	return seconds(n)
+}
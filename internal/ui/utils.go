@@ -3,19 +3,30 @@ package ui
 import (
 	"aliyun-tui-viewer/internal/config"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/rivo/tview"
 )
 
-// CopyToClipboard copies the given data as JSON to the system clipboard
+// CopyToClipboard copies the given data as JSON to the system clipboard. A string, e.g. a
+// URL, is copied as it is.
 func CopyToClipboard(data interface{}) error {
+	if text, ok := data.(string); ok {
+		if err := clipboard.WriteAll(text); err != nil {
+			return fmt.Errorf("failed to copy to clipboard: %w", err)
+		}
+		return nil
+	}
+
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data to JSON: %w", err)
@@ -220,3 +231,34 @@ func OpenInPager(data interface{}, app *tview.Application) error {
 	os.Remove(tmpFile)
 	return nil
 }
+
+// PipeToPager streams r into the standard input of the configured pager, suspending the
+// application until the pager exits. Quitting the pager early stops reading r.
+func PipeToPager(r io.Reader, app *tview.Application) error {
+	pagerCmd, err := config.GetPager()
+	if err != nil {
+		return fmt.Errorf("failed to get pager command: %w", err)
+	}
+	cmdParts := strings.Fields(pagerCmd)
+	if len(cmdParts) == 0 {
+		return fmt.Errorf("pager command is empty")
+	}
+
+	var runErr error
+	app.Suspend(func() {
+		cmd := exec.Command(cmdParts[0], cmdParts[1:]...)
+		cmd.Stdin = r
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		runErr = cmd.Run()
+	})
+	if runErr != nil && !isBrokenPipe(runErr) {
+		return fmt.Errorf("running pager %s: %w", cmdParts[0], runErr)
+	}
+	return nil
+}
+
+// isBrokenPipe reports whether err comes from writing to a pager that has already quit
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
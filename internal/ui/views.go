@@ -3,6 +3,7 @@ package ui
 import (
 	"aliyun-tui-viewer/internal/service"
 	"fmt"
+	"strings"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
@@ -103,56 +104,35 @@ func CreateSecurityGroupDetailView(securityGroup interface{}) *tview.Flex {
 	return CreateDetailViewWithInstructions(detailView)
 }
 
-// CreateSecurityGroupRulesView creates security group rules view
+// CreateSecurityGroupRulesView creates security group rules view. The reference of each row
+// is the rule's ID.
 func CreateSecurityGroupRulesView(rulesResponse *ecs.DescribeSecurityGroupAttributeResponse) *tview.Table {
 	table := tview.NewTable().
 		SetBorders(true).
 		SetSelectable(true, false)
 	table = SetupTableWithFixedWidth(table)
 
-	// 合并入站和出站规则
-	allRules := []interface{}{}
-
-	// 添加入站规则
-	for _, rule := range rulesResponse.Permissions.Permission {
-		ruleData := map[string]interface{}{
-			"Direction":     "Ingress",
-			"IpProtocol":    rule.IpProtocol,
-			"PortRange":     rule.PortRange,
-			"SourceCidrIp":  rule.SourceCidrIp,
-			"SourceGroupId": rule.SourceGroupId,
-			"Policy":        rule.Policy,
-			"Priority":      rule.Priority,
-			"NicType":       rule.NicType,
-			"Description":   rule.Description,
-		}
-		allRules = append(allRules, ruleData)
-	}
-
 	headers := []string{"Direction", "Protocol", "Port Range", "Source/Dest", "Policy", "Priority", "Description"}
 	CreateTableHeaders(table, headers)
 
-	if len(allRules) == 0 {
+	rules := rulesResponse.Permissions.Permission
+	if len(rules) == 0 {
 		table.SetCell(1, 0, tview.NewTableCell("No security group rules found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
 	} else {
-		for r, ruleInterface := range allRules {
-			rule := ruleInterface.(map[string]interface{})
-
-			// 确定源/目标
-			sourceDest := ""
-			if rule["SourceCidrIp"] != nil && rule["SourceCidrIp"].(string) != "" {
-				sourceDest = rule["SourceCidrIp"].(string)
-			} else if rule["SourceGroupId"] != nil && rule["SourceGroupId"].(string) != "" {
-				sourceDest = rule["SourceGroupId"].(string)
+		for r, rule := range rules {
+			spec := service.SecurityGroupRuleSpecOf(rule)
+			direction := "Ingress"
+			if spec.Direction == service.SecurityGroupRuleEgress {
+				direction = "Egress"
 			}
 
-			table.SetCell(r+1, 0, tview.NewTableCell(rule["Direction"].(string)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 1, tview.NewTableCell(rule["IpProtocol"].(string)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 2, tview.NewTableCell(rule["PortRange"].(string)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 3, tview.NewTableCell(sourceDest).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 4, tview.NewTableCell(rule["Policy"].(string)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 5, tview.NewTableCell(fmt.Sprintf("%v", rule["Priority"])).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 6, tview.NewTableCell(rule["Description"].(string)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 0, tview.NewTableCell(direction).SetTextColor(tcell.ColorWhite).SetReference(rule.SecurityGroupRuleId).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(rule.IpProtocol).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(rule.PortRange).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(spec.Peer).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(rule.Policy).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(rule.Priority).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 6, tview.NewTableCell(rule.Description).SetTextColor(tcell.ColorWhite).SetExpansion(1))
 		}
 	}
 	table.SetTitle(fmt.Sprintf("Security Group Rules: %s", rulesResponse.SecurityGroupId)).SetBorder(true)
@@ -278,6 +258,37 @@ func CreateDnsRecordsListView(records []alidns.Record, domainName string) *tview
 	return table
 }
 
+// CreateDnsZoneImportView creates the preview of the changes a zone import makes
+func CreateDnsZoneImportView(diff *service.ZoneDiff) *tview.Table {
+	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+	headers := []string{"Action", "Record ID", "RR", "Type", "Value", "TTL", "Line"}
+	CreateTableHeaders(table, headers)
+
+	actionColors := map[string]tcell.Color{
+		service.ZoneChangeAdd:    tcell.ColorGreen,
+		service.ZoneChangeUpdate: tcell.ColorYellow,
+		service.ZoneChangeDelete: tcell.ColorRed,
+	}
+	if len(diff.Changes) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No changes, the records already match the zone file.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	} else {
+		for r, change := range diff.Changes {
+			spec := change.Record()
+			value, ttl := change.Describe()
+			table.SetCell(r+1, 0, tview.NewTableCell(change.Action).SetTextColor(actionColors[change.Action]).SetReference(change.RecordId).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(change.RecordId).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 2, tview.NewTableCell(spec.RR).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 3, tview.NewTableCell(spec.Type).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 4, tview.NewTableCell(value).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 5, tview.NewTableCell(ttl).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+			table.SetCell(r+1, 6, tview.NewTableCell(spec.Line).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+		}
+	}
+	table.SetTitle(fmt.Sprintf("Import into %s: %d changes, %d records unchanged", diff.Domain, len(diff.Changes), diff.Unchanged)).SetBorder(true)
+	return table
+}
+
 // CreateSlbListView creates SLB instances list view
 func CreateSlbListView(slbs []slb.LoadBalancer) *tview.Table {
 	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
@@ -370,8 +381,8 @@ func CreateSlbDetailedListenersView(listeners []service.ListenerDetail, loadBala
 				vServerGroupStr = listener.VServerGroupId
 			}
 
-			table.SetCell(r+1, 0, tview.NewTableCell(listener.Protocol).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%d", listener.Port)).SetTextColor(tcell.ColorWhite).SetReference(fmt.Sprintf("%d", listener.Port)).SetExpansion(1))
+			table.SetCell(r+1, 0, tview.NewTableCell(listener.Protocol).SetTextColor(tcell.ColorWhite).SetReference(SlbListenerReference(listener.Protocol, listener.Port)).SetExpansion(1))
+			table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%d", listener.Port)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
 			table.SetCell(r+1, 2, tview.NewTableCell(backendPortStr).SetTextColor(tcell.ColorWhite).SetExpansion(1))
 			table.SetCell(r+1, 3, tview.NewTableCell(listener.Status).SetTextColor(tcell.ColorWhite).SetExpansion(1))
 			table.SetCell(r+1, 4, tview.NewTableCell(listener.HealthCheck).SetTextColor(tcell.ColorWhite).SetExpansion(1))
@@ -531,23 +542,35 @@ func CreateOssObjectListView(objects []oss.ObjectProperties, bucketName string)
 }
 
 // CreateOssObjectPaginatedView creates OSS objects list view with pagination controls
-func CreateOssObjectPaginatedView(objects []oss.ObjectProperties, bucketName string, currentPage int, hasNext, hasPrev bool) *tview.Flex {
+func CreateOssObjectPaginatedView(folders []string, objects []oss.ObjectProperties, bucketName, prefix string, currentPage int, hasNext, hasPrev bool) *tview.Flex {
 	// Create the table
 	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
 	table = SetupTableWithFixedWidth(table)
 	headers := []string{"Object Key", "Size (Bytes)", "Last Modified", "Storage Class", "ETag"}
 	CreateTableHeaders(table, headers)
 
-	if len(objects) == 0 {
-		table.SetCell(1, 0, tview.NewTableCell("No objects found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
-	} else {
-		for r, object := range objects {
-			table.SetCell(r+1, 0, tview.NewTableCell(object.Key).SetTextColor(tcell.ColorWhite).SetReference(object.Key).SetExpansion(1))
-			table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%d", object.Size)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 2, tview.NewTableCell(object.LastModified.Format("2006-01-02 15:04:05")).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 3, tview.NewTableCell(object.StorageClass).SetTextColor(tcell.ColorWhite).SetExpansion(1))
-			table.SetCell(r+1, 4, tview.NewTableCell(object.ETag).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+	// Folders come first; their references end with the delimiter, unlike object keys
+	r := 1
+	for _, folder := range folders {
+		table.SetCell(r, 0, tview.NewTableCell(strings.TrimPrefix(folder, prefix)).SetTextColor(tcell.ColorDodgerBlue).SetReference(folder).SetExpansion(1))
+		for col := 1; col < len(headers); col++ {
+			table.SetCell(r, col, tview.NewTableCell("").SetExpansion(1))
+		}
+		r++
+	}
+	for _, object := range objects {
+		if object.Key == prefix {
+			continue // The placeholder object of the folder itself
 		}
+		table.SetCell(r, 0, tview.NewTableCell(strings.TrimPrefix(object.Key, prefix)).SetTextColor(tcell.ColorWhite).SetReference(object.Key).SetExpansion(1))
+		table.SetCell(r, 1, tview.NewTableCell(fmt.Sprintf("%d", object.Size)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+		table.SetCell(r, 2, tview.NewTableCell(object.LastModified.Format("2006-01-02 15:04:05")).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(object.StorageClass).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(object.ETag).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+		r++
+	}
+	if r == 1 {
+		table.SetCell(1, 0, tview.NewTableCell("No objects found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
 	}
 
 	// Create pagination info
@@ -573,12 +596,154 @@ func CreateOssObjectPaginatedView(objects []oss.ObjectProperties, bucketName str
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
 	flex.AddItem(table, 0, 1, true)
 	flex.AddItem(statusBar, 1, 0, false)
-	flex.SetTitle(fmt.Sprintf("Objects in %s", bucketName)).SetBorder(true)
+	flex.SetTitle(fmt.Sprintf("Objects in %s", OssBreadcrumb(bucketName, prefix))).SetBorder(true)
 	flex.SetBackgroundColor(tcell.ColorReset)
 
 	return flex
 }
 
+// CreateOssSearchResultsView creates the table the matches of an object search are added
+// to with AddOssSearchResult as they are found
+func CreateOssSearchResultsView() *tview.Table {
+	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+	CreateTableHeaders(table, []string{"Object Key", "Size", "Last Modified", "Storage Class"})
+	table.SetBorder(true)
+	return table
+}
+
+// AddOssSearchResult adds an object found by a search to the results table
+func AddOssSearchResult(table *tview.Table, object oss.ObjectProperties) {
+	r := table.GetRowCount()
+	table.SetCell(r, 0, tview.NewTableCell(object.Key).SetTextColor(tcell.ColorWhite).SetReference(object.Key).SetExpansion(1))
+	table.SetCell(r, 1, tview.NewTableCell(FormatBytes(object.Size)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+	table.SetCell(r, 2, tview.NewTableCell(object.LastModified.Format("2006-01-02 15:04:05")).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+	table.SetCell(r, 3, tview.NewTableCell(object.StorageClass).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+}
+
+// CreateOssObjectVersionsView creates the table of the versions and delete markers of an
+// object, newest first. The current version is green and delete markers are red.
+func CreateOssObjectVersionsView(bucketName, objectKey string, versions []service.ObjectVersion) *tview.Table {
+	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+	headers := []string{"Version ID", "Current", "Type", "Size", "Last Modified", "Storage Class"}
+	CreateTableHeaders(table, headers)
+
+	markers := 0
+	if len(versions) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No versions found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	}
+	for i, version := range versions {
+		r := i + 1
+		color, kind, size, current := tcell.ColorWhite, "Version", FormatBytes(version.Size), ""
+		if version.IsDeleteMarker {
+			color, kind, size = tcell.ColorRed, "Delete marker", "-"
+			markers++
+		}
+		if version.IsLatest {
+			current = "Yes"
+			if !version.IsDeleteMarker {
+				color = tcell.ColorGreen
+			}
+		}
+		table.SetCell(r, 0, tview.NewTableCell(version.VersionId).SetTextColor(color).SetReference(version.VersionId).SetExpansion(1))
+		table.SetCell(r, 1, tview.NewTableCell(current).SetTextColor(color).SetExpansion(1))
+		table.SetCell(r, 2, tview.NewTableCell(kind).SetTextColor(color).SetExpansion(1))
+		table.SetCell(r, 3, tview.NewTableCell(size).SetTextColor(color).SetAlign(tview.AlignRight).SetExpansion(1))
+		table.SetCell(r, 4, tview.NewTableCell(version.LastModified.Format("2006-01-02 15:04:05")).SetTextColor(color).SetExpansion(1))
+		table.SetCell(r, 5, tview.NewTableCell(version.StorageClass).SetTextColor(color).SetExpansion(1))
+	}
+
+	title := fmt.Sprintf("Versions of oss://%s/%s (%d versions, %d delete markers)", bucketName, objectKey, len(versions)-markers, markers)
+	if len(versions) == 1 && versions[0].Unversioned() {
+		title = fmt.Sprintf("Versions of oss://%s/%s (written while versioning was off, no previous versions)", bucketName, objectKey)
+	}
+	table.SetTitle(title)
+	table.SetBorder(true)
+	return table
+}
+
+// CreateOssUsageView creates the table of a bucket usage summary, filled in by
+// RenderOssUsage as the objects are counted
+func CreateOssUsageView() *tview.Table {
+	table := tview.NewTable().SetBorders(true).SetSelectable(true, false)
+	table = SetupTableWithFixedWidth(table)
+	table.SetBorder(true)
+	return table
+}
+
+// RenderOssUsage shows a usage summary: a row per folder, largest first, with its size in
+// total and by storage class. The title reports the totals followed by status, e.g.
+// " (counting...)".
+func RenderOssUsage(table *tview.Table, bucketName string, usage *service.BucketUsage, status string) {
+	classes := usage.StorageClasses()
+	headers := append([]string{"Folder", "Objects", "Size", "Share"}, classes...)
+	table.Clear()
+	CreateTableHeaders(table, headers)
+
+	folders := usage.Folders()
+	if len(folders) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No objects found.").SetSelectable(false).SetExpansion(len(headers)).SetAlign(tview.AlignCenter))
+	}
+	for r, folder := range folders {
+		name, color := strings.TrimPrefix(folder.Prefix, usage.Prefix), tcell.ColorDodgerBlue
+		if folder.Prefix == usage.Prefix {
+			name, color = "(objects in this folder)", tcell.ColorWhite
+		}
+		share := 0.0
+		if usage.Total.Bytes > 0 {
+			share = float64(folder.Bytes) * 100 / float64(usage.Total.Bytes)
+		}
+		table.SetCell(r+1, 0, tview.NewTableCell(name).SetTextColor(color).SetReference(folder.Prefix).SetExpansion(1))
+		table.SetCell(r+1, 1, tview.NewTableCell(fmt.Sprintf("%d", folder.Objects)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+		table.SetCell(r+1, 2, tview.NewTableCell(FormatBytes(folder.Bytes)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+		table.SetCell(r+1, 3, tview.NewTableCell(fmt.Sprintf("%.1f%%", share)).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+		for c, class := range classes {
+			text := ""
+			if classUsage, ok := folder.ByClass[class]; ok {
+				text = FormatBytes(classUsage.Bytes)
+			}
+			table.SetCell(r+1, 4+c, tview.NewTableCell(text).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignRight).SetExpansion(1))
+		}
+	}
+
+	if row, _ := table.GetSelection(); len(folders) > 0 && (row < 1 || row > len(folders)) {
+		table.Select(1, 0)
+	}
+
+	var byClass []string
+	for _, class := range classes {
+		byClass = append(byClass, fmt.Sprintf("%s %s", class, FormatBytes(usage.Total.ByClass[class].Bytes)))
+	}
+	title := fmt.Sprintf("Usage of %s: %d objects, %s", OssBreadcrumb(bucketName, usage.Prefix), usage.Total.Objects, FormatBytes(usage.Total.Bytes))
+	if len(byClass) > 0 {
+		title += " (" + strings.Join(byClass, ", ") + ")"
+	}
+	table.SetTitle(title + status)
+}
+
+// OssBreadcrumb shows where a prefix is in its bucket, e.g. "my-bucket > logs > 2024"
+func OssBreadcrumb(bucketName, prefix string) string {
+	parts := []string{bucketName}
+	for _, part := range strings.Split(strings.TrimSuffix(prefix, "/"), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+// CreateOssObjectPreviewView creates the view showing the beginning of a text object
+func CreateOssObjectPreviewView(title, content string) *tview.TextView {
+	textView := tview.NewTextView().
+		SetText(content).
+		SetScrollable(true).
+		SetWrap(true)
+	textView.SetBackgroundColor(tcell.ColorReset)
+	textView.SetTitle(title).SetBorder(true)
+	return textView
+}
+
 // CreateRdsListView creates RDS instances list view
 func CreateRdsListView(instances []rds.DBInstance) *tview.Table {
 	table := tview.NewTable().